@@ -0,0 +1,259 @@
+package katapultpro
+
+import (
+	"context"
+	"io"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/connections"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/nodes"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/photos"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/sections"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/traces"
+)
+
+// The interfaces below describe every typed client and scope. Accept them in your code and use
+// NewAPI(client) in production and the katapultmock package in tests. Leaf clients and scopes
+// (NodeAPI, SectionAPI, ElementsAPI, AnchorsAPI, TracesAPI, JobsAPI, JobSectionAPI) are satisfied
+// directly by the concrete types; interfaces with drill-down methods (Node, Photo, Elements, ...)
+// return interfaces, so NewAPI adapts the concrete clients to them.
+
+// ClientAPI is the typed API surface of *Client. Use NewAPI to get one.
+type ClientAPI interface {
+	Jobs() JobsAPI
+	Job(jobID string) JobAPI
+	ListJobs(ctx context.Context, opts *ListJobsOptions) ([]Job, error)
+	GetJob(ctx context.Context, jobID string, opts *GetJobOptions) (*Job, error)
+	CreateJob(ctx context.Context, req *CreateJobRequest) (*Job, error)
+	UpdateJob(ctx context.Context, jobID string, req *UpdateJobRequest) (*Job, error)
+	GetJobStatus(ctx context.Context, jobID string) (JobStatus, error)
+	UpdateJobStatus(ctx context.Context, jobID string, status JobStatus) error
+}
+
+// JobsAPI is implemented by *jobs.Client.
+type JobsAPI interface {
+	List(ctx context.Context, opts *ListJobsOptions) ([]Job, error)
+	Get(ctx context.Context, jobID string, opts *GetJobOptions) (*Job, error)
+	GetInto(ctx context.Context, jobID string, opts *GetJobOptions, out any) error
+	Create(ctx context.Context, req *CreateJobRequest) (*Job, error)
+	Update(ctx context.Context, jobID string, req *UpdateJobRequest) (*Job, error)
+	GetStatus(ctx context.Context, jobID string) (JobStatus, error)
+	UpdateStatus(ctx context.Context, jobID string, status JobStatus) error
+}
+
+// JobAPI is the typed API surface of *JobScope.
+type JobAPI interface {
+	JobID() string
+	Get(ctx context.Context, opts *GetJobOptions) (*Job, error)
+	Update(ctx context.Context, req *UpdateJobRequest) (*Job, error)
+	Status(ctx context.Context) (JobStatus, error)
+	SetStatus(ctx context.Context, status JobStatus) error
+	Nodes() NodesAPI
+	Connections() ConnectionsAPI
+	Photos() PhotosAPI
+	Traces() TracesAPI
+	Sections() JobSectionsAPI
+}
+
+// NodesAPI is the typed API surface of *nodes.Client.
+type NodesAPI interface {
+	Node(nodeID string) NodeAPI
+	List(ctx context.Context) ([]Node, error)
+	Get(ctx context.Context, nodeID string) (*Node, error)
+	Create(ctx context.Context, req *CreateNodeRequest) (*Node, error)
+	Update(ctx context.Context, nodeID string, req *UpdateNodeRequest, opts *UpdateNodeOptions) (*Node, error)
+	UploadPhoto(ctx context.Context, nodeID string, imageData io.Reader, opts *UploadNodePhotoOptions) (*Photo, error)
+	Delete(ctx context.Context, nodeID string) error
+}
+
+// NodeAPI is implemented by *nodes.NodeScope.
+type NodeAPI interface {
+	NodeID() string
+	Get(ctx context.Context) (*Node, error)
+	Update(ctx context.Context, req *UpdateNodeRequest, opts *UpdateNodeOptions) (*Node, error)
+	UploadPhoto(ctx context.Context, imageData io.Reader, opts *UploadNodePhotoOptions) (*Photo, error)
+	Delete(ctx context.Context) error
+}
+
+// ConnectionsAPI is the typed API surface of *connections.Client.
+type ConnectionsAPI interface {
+	Connection(connectionID string) ConnectionAPI
+	Sections(connectionID string) SectionsAPI
+	List(ctx context.Context) ([]Connection, error)
+	Get(ctx context.Context, connectionID string) (*Connection, error)
+	Create(ctx context.Context, req *CreateConnectionRequest) (*Connection, error)
+	Update(ctx context.Context, connectionID string, req *UpdateConnectionRequest, opts *UpdateConnectionOptions) (*Connection, error)
+	Delete(ctx context.Context, connectionID string) error
+}
+
+// ConnectionAPI is the typed API surface of *connections.ConnectionScope.
+type ConnectionAPI interface {
+	ConnectionID() string
+	Get(ctx context.Context) (*Connection, error)
+	Update(ctx context.Context, req *UpdateConnectionRequest, opts *UpdateConnectionOptions) (*Connection, error)
+	Delete(ctx context.Context) error
+	Sections() SectionsAPI
+}
+
+// SectionsAPI is the typed API surface of the connection-scoped *sections.Client.
+type SectionsAPI interface {
+	Section(sectionKey string) SectionAPI
+	List(ctx context.Context) ([]Section, error)
+	Get(ctx context.Context, sectionKey string) (*Section, error)
+	Create(ctx context.Context, req *CreateSectionRequest) (*Section, error)
+	Update(ctx context.Context, sectionKey string, req *UpdateSectionRequest, opts *UpdateSectionOptions) (*Section, error)
+	UploadPhoto(ctx context.Context, sectionID string, imageData io.Reader, opts *UploadSectionPhotoOptions) (*Photo, error)
+	Delete(ctx context.Context, sectionKey string) error
+}
+
+// SectionAPI is implemented by *sections.SectionScope.
+type SectionAPI interface {
+	SectionKey() string
+	Get(ctx context.Context) (*Section, error)
+	Update(ctx context.Context, req *UpdateSectionRequest, opts *UpdateSectionOptions) (*Section, error)
+	UploadPhoto(ctx context.Context, imageData io.Reader, opts *UploadSectionPhotoOptions) (*Photo, error)
+	Delete(ctx context.Context) error
+}
+
+// JobSectionsAPI is the typed API surface of the job-scoped *sections.JobClient.
+type JobSectionsAPI interface {
+	Section(sectionID string) JobSectionAPI
+	List(ctx context.Context) ([]Section, error)
+	Get(ctx context.Context, sectionID string) (*Section, error)
+}
+
+// JobSectionAPI is implemented by *sections.JobSectionScope.
+type JobSectionAPI interface {
+	SectionID() string
+	Get(ctx context.Context) (*Section, error)
+}
+
+// PhotosAPI is the typed API surface of *photos.Client.
+type PhotosAPI interface {
+	Photo(photoID string) PhotoAPI
+	List(ctx context.Context) ([]Photo, error)
+	Get(ctx context.Context, photoID string) (*Photo, error)
+	Upload(ctx context.Context, imageData io.Reader) (*Photo, error)
+	UploadWithOptions(ctx context.Context, imageData io.Reader, opts *PhotoUploadOptions) (*Photo, error)
+	Associate(ctx context.Context, photoID string, req *AssociatePhotoRequest) error
+	ListElements(ctx context.Context, photoID string) ([]PhotoElement, error)
+	ListAllElements(ctx context.Context, photoIDs []string, concurrency int) (map[string][]PhotoElement, error)
+	GetElement(ctx context.Context, photoID, elementID string) (*PhotoElement, error)
+	CreateElement(ctx context.Context, photoID string, req *CreatePhotoElementRequest) (*PhotoElement, error)
+	UpdateElement(ctx context.Context, photoID, elementID string, req *UpdatePhotoElementRequest, opts *UpdatePhotoElementOptions) (*PhotoElement, error)
+	DeleteElement(ctx context.Context, photoID, elementID string) error
+	ListCalibrationAnchors(ctx context.Context, photoID string) ([]PhotoCalibrationAnchor, error)
+	GetCalibrationAnchor(ctx context.Context, photoID, anchorID string) (*PhotoCalibrationAnchor, error)
+	CreateCalibrationAnchor(ctx context.Context, photoID string, req *CreatePhotoCalibrationAnchorRequest) (*PhotoCalibrationAnchor, error)
+	UpdateCalibrationAnchor(ctx context.Context, photoID, anchorID string, req *UpdatePhotoCalibrationAnchorRequest, opts *UpdatePhotoCalibrationAnchorOptions) (*PhotoCalibrationAnchor, error)
+	DeleteCalibrationAnchor(ctx context.Context, photoID, anchorID string) error
+}
+
+// PhotoAPI is the typed API surface of *photos.PhotoScope.
+type PhotoAPI interface {
+	PhotoID() string
+	Get(ctx context.Context) (*Photo, error)
+	Associate(ctx context.Context, req *AssociatePhotoRequest) error
+	URL(ctx context.Context, size PhotoSize) (string, error)
+	Download(ctx context.Context, size PhotoSize, w io.Writer) (int64, error)
+	OpenImage(ctx context.Context, size PhotoSize, offset int64) (*PhotoImage, error)
+	Elements() ElementsAPI
+	Anchors() AnchorsAPI
+}
+
+// ElementsAPI is implemented by *photos.ElementsClient.
+type ElementsAPI interface {
+	List(ctx context.Context) ([]PhotoElement, error)
+	Get(ctx context.Context, elementID string) (*PhotoElement, error)
+	Create(ctx context.Context, req *CreatePhotoElementRequest) (*PhotoElement, error)
+	Update(ctx context.Context, elementID string, req *UpdatePhotoElementRequest, opts *UpdatePhotoElementOptions) (*PhotoElement, error)
+	Delete(ctx context.Context, elementID string) error
+}
+
+// AnchorsAPI is implemented by *photos.AnchorsClient.
+type AnchorsAPI interface {
+	List(ctx context.Context) ([]PhotoCalibrationAnchor, error)
+	Get(ctx context.Context, anchorID string) (*PhotoCalibrationAnchor, error)
+	Create(ctx context.Context, req *CreatePhotoCalibrationAnchorRequest) (*PhotoCalibrationAnchor, error)
+	Update(ctx context.Context, anchorID string, req *UpdatePhotoCalibrationAnchorRequest, opts *UpdatePhotoCalibrationAnchorOptions) (*PhotoCalibrationAnchor, error)
+	Delete(ctx context.Context, anchorID string) error
+}
+
+// TracesAPI is implemented by *traces.Client.
+type TracesAPI interface {
+	List(ctx context.Context) ([]Trace, error)
+	Get(ctx context.Context, traceID string) (*Trace, error)
+	Create(ctx context.Context, req *CreateTraceRequest) (*Trace, error)
+	Update(ctx context.Context, traceID string, req *UpdateTraceRequest, opts *UpdateTraceOptions) (*Trace, error)
+	Delete(ctx context.Context, traceID string) error
+}
+
+// Ensure the concrete leaf types implement their interfaces at compile time.
+var (
+	_ JobsAPI       = (*jobs.Client)(nil)
+	_ NodeAPI       = (*nodes.NodeScope)(nil)
+	_ SectionAPI    = (*sections.SectionScope)(nil)
+	_ JobSectionAPI = (*sections.JobSectionScope)(nil)
+	_ ElementsAPI   = (*photos.ElementsClient)(nil)
+	_ AnchorsAPI    = (*photos.AnchorsClient)(nil)
+	_ TracesAPI     = (*traces.Client)(nil)
+)
+
+// NewAPI returns c as a ClientAPI. Every method calls straight through to the concrete client.
+func NewAPI(c *Client) ClientAPI { return clientAPI{c} }
+
+// API returns the job scope as a JobAPI.
+func (s *JobScope) API() JobAPI { return jobAPI{s} }
+
+// The adapters embed the concrete types, so every leaf method is promoted unchanged; they only
+// override drill-down methods to return interfaces.
+
+type clientAPI struct{ *Client }
+
+func (c clientAPI) Jobs() JobsAPI           { return c.Client.Jobs() }
+func (c clientAPI) Job(jobID string) JobAPI { return jobAPI{c.Client.Job(jobID)} }
+
+type jobAPI struct{ *JobScope }
+
+func (s jobAPI) Nodes() NodesAPI             { return nodesAPI{s.JobScope.Nodes()} }
+func (s jobAPI) Connections() ConnectionsAPI { return connectionsAPI{s.JobScope.Connections()} }
+func (s jobAPI) Photos() PhotosAPI           { return photosAPI{s.JobScope.Photos()} }
+func (s jobAPI) Traces() TracesAPI           { return s.JobScope.Traces() }
+func (s jobAPI) Sections() JobSectionsAPI    { return jobSectionsAPI{s.JobScope.Sections()} }
+
+type nodesAPI struct{ *nodes.Client }
+
+func (c nodesAPI) Node(nodeID string) NodeAPI { return c.Client.Node(nodeID) }
+
+type connectionsAPI struct{ *connections.Client }
+
+func (c connectionsAPI) Connection(connectionID string) ConnectionAPI {
+	return connectionAPI{c.Client.Connection(connectionID)}
+}
+
+func (c connectionsAPI) Sections(connectionID string) SectionsAPI {
+	return sectionsAPI{c.Client.Sections(connectionID)}
+}
+
+type connectionAPI struct{ *connections.ConnectionScope }
+
+func (s connectionAPI) Sections() SectionsAPI { return sectionsAPI{s.ConnectionScope.Sections()} }
+
+type sectionsAPI struct{ *sections.Client }
+
+func (c sectionsAPI) Section(sectionKey string) SectionAPI { return c.Client.Section(sectionKey) }
+
+type jobSectionsAPI struct{ *sections.JobClient }
+
+func (c jobSectionsAPI) Section(sectionID string) JobSectionAPI {
+	return c.JobClient.Section(sectionID)
+}
+
+type photosAPI struct{ *photos.Client }
+
+func (c photosAPI) Photo(photoID string) PhotoAPI { return photoAPI{c.Client.Photo(photoID)} }
+
+type photoAPI struct{ *photos.PhotoScope }
+
+func (s photoAPI) Elements() ElementsAPI { return s.PhotoScope.Elements() }
+func (s photoAPI) Anchors() AnchorsAPI   { return s.PhotoScope.Anchors() }
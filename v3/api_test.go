@@ -0,0 +1,63 @@
+package katapultpro_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+func TestNewAPI_DrillDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/jobs/j1/photos/p1/photo_elements" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":[{"id":"e1"}],"meta":{"token_count":9999,"last_refill_time":0}}`))
+	}))
+	defer srv.Close()
+
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL))
+	var api katapultpro.ClientAPI = katapultpro.NewAPI(client)
+	els, err := api.Job("j1").Photos().Photo("p1").Elements().List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(els) != 1 || els[0].ID != "e1" {
+		t.Errorf("got elements %+v", els)
+	}
+}
+
+// fakeDoer answers every request with data and records the path.
+type fakeDoer struct {
+	paths []string
+	data  string
+}
+
+func (d *fakeDoer) Do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	d.paths = append(d.paths, path)
+	return json.Unmarshal([]byte(d.data), out)
+}
+
+func (d *fakeDoer) DoWithBody(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	return d.Do(ctx, method, path, query, nil, out)
+}
+
+func TestNewJobScope_CustomDoer(t *testing.T) {
+	var do katapultpro.Doer = &fakeDoer{data: `{"status":"archived"}`}
+	status, err := katapultpro.NewJobScope(do, "j1").Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != katapultpro.JobStatusArchived {
+		t.Errorf("got status %q", status)
+	}
+	if p := do.(*fakeDoer).paths; len(p) != 1 || p[0] != "v3/jobs/j1/status" {
+		t.Errorf("got paths %v", p)
+	}
+}
@@ -0,0 +1,376 @@
+// Package attachments builds per-pole attachment height reports for make-ready sheets.
+//
+// For each node, and each connection section for midspan heights, the report joins the wire
+// measurements in the photofirst_data of its photos, the photo elements that belong to a trace,
+// and the trace records (company, cable type, label), and lists the attachments from the highest
+// down:
+//
+//	b := &attachments.Builder{Job: client.Job("job-123")}
+//	report, err := b.Build(ctx)
+//	_ = report.WriteCSV(os.Stdout)
+//
+// Use BuildReport and BuildSectionReports to produce the same report from records already fetched
+// or cached.
+package attachments
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// DefaultConcurrency is the number of photos whose elements are fetched at once when
+// Builder.Concurrency is zero.
+const DefaultConcurrency = 4
+
+// Attachment is one traced element on one photo of a node or section. A wire measured on several
+// photos of the same pole appears once per photo.
+type Attachment struct {
+	NodeID       string `json:"node_id,omitempty"`
+	ConnectionID string `json:"connection_id,omitempty"` // Set with SectionID for midspan attachments
+	SectionID    string `json:"section_id,omitempty"`
+	PhotoID      string `json:"photo_id"`
+	MainPhoto    bool   `json:"main_photo,omitempty"`
+	ElementID    string `json:"element_id"`
+	ElementType  string `json:"element_type"`
+	TraceID      string `json:"trace_id,omitempty"`
+	TraceLabel   string `json:"trace_label,omitempty"`
+	Owner        string `json:"owner,omitempty"` // Trace company
+	CableType    string `json:"cable_type,omitempty"`
+	// Height is the manual height when set, otherwise the measured height. HasHeight is false when
+	// the element has neither.
+	Height    katapultpro.Height `json:"height_in"`
+	HasHeight bool               `json:"has_height"`
+	// Measured is _measured_height (inches); zero when not measured.
+	Measured katapultpro.Height `json:"measured_height_in,omitempty"`
+	// Manual is manual_height as entered (feet-inches), or "".
+	Manual string `json:"manual_height,omitempty"`
+}
+
+// Difference returns Measured minus the manual height, and false unless both are present.
+func (a Attachment) Difference() (katapultpro.Height, bool) {
+	if a.Manual == "" || a.Measured == 0 {
+		return 0, false
+	}
+	m, err := katapultpro.ParseHeight(a.Manual)
+	if err != nil {
+		return 0, false
+	}
+	return a.Measured - m, true
+}
+
+// NodeReport lists a node's attachments from the highest down; attachments without a height come last.
+type NodeReport struct {
+	NodeID      string       `json:"node_id"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// SectionReport lists the attachments measured at a connection section (midspan), from the highest down.
+type SectionReport struct {
+	ConnectionID string       `json:"connection_id"`
+	SectionID    string       `json:"section_id"`
+	Attachments  []Attachment `json:"attachments"`
+}
+
+// Report is the attachment report for a job: one entry per node and per section with at least one
+// attachment, ordered by ID.
+type Report struct {
+	JobID    string          `json:"job_id"`
+	Nodes    []NodeReport    `json:"nodes"`
+	Sections []SectionReport `json:"sections,omitempty"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// csvHeader is the header row of WriteCSV.
+var csvHeader = []string{"node_id", "connection_id", "section_id", "photo_id", "main_photo", "element_id", "element_type", "trace_id", "trace_label",
+	"owner", "cable_type", "height", "height_in", "measured_height_in", "manual_height"}
+
+// WriteCSV writes one row per attachment, node attachments first. Heights are given in feet-inches
+// ("height") and in inches.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	var all []Attachment
+	for _, n := range r.Nodes {
+		all = append(all, n.Attachments...)
+	}
+	for _, s := range r.Sections {
+		all = append(all, s.Attachments...)
+	}
+	for _, a := range all {
+		height, heightIn, measured := "", "", ""
+		if a.HasHeight {
+			height, heightIn = a.Height.String(), inches(a.Height)
+		}
+		if a.Measured != 0 {
+			measured = inches(a.Measured)
+		}
+		row := []string{a.NodeID, a.ConnectionID, a.SectionID, a.PhotoID, strconv.FormatBool(a.MainPhoto), a.ElementID, a.ElementType,
+			a.TraceID, a.TraceLabel, a.Owner, a.CableType, height, heightIn, measured, a.Manual}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func inches(h katapultpro.Height) string { return strconv.FormatFloat(h.Inches(), 'f', 2, 64) }
+
+// Builder fetches a job's records and builds its attachment report.
+type Builder struct {
+	Job *katapultpro.JobScope
+	// MainPhotoOnly limits each node to its main photo(s).
+	MainPhotoOnly bool
+	// Concurrency bounds the element listings in flight. Zero means DefaultConcurrency.
+	Concurrency int
+}
+
+// Build fetches the job's nodes, connections, photos, traces, and the elements of every photo
+// associated with a node or section, and builds the report.
+func (b *Builder) Build(ctx context.Context) (*Report, error) {
+	nodes, err := b.Job.Nodes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("attachments: list nodes: %w", err)
+	}
+	conns, err := b.Job.Connections().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("attachments: list connections: %w", err)
+	}
+	photos, err := b.Job.Photos().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("attachments: list photos: %w", err)
+	}
+	traces, err := b.Job.Traces().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("attachments: list traces: %w", err)
+	}
+
+	wanted := map[string]bool{}
+	want := func(m katapultpro.PhotoAssociationMap) {
+		for id, assoc := range m {
+			if !b.MainPhotoOnly || isMain(assoc) {
+				wanted[id] = true
+			}
+		}
+	}
+	for _, n := range nodes {
+		want(n.Photos)
+	}
+	for _, c := range conns {
+		for _, sec := range c.Sections {
+			want(sec.Photos)
+		}
+	}
+	ids := make([]string, 0, len(wanted))
+	for id := range wanted {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	elements, err := b.Job.Photos().ListAllElements(ctx, ids, concurrency(b.Concurrency))
+	if err != nil {
+		return nil, fmt.Errorf("attachments: %w", err)
+	}
+	report := BuildReport(b.Job.JobID(), nodes, photos, elements, traces, b.MainPhotoOnly)
+	report.Sections = BuildSectionReports(conns, photos, elements, traces, b.MainPhotoOnly)
+	return report, nil
+}
+
+func concurrency(n int) int {
+	if n <= 0 {
+		return DefaultConcurrency
+	}
+	return n
+}
+
+// BuildReport builds the report from fetched records. elements maps photo IDs to their photo
+// elements and may be nil, in which case only the wire measurements in photofirst_data are used.
+// An element is included if it has a trace (its _trace or trace_id, or a trace whose items list it)
+// or is a wire measurement.
+func BuildReport(jobID string, nodes []katapultpro.Node, photos []katapultpro.Photo, elements map[string][]katapultpro.PhotoElement,
+	traces []katapultpro.Trace, mainPhotoOnly bool) *Report {
+	j := newJoiner(photos, elements, traces, mainPhotoOnly)
+	report := &Report{JobID: jobID}
+	sorted := append([]katapultpro.Node(nil), nodes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	for _, n := range sorted {
+		if atts := j.collect(Attachment{NodeID: n.ID}, n.Photos); len(atts) > 0 {
+			report.Nodes = append(report.Nodes, NodeReport{NodeID: n.ID, Attachments: atts})
+		}
+	}
+	return report
+}
+
+// BuildSectionReports builds the midspan part of the report from the sections embedded in the
+// connections, like BuildReport does for nodes.
+func BuildSectionReports(conns []katapultpro.Connection, photos []katapultpro.Photo, elements map[string][]katapultpro.PhotoElement,
+	traces []katapultpro.Trace, mainPhotoOnly bool) []SectionReport {
+	j := newJoiner(photos, elements, traces, mainPhotoOnly)
+	var out []SectionReport
+	for _, c := range conns {
+		for id, sec := range c.Sections {
+			if atts := j.collect(Attachment{ConnectionID: c.ID, SectionID: id}, sec.Photos); len(atts) > 0 {
+				out = append(out, SectionReport{ConnectionID: c.ID, SectionID: id, Attachments: atts})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ConnectionID != out[j].ConnectionID {
+			return out[i].ConnectionID < out[j].ConnectionID
+		}
+		return out[i].SectionID < out[j].SectionID
+	})
+	return out
+}
+
+// joiner indexes photos and traces for building attachment lists.
+type joiner struct {
+	photoByID     map[string]*katapultpro.Photo
+	traceByID     map[string]*katapultpro.Trace
+	itemTrace     map[string]string // photo ID + "/" + element ID to the trace listing it
+	elements      map[string][]katapultpro.PhotoElement
+	mainPhotoOnly bool
+}
+
+func newJoiner(photos []katapultpro.Photo, elements map[string][]katapultpro.PhotoElement, traces []katapultpro.Trace, mainPhotoOnly bool) *joiner {
+	j := &joiner{
+		photoByID:     make(map[string]*katapultpro.Photo, len(photos)),
+		traceByID:     make(map[string]*katapultpro.Trace, len(traces)),
+		itemTrace:     map[string]string{},
+		elements:      elements,
+		mainPhotoOnly: mainPhotoOnly,
+	}
+	for i := range photos {
+		j.photoByID[photos[i].ID] = &photos[i]
+	}
+	for i := range traces {
+		t := &traces[i]
+		j.traceByID[t.ID] = t
+		for photoID, item := range t.Items {
+			for _, els := range item {
+				for elID, on := range els {
+					if on {
+						j.itemTrace[photoID+"/"+elID] = t.ID
+					}
+				}
+			}
+		}
+	}
+	return j
+}
+
+// collect returns the attachments on the associated photos, highest first, with base's location fields.
+func (j *joiner) collect(base Attachment, assoc katapultpro.PhotoAssociationMap) []Attachment {
+	photoIDs := make([]string, 0, len(assoc))
+	for id, a := range assoc {
+		if !j.mainPhotoOnly || isMain(a) {
+			photoIDs = append(photoIDs, id)
+		}
+	}
+	sort.Strings(photoIDs)
+	var atts []Attachment
+	for _, photoID := range photoIDs {
+		b := base
+		b.PhotoID, b.MainPhoto = photoID, isMain(assoc[photoID])
+		atts = append(atts, photoAttachments(b, j.photoByID[photoID], j.elements[photoID], j.itemTrace)...)
+	}
+	for i := range atts {
+		a := &atts[i]
+		if t := j.traceByID[a.TraceID]; t != nil {
+			a.TraceLabel, a.Owner, a.CableType = t.Label, t.Company, t.CableType
+		}
+	}
+	sort.SliceStable(atts, func(i, k int) bool {
+		a, b := atts[i], atts[k]
+		if a.HasHeight != b.HasHeight {
+			return a.HasHeight
+		}
+		if a.Height != b.Height {
+			return a.Height > b.Height
+		}
+		if a.PhotoID != b.PhotoID {
+			return a.PhotoID < b.PhotoID
+		}
+		return a.ElementID < b.ElementID
+	})
+	return atts
+}
+
+// photoAttachments returns the traced elements and wire measurements of one photo. Elements and
+// wire measurements with the same ID are the same wire and are merged.
+func photoAttachments(base Attachment, photo *katapultpro.Photo, elements []katapultpro.PhotoElement, itemTrace map[string]string) []Attachment {
+	var out []Attachment
+	seen := map[string]bool{}
+	var wires map[string]katapultpro.WireMeasurement
+	if photo != nil && photo.PhotofirstData != nil {
+		wires = photo.PhotofirstData.Wire
+	}
+	for _, e := range elements {
+		traceID := e.Trace
+		if traceID == "" {
+			traceID = e.TraceID
+		}
+		if traceID == "" {
+			traceID = itemTrace[base.PhotoID+"/"+e.ID]
+		}
+		w, isWire := wires[e.ID]
+		if traceID == "" && !isWire {
+			continue
+		}
+		if e.MeasuredHeight == 0 && isWire {
+			e.MeasuredHeight = w.MeasuredHeight
+		}
+		if traceID == "" {
+			traceID = w.Trace
+		}
+		a := base
+		a.ElementID, a.ElementType, a.TraceID = e.ID, e.ElementType, traceID
+		if a.ElementType == "" {
+			a.ElementType = "wire"
+		}
+		a.Manual, a.Measured = e.ManualHeight, e.MeasuredHeightValue()
+		a.Height, a.HasHeight = e.Height()
+		out = append(out, a)
+		seen[e.ID] = true
+	}
+	wireIDs := make([]string, 0, len(wires))
+	for id := range wires {
+		if !seen[id] {
+			wireIDs = append(wireIDs, id)
+		}
+	}
+	sort.Strings(wireIDs)
+	for _, id := range wireIDs {
+		w := wires[id]
+		a := base
+		a.ElementID, a.ElementType, a.TraceID = id, "wire", w.Trace
+		if a.TraceID == "" {
+			a.TraceID = itemTrace[base.PhotoID+"/"+id]
+		}
+		a.Measured = w.Height()
+		a.Height, a.HasHeight = a.Measured, w.MeasuredHeight != 0
+		out = append(out, a)
+	}
+	return out
+}
+
+// isMain reports whether a node's photo association is "main".
+func isMain(a katapultpro.PhotoAssociation) bool {
+	s, ok := a.Association.(string)
+	return ok && s == "main"
+}
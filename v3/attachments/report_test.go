@@ -0,0 +1,114 @@
+package attachments_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/attachments"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+func TestBuilder_JoinsWiresElementsAndTraces(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Make ready"})
+	power := srv.AddTrace(job.ID, katapultpro.Trace{TraceType: "cable", Company: "PowerCo", CableType: "Primary", Label: "P1"})
+	telco := srv.AddTrace(job.ID, katapultpro.Trace{TraceType: "cable", Company: "Brightspeed", CableType: "Telco Com", Label: "T1"})
+	photo := srv.AddPhoto(job.ID, katapultpro.Photo{PhotofirstData: &katapultpro.PhotofirstData{
+		Wire: map[string]katapultpro.WireMeasurement{
+			"wire-power": {MeasuredHeight: 420, Trace: power.ID},
+			"wire-loose": {MeasuredHeight: 150},
+		},
+	}})
+	other := srv.AddPhoto(job.ID, katapultpro.Photo{})
+	pole := srv.AddNode(job.ID, katapultpro.Node{Photos: katapultpro.PhotoAssociationMap{
+		photo.ID: {Association: "main"},
+		other.ID: {Association: true},
+	}})
+	srv.AddNode(job.ID, katapultpro.Node{}) // no photos, not reported
+
+	client := srv.Client()
+	ctx := context.Background()
+	_, err := client.Job(job.ID).Photos().Photo(photo.ID).Elements().Create(ctx, &katapultpro.CreatePhotoElementRequest{
+		ElementType: "wire", ManualHeight: "20-6", TraceID: telco.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := (&attachments.Builder{Job: client.Job(job.ID)}).Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Nodes) != 1 || report.Nodes[0].NodeID != pole.ID {
+		t.Fatalf("nodes = %+v", report.Nodes)
+	}
+	got := report.Nodes[0].Attachments
+	if len(got) != 3 {
+		t.Fatalf("attachments = %+v", got)
+	}
+	if a := got[0]; a.ElementID != "wire-power" || a.Owner != "PowerCo" || a.CableType != "Primary" || a.TraceLabel != "P1" ||
+		a.Height != katapultpro.Inches(420) || !a.MainPhoto || a.PhotoID != photo.ID {
+		t.Errorf("top attachment = %+v", a)
+	}
+	if a := got[1]; a.Owner != "Brightspeed" || a.Manual != "20-6" || a.Height != katapultpro.FeetInches(20, 6) {
+		t.Errorf("second attachment = %+v", a)
+	}
+	if a := got[2]; a.ElementID != "wire-loose" || a.TraceID != "" || a.Height != katapultpro.Inches(150) {
+		t.Errorf("third attachment = %+v", a)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[0][0] != "node_id" || rows[1][11] != "35-0" || rows[1][12] != "420.00" || rows[2][14] != "20-6" {
+		t.Errorf("csv = %q", rows)
+	}
+
+	buf.Reset()
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded attachments.Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Nodes[0].Attachments) != 3 {
+		t.Errorf("json round trip: %v", err)
+	}
+}
+
+func TestBuildReport_MeasuredVsManual(t *testing.T) {
+	nodes := []katapultpro.Node{{ID: "n1", Photos: katapultpro.PhotoAssociationMap{"p1": {Association: "main"}, "p2": {Association: true}}}}
+	photos := []katapultpro.Photo{{ID: "p1", PhotofirstData: &katapultpro.PhotofirstData{
+		Wire: map[string]katapultpro.WireMeasurement{"w1": {MeasuredHeight: 246}},
+	}}}
+	elements := map[string][]katapultpro.PhotoElement{
+		"p1": {{ID: "w1", ElementType: "wire", ManualHeight: "20-0"}},
+		"p2": {{ID: "w2", ElementType: "wire", MeasuredHeight: 300}}, // no trace: dropped
+	}
+	traces := []katapultpro.Trace{{ID: "t1", Company: "Comcast", Items: map[string]katapultpro.TraceItem{"p1": {"wire": {"w1": true}}}}}
+
+	report := attachments.BuildReport("j1", nodes, photos, elements, traces, false)
+	atts := report.Nodes[0].Attachments
+	if len(atts) != 1 {
+		t.Fatalf("attachments = %+v", atts)
+	}
+	a := atts[0]
+	if a.TraceID != "t1" || a.Owner != "Comcast" || a.Measured != katapultpro.Inches(246) || a.Height != katapultpro.Feet(20) {
+		t.Errorf("attachment = %+v", a)
+	}
+	if d, ok := a.Difference(); !ok || d != katapultpro.Inches(6) {
+		t.Errorf("difference = %v, %v", d, ok)
+	}
+
+	if r := attachments.BuildReport("j1", nodes, photos, elements, traces, true); len(r.Nodes[0].Attachments) != 1 {
+		t.Errorf("main only = %+v", r.Nodes)
+	}
+}
@@ -0,0 +1,288 @@
+// Package attrs reads and writes typed values in attribute lists. It is the runtime for the structs
+// cmd/attrgen generates from an attribute schema, and can be used on its own:
+//
+//	height, err := attrs.One[float64](node.Attributes, "pole_height") // nil if absent
+//	notes, err := attrs.Many[string](node.Attributes, "note")
+//
+//	set := katapultpro.EntityAttributeList{}
+//	attrs.SetOne(set, "pole_height", &height, attrs.Reuse, node.Attributes)
+//	attrs.Update(&req.RemoveAttributes, &req.Attributes, set, node.Attributes)
+//
+// Writing a value needs instance IDs, which a Strategy chooses: Sequential numbers the instances
+// "-a", "-b", ...; Reuse keeps the IDs of the entity's current instances; Random generates new IDs
+// shaped like the ones the API generates.
+package attrs
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Value is a Go type attribute values are read and written as. interface{} reads values as they
+// were decoded from JSON.
+type Value interface {
+	string | float64 | int64 | bool | interface{}
+}
+
+// One returns the attribute's value with the lowest instance ID, or nil if it has no non-null
+// instances.
+func One[T Value](list katapultpro.EntityAttributeList, name string) (*T, error) {
+	for _, id := range sortedIDs(list[name]) {
+		if list[name][id] == nil {
+			continue
+		}
+		v, err := convert[T](list[name][id])
+		if err != nil {
+			return nil, fmt.Errorf("attrs: %s (%s): %w", name, id, err)
+		}
+		return &v, nil
+	}
+	return nil, nil
+}
+
+// Many returns the attribute's non-null values in instance ID order.
+func Many[T Value](list katapultpro.EntityAttributeList, name string) ([]T, error) {
+	var out []T
+	for _, id := range sortedIDs(list[name]) {
+		if list[name][id] == nil {
+			continue
+		}
+		v, err := convert[T](list[name][id])
+		if err != nil {
+			return nil, fmt.Errorf("attrs: %s (%s): %w", name, id, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// convert reads v as T. Numbers and booleans are also read from strings, and strings from numbers
+// and booleans, since the API often stores attributes as text.
+func convert[T Value](v interface{}) (T, error) {
+	var out T
+	switch p := any(&out).(type) {
+	case *string:
+		switch t := v.(type) {
+		case string:
+			*p = t
+		case float64:
+			*p = strconv.FormatFloat(t, 'f', -1, 64)
+		case bool:
+			*p = strconv.FormatBool(t)
+		default:
+			return out, fmt.Errorf("want a string, got %T", v)
+		}
+	case *float64:
+		n, ok := number(v)
+		if !ok {
+			return out, fmt.Errorf("want a number, got %v", v)
+		}
+		*p = n
+	case *int64:
+		n, ok := number(v)
+		if !ok || n != math.Trunc(n) || math.Abs(n) > 1<<53 {
+			return out, fmt.Errorf("want a whole number, got %v", v)
+		}
+		*p = int64(n)
+	case *bool:
+		switch t := v.(type) {
+		case bool:
+			*p = t
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(t))
+			if err != nil {
+				return out, fmt.Errorf("want a boolean, got %q", t)
+			}
+			*p = b
+		default:
+			return out, fmt.Errorf("want a boolean, got %T", v)
+		}
+	case *interface{}:
+		*p = v
+	}
+	return out, nil
+}
+
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return n, err == nil && !math.IsNaN(n) && !math.IsInf(n, 0)
+	}
+	return 0, false
+}
+
+// SetOne adds v to list under name with one instance ID from s. A nil v is not added. current is
+// the entity's current attribute list, or nil for a new entity or when it is not known.
+func SetOne[T Value](list katapultpro.EntityAttributeList, name string, v *T, s Strategy, current katapultpro.EntityAttributeList) {
+	if v == nil {
+		return
+	}
+	list[name] = map[string]interface{}{s.IDs(name, current[name], 1)[0]: *v}
+}
+
+// SetMany adds vs to list under name with instance IDs from s. An empty vs is not added.
+func SetMany[T Value](list katapultpro.EntityAttributeList, name string, vs []T, s Strategy, current katapultpro.EntityAttributeList) {
+	if len(vs) == 0 {
+		return
+	}
+	ids := s.IDs(name, current[name], len(vs))
+	instances := make(map[string]interface{}, len(vs))
+	for i, v := range vs {
+		instances[ids[i]] = v
+	}
+	list[name] = instances
+}
+
+// Merge copies set into *dst, allocating it if needed. Use it for create requests.
+func Merge(dst *katapultpro.EntityAttributeList, set katapultpro.EntityAttributeList) {
+	if len(set) == 0 {
+		return
+	}
+	if *dst == nil {
+		*dst = katapultpro.EntityAttributeList{}
+	}
+	for name, instances := range set {
+		if (*dst)[name] == nil {
+			(*dst)[name] = map[string]interface{}{}
+		}
+		for id, v := range instances {
+			(*dst)[name][id] = v
+		}
+	}
+}
+
+// Update adds set to an update request so that each attribute in it ends up with exactly the
+// instances in set. With current, the current instances set does not keep are nulled out; without
+// it, the attribute is also listed in *remove, which the API applies before the attributes.
+func Update(remove *[]string, dst *katapultpro.EntityAttributeList, set, current katapultpro.EntityAttributeList) {
+	for _, name := range sortedNames(set) {
+		instances := set[name]
+		if current == nil {
+			if !contains(*remove, name) {
+				*remove = append(*remove, name)
+			}
+			continue
+		}
+		for id := range current[name] {
+			if _, ok := instances[id]; !ok {
+				instances[id] = nil
+			}
+		}
+	}
+	Merge(dst, set)
+}
+
+// Strategy chooses instance IDs for the values written to an attribute.
+type Strategy interface {
+	// IDs returns n distinct instance IDs for the attribute, given its current instances, which
+	// are nil when the entity is new or not known.
+	IDs(name string, current map[string]interface{}, n int) []string
+}
+
+// StrategyFunc adapts a function to a Strategy.
+type StrategyFunc func(name string, current map[string]interface{}, n int) []string
+
+// IDs calls f.
+func (f StrategyFunc) IDs(name string, current map[string]interface{}, n int) []string {
+	return f(name, current, n)
+}
+
+// Strategies.
+var (
+	// Sequential uses "-a", "-b", ..., "-z", "-aa", ... regardless of the current instances, so
+	// the same values always produce the same list.
+	Sequential Strategy = StrategyFunc(func(_ string, _ map[string]interface{}, n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = sequentialID(i)
+		}
+		return ids
+	})
+	// Reuse keeps the current instance IDs in sorted order, then continues with sequential IDs
+	// not already in use.
+	Reuse Strategy = StrategyFunc(func(_ string, current map[string]interface{}, n int) []string {
+		ids := sortedIDs(current)
+		if len(ids) > n {
+			ids = ids[:n]
+		}
+		for i := 0; len(ids) < n; i++ {
+			if id := sequentialID(i); !contains(ids, id) && !has(current, id) {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	})
+	// Random generates new 20-character push-style IDs, as the API does for add_attributes.
+	Random Strategy = StrategyFunc(func(_ string, _ map[string]interface{}, n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = randomID()
+		}
+		return ids
+	})
+)
+
+func sequentialID(i int) string {
+	var b []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		b = append([]byte{byte('a' + (i-1)%26)}, b...)
+	}
+	return "-" + string(b)
+}
+
+const pushAlphabet = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+func randomID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	b[0] = '-'
+	for i := 1; i < len(b); i++ {
+		b[i] = pushAlphabet[int(b[i])%len(pushAlphabet)]
+	}
+	return string(b)
+}
+
+func sortedIDs(instances map[string]interface{}) []string {
+	ids := make([]string, 0, len(instances))
+	for id := range instances {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortedNames(list katapultpro.EntityAttributeList) []string {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func has(instances map[string]interface{}, id string) bool {
+	_, ok := instances[id]
+	return ok
+}
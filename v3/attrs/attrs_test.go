@@ -0,0 +1,141 @@
+package attrs_test
+
+//go:generate go run ../cmd/attrgen -schema testdata/schema.yaml -package attrs_test -node PoleAttributes -connection SpanAttributes -section MidspanAttributes -out example_gen_test.go
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/attrs"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestOneMany(t *testing.T) {
+	list := katapultpro.EntityAttributeList{
+		"pole_height": {"-b": 40.0, "-a": "35.5"},
+		"pole_class":  {"-a": "3"},
+		"guyed":       {"-a": "true"},
+		"note":        {"-c": "three", "-a": "one", "-b": nil},
+		"bad":         {"-a": "x"},
+	}
+	if h, err := attrs.One[float64](list, "pole_height"); err != nil || *h != 35.5 {
+		t.Errorf("pole_height = %v, %v", h, err)
+	}
+	if c, err := attrs.One[int64](list, "pole_class"); err != nil || *c != 3 {
+		t.Errorf("pole_class = %v, %v", c, err)
+	}
+	if g, err := attrs.One[bool](list, "guyed"); err != nil || !*g {
+		t.Errorf("guyed = %v, %v", g, err)
+	}
+	if s, err := attrs.One[string](list, "pole_height"); err != nil || *s != "35.5" {
+		t.Errorf("pole_height as string = %v, %v", s, err)
+	}
+	if n, err := attrs.Many[string](list, "note"); err != nil || !reflect.DeepEqual(n, []string{"one", "three"}) {
+		t.Errorf("note = %v, %v", n, err)
+	}
+	if v, err := attrs.One[string](list, "missing"); err != nil || v != nil {
+		t.Errorf("missing = %v, %v", v, err)
+	}
+	if _, err := attrs.One[float64](list, "bad"); err == nil || err.Error() != "attrs: bad (-a): want a number, got x" {
+		t.Errorf("bad err = %v", err)
+	}
+	if _, err := attrs.One[int64](list, "pole_height"); err == nil {
+		t.Error("35.5 as integer: expected error")
+	}
+}
+
+func TestStrategies(t *testing.T) {
+	if got := attrs.Sequential.IDs("x", map[string]interface{}{"-q": 1}, 3); !reflect.DeepEqual(got, []string{"-a", "-b", "-c"}) {
+		t.Errorf("Sequential = %v", got)
+	}
+	if got := attrs.Sequential.IDs("x", nil, 28)[26:]; !reflect.DeepEqual(got, []string{"-aa", "-ab"}) {
+		t.Errorf("Sequential past z = %v", got)
+	}
+	current := map[string]interface{}{"-y": 1, "-b": 2}
+	if got := attrs.Reuse.IDs("x", current, 3); !reflect.DeepEqual(got, []string{"-b", "-y", "-a"}) {
+		t.Errorf("Reuse = %v", got)
+	}
+	if got := attrs.Reuse.IDs("x", current, 1); !reflect.DeepEqual(got, []string{"-b"}) {
+		t.Errorf("Reuse fewer = %v", got)
+	}
+	ids := attrs.Random.IDs("x", nil, 2)
+	if len(ids[0]) != 20 || ids[0][0] != '-' || ids[0] == ids[1] {
+		t.Errorf("Random = %v", ids)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	set := katapultpro.EntityAttributeList{"note": {"-a": "new"}}
+	var req katapultpro.UpdateNodeRequest
+	attrs.Update(&req.RemoveAttributes, &req.Attributes, set, katapultpro.EntityAttributeList{"note": {"-a": "old", "-b": "gone"}})
+	if req.RemoveAttributes != nil || !reflect.DeepEqual(req.Attributes, katapultpro.EntityAttributeList{"note": {"-a": "new", "-b": nil}}) {
+		t.Errorf("with current: %+v", req)
+	}
+
+	req = katapultpro.UpdateNodeRequest{}
+	attrs.Update(&req.RemoveAttributes, &req.Attributes, katapultpro.EntityAttributeList{"note": {"-a": "new"}}, nil)
+	if !reflect.DeepEqual(req.RemoveAttributes, []string{"note"}) || len(req.Attributes["note"]) != 1 {
+		t.Errorf("without current: %+v", req)
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Typed"})
+	nodes := srv.Client().Job(job.ID).Nodes()
+	ctx := context.Background()
+
+	pole := PoleAttributes{NodeType: ptr("pole"), SCID: ptr("012A"), PoleHeight: ptr(40.5), PoleClass: ptr(int64(3)),
+		Note: []string{"leaning", "tagged"}, Tag: ptr[interface{}]("T-1")}
+	create := katapultpro.CreateNodeRequest{Latitude: 40, Longitude: -111}
+	pole.ApplyCreate(&create, attrs.Sequential)
+	node, err := nodes.Create(ctx, &create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node, err = nodes.Get(ctx, node.ID); err != nil {
+		t.Fatal(err)
+	}
+	var got PoleAttributes
+	if err := got.UnmarshalAttributes(node.Attributes); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, pole) {
+		t.Errorf("round trip = %+v, want %+v", got, pole)
+	}
+
+	// Replace note with a single value, keeping its first instance ID; other attributes are untouched.
+	var update katapultpro.UpdateNodeRequest
+	(&PoleAttributes{Note: []string{"straightened"}}).ApplyUpdate(&update, attrs.Reuse, node.Attributes)
+	if node, err = nodes.Update(ctx, node.ID, &update, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(node.Attributes["note"], map[string]interface{}{"-a": "straightened"}) || node.Attributes["scid"]["-a"] != "012A" {
+		t.Errorf("after Reuse update: %v", node.Attributes)
+	}
+
+	// Without the current list, remove_attributes clears the old instances first.
+	update = katapultpro.UpdateNodeRequest{}
+	(&PoleAttributes{PoleHeight: ptr(45.0)}).ApplyUpdate(&update, attrs.Random, nil)
+	if node, err = nodes.Update(ctx, node.ID, &update, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := got.UnmarshalAttributes(node.Attributes); err != nil || *got.PoleHeight != 45 || len(node.Attributes["pole_height"]) != 1 {
+		t.Errorf("after Random update: %v, %v", node.Attributes, err)
+	}
+
+	var span SpanAttributes
+	if err := span.UnmarshalAttributes(katapultpro.EntityAttributeList{"connection_type": {"-x": "aerial cable"}}); err != nil || *span.ConnectionType != "aerial cable" {
+		t.Errorf("span = %+v, %v", span, err)
+	}
+	var sec katapultpro.CreateSectionRequest
+	(&MidspanAttributes{MidspanHeight: ptr(18.0)}).ApplyCreate(&sec, attrs.Sequential)
+	if len(sec.Attributes) != 1 || sec.Attributes["midspan_height"]["-a"] != 18.0 {
+		t.Errorf("section request = %+v", sec)
+	}
+}
@@ -0,0 +1,152 @@
+// Code generated by attrgen from schema.yaml. DO NOT EDIT.
+
+package attrs_test
+
+import (
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/attrs"
+)
+
+// PoleAttributes holds the node attributes of schema.yaml. Unset fields are nil.
+type PoleAttributes struct {
+	Guyed      *bool        // guyed
+	NodeType   *string      // node_type; one of pole, reference; required
+	Note       []string     // note
+	Tag        *interface{} // pole tag
+	PoleClass  *int64       // pole_class
+	PoleHeight *float64     // pole_height; 20 to 150
+	SCID       *string      // scid; matches ^[0-9]{3}[A-Z]?$; required
+}
+
+// UnmarshalAttributes sets a from list, replacing all of its fields.
+func (a *PoleAttributes) UnmarshalAttributes(list katapultpro.EntityAttributeList) error {
+	var (
+		v   PoleAttributes
+		err error
+	)
+	if v.Guyed, err = attrs.One[bool](list, "guyed"); err != nil {
+		return err
+	}
+	if v.NodeType, err = attrs.One[string](list, "node_type"); err != nil {
+		return err
+	}
+	if v.Note, err = attrs.Many[string](list, "note"); err != nil {
+		return err
+	}
+	if v.Tag, err = attrs.One[interface{}](list, "pole tag"); err != nil {
+		return err
+	}
+	if v.PoleClass, err = attrs.One[int64](list, "pole_class"); err != nil {
+		return err
+	}
+	if v.PoleHeight, err = attrs.One[float64](list, "pole_height"); err != nil {
+		return err
+	}
+	if v.SCID, err = attrs.One[string](list, "scid"); err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalAttributes returns the fields that are set as an attribute list, with instance IDs
+// chosen by s. current is the node's current attribute list, or nil.
+func (a *PoleAttributes) MarshalAttributes(s attrs.Strategy, current katapultpro.EntityAttributeList) katapultpro.EntityAttributeList {
+	list := katapultpro.EntityAttributeList{}
+	attrs.SetOne(list, "guyed", a.Guyed, s, current)
+	attrs.SetOne(list, "node_type", a.NodeType, s, current)
+	attrs.SetMany(list, "note", a.Note, s, current)
+	attrs.SetOne(list, "pole tag", a.Tag, s, current)
+	attrs.SetOne(list, "pole_class", a.PoleClass, s, current)
+	attrs.SetOne(list, "pole_height", a.PoleHeight, s, current)
+	attrs.SetOne(list, "scid", a.SCID, s, current)
+	return list
+}
+
+// ApplyCreate adds the fields that are set to req.
+func (a *PoleAttributes) ApplyCreate(req *katapultpro.CreateNodeRequest, s attrs.Strategy) {
+	attrs.Merge(&req.Attributes, a.MarshalAttributes(s, nil))
+}
+
+// ApplyUpdate adds the fields that are set to req, replacing all instances of those attributes.
+// current is the node's current attribute list; when it is nil the attributes are also listed in
+// RemoveAttributes. Unset fields leave their attributes unchanged.
+func (a *PoleAttributes) ApplyUpdate(req *katapultpro.UpdateNodeRequest, s attrs.Strategy, current katapultpro.EntityAttributeList) {
+	attrs.Update(&req.RemoveAttributes, &req.Attributes, a.MarshalAttributes(s, current), current)
+}
+
+// SpanAttributes holds the connection attributes of schema.yaml. Unset fields are nil.
+type SpanAttributes struct {
+	ConnectionType *string // connection_type; one of aerial cable, underground cable
+}
+
+// UnmarshalAttributes sets a from list, replacing all of its fields.
+func (a *SpanAttributes) UnmarshalAttributes(list katapultpro.EntityAttributeList) error {
+	var (
+		v   SpanAttributes
+		err error
+	)
+	if v.ConnectionType, err = attrs.One[string](list, "connection_type"); err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalAttributes returns the fields that are set as an attribute list, with instance IDs
+// chosen by s. current is the connection's current attribute list, or nil.
+func (a *SpanAttributes) MarshalAttributes(s attrs.Strategy, current katapultpro.EntityAttributeList) katapultpro.EntityAttributeList {
+	list := katapultpro.EntityAttributeList{}
+	attrs.SetOne(list, "connection_type", a.ConnectionType, s, current)
+	return list
+}
+
+// ApplyCreate adds the fields that are set to req.
+func (a *SpanAttributes) ApplyCreate(req *katapultpro.CreateConnectionRequest, s attrs.Strategy) {
+	attrs.Merge(&req.Attributes, a.MarshalAttributes(s, nil))
+}
+
+// ApplyUpdate adds the fields that are set to req, replacing all instances of those attributes.
+// current is the connection's current attribute list; when it is nil the attributes are also listed in
+// RemoveAttributes. Unset fields leave their attributes unchanged.
+func (a *SpanAttributes) ApplyUpdate(req *katapultpro.UpdateConnectionRequest, s attrs.Strategy, current katapultpro.EntityAttributeList) {
+	attrs.Update(&req.RemoveAttributes, &req.Attributes, a.MarshalAttributes(s, current), current)
+}
+
+// MidspanAttributes holds the section attributes of schema.yaml. Unset fields are nil.
+type MidspanAttributes struct {
+	MidspanHeight *float64 // midspan_height; at least 0
+}
+
+// UnmarshalAttributes sets a from list, replacing all of its fields.
+func (a *MidspanAttributes) UnmarshalAttributes(list katapultpro.EntityAttributeList) error {
+	var (
+		v   MidspanAttributes
+		err error
+	)
+	if v.MidspanHeight, err = attrs.One[float64](list, "midspan_height"); err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// MarshalAttributes returns the fields that are set as an attribute list, with instance IDs
+// chosen by s. current is the section's current attribute list, or nil.
+func (a *MidspanAttributes) MarshalAttributes(s attrs.Strategy, current katapultpro.EntityAttributeList) katapultpro.EntityAttributeList {
+	list := katapultpro.EntityAttributeList{}
+	attrs.SetOne(list, "midspan_height", a.MidspanHeight, s, current)
+	return list
+}
+
+// ApplyCreate adds the fields that are set to req.
+func (a *MidspanAttributes) ApplyCreate(req *katapultpro.CreateSectionRequest, s attrs.Strategy) {
+	attrs.Merge(&req.Attributes, a.MarshalAttributes(s, nil))
+}
+
+// ApplyUpdate adds the fields that are set to req, replacing all instances of those attributes.
+// current is the section's current attribute list; when it is nil the attributes are also listed in
+// RemoveAttributes. Unset fields leave their attributes unchanged.
+func (a *MidspanAttributes) ApplyUpdate(req *katapultpro.UpdateSectionRequest, s attrs.Strategy, current katapultpro.EntityAttributeList) {
+	attrs.Update(&req.RemoveAttributes, &req.Attributes, a.MarshalAttributes(s, current), current)
+}
@@ -0,0 +1,95 @@
+package bulkupload
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// DefaultManifest is the manifest file name used when Uploader.Manifest is empty. It is created in
+// the directory being uploaded.
+const DefaultManifest = ".kpro-upload.jsonl"
+
+// ManifestEntry records one file a run uploaded.
+type ManifestEntry struct {
+	JobID      string `json:"job_id"`
+	Path       string `json:"path"` // Relative to the uploaded directory, with "/" separators
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	PhotoID    string `json:"photo_id"`
+	Associated bool   `json:"associated,omitempty"`
+}
+
+// manifest is the local record of uploads. The API keeps no filename for uploaded photos, so this is
+// how a later run knows which files are already in the job. It is a JSON-lines file that is only
+// appended to; the last entry for a job and path wins.
+type manifest struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]ManifestEntry
+}
+
+func manifestKey(jobID, path string) string { return jobID + "\x00" + path }
+
+// loadManifest reads the manifest at path. A missing file is an empty manifest.
+func loadManifest(path string) (*manifest, error) {
+	m := &manifest{path: path, entries: map[string]ManifestEntry{}}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bulkupload: manifest: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e ManifestEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("bulkupload: manifest %s line %d: %w", path, line, err)
+		}
+		m.entries[manifestKey(e.JobID, e.Path)] = e
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("bulkupload: manifest: %w", err)
+	}
+	return m, nil
+}
+
+// lookup returns the entry for the file if its size and hash are unchanged.
+func (m *manifest) lookup(jobID, path string, size int64, sum string) (ManifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[manifestKey(jobID, path)]
+	return e, ok && e.Size == size && e.SHA256 == sum
+}
+
+// record appends e to the manifest file.
+func (m *manifest) record(e ManifestEntry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("manifest: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+	m.entries[manifestKey(e.JobID, e.Path)] = e
+	return nil
+}
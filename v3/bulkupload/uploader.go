@@ -0,0 +1,432 @@
+// Package bulkupload uploads a directory of field photos to a job and associates each one with the
+// nearest node or section by its EXIF GPS position.
+//
+//	u := &bulkupload.Uploader{Job: client.Job("job-123"), MaxDistance: 20}
+//	report, err := u.Run(ctx, "/mnt/card/DCIM")
+//	_ = report.WriteText(os.Stdout)
+//
+// Each upload is recorded in a manifest file (DefaultManifest in the directory, unless
+// Uploader.Manifest says otherwise) keyed on the file's path, size, and SHA-256, because the API keeps
+// no filename for uploaded photos. Files the manifest shows are already in the job, or that match a
+// job photo's filename and size or name_date, are skipped, so a run can be repeated after a failure. Photos without GPS, or with no node or section within MaxDistance,
+// are still uploaded and reported as unmatched for manual association.
+package bulkupload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/exif"
+)
+
+// Defaults for Uploader.
+const (
+	DefaultMaxDistance = 25.0 // Meters
+	DefaultConcurrency = 4
+)
+
+// Status is the outcome for one file.
+type Status string
+
+const (
+	StatusAssociated Status = "associated" // Uploaded and associated with a node or section.
+	StatusUnmatched  Status = "unmatched"  // Uploaded, but no GPS or no target within MaxDistance.
+	StatusSkipped    Status = "skipped"    // Already in the job.
+	StatusPlanned    Status = "planned"    // DryRun: would be uploaded (and associated if NodeID or SectionID is set).
+	StatusFailed     Status = "failed"
+)
+
+// Result describes what happened to one file.
+type Result struct {
+	Path         string  `json:"path"`
+	Status       Status  `json:"status"`
+	PhotoID      string  `json:"photo_id,omitempty"`
+	DateTaken    int64   `json:"date_taken,omitempty"` // Unix seconds from EXIF
+	HasGPS       bool    `json:"has_gps"`
+	Latitude     float64 `json:"latitude,omitempty"`
+	Longitude    float64 `json:"longitude,omitempty"`
+	NodeID       string  `json:"node_id,omitempty"`
+	ConnectionID string  `json:"connection_id,omitempty"`
+	SectionID    string  `json:"section_id,omitempty"`
+	Distance     float64 `json:"distance,omitempty"` // Meters to the associated (or nearest) target
+	Error        string  `json:"error,omitempty"`
+}
+
+// Report is the result of a run, one entry per JPEG in path order.
+type Report struct {
+	JobID   string   `json:"job_id"`
+	Results []Result `json:"results"`
+}
+
+// Unmatched returns the uploaded photos that were not associated.
+func (r *Report) Unmatched() []Result {
+	return r.filter(StatusUnmatched)
+}
+
+// Failed returns the files that could not be read, uploaded, or associated.
+func (r *Report) Failed() []Result {
+	return r.filter(StatusFailed)
+}
+
+func (r *Report) filter(s Status) []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Status == s {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// WriteText writes a summary line followed by one line per unmatched or failed file.
+func (r *Report) WriteText(w io.Writer) error {
+	counts := map[Status]int{}
+	for _, res := range r.Results {
+		counts[res.Status]++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "bulk upload to job %s: %d file(s), %d associated, %d unmatched, %d skipped, %d failed",
+		r.JobID, len(r.Results), counts[StatusAssociated], counts[StatusUnmatched], counts[StatusSkipped], counts[StatusFailed])
+	if n := counts[StatusPlanned]; n > 0 {
+		fmt.Fprintf(&b, ", %d planned", n)
+	}
+	b.WriteString("\n")
+	for _, res := range r.Results {
+		switch res.Status {
+		case StatusUnmatched:
+			reason := "no GPS"
+			if res.HasGPS {
+				reason = "no target within range"
+				if res.Distance > 0 {
+					reason = fmt.Sprintf("nearest target %.1fm away", res.Distance)
+				}
+			}
+			fmt.Fprintf(&b, "unmatched %s photo %s (%s)\n", res.Path, res.PhotoID, reason)
+		case StatusFailed:
+			fmt.Fprintf(&b, "failed    %s: %s\n", res.Path, res.Error)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Uploader uploads and associates photos for one job.
+type Uploader struct {
+	Job *katapultpro.JobScope
+	// MaxDistance is the greatest distance in meters between a photo and the node or section it is
+	// associated with. Zero means DefaultMaxDistance.
+	MaxDistance float64
+	// Concurrency bounds the number of uploads in flight. Zero means DefaultConcurrency.
+	Concurrency int
+	// Main associates photos as the main photo of their target instead of an additional photo.
+	Main bool
+	// DryRun reads and matches files without uploading anything.
+	DryRun bool
+	// OnResult, if set, is called after each file is processed (serially, in completion order).
+	OnResult func(Result)
+	// Manifest is the path of the manifest recording uploads. Empty means DefaultManifest in the
+	// directory being uploaded. Dry runs read it but do not write it.
+	Manifest string
+}
+
+// target is a node or section a photo can be associated with.
+type target struct {
+	nodeID, connectionID, sectionID string
+	lat, lon                        float64
+}
+
+type file struct {
+	index int
+	path  string
+}
+
+// run is the state shared by the files of one Run.
+type run struct {
+	dir      string
+	seen     map[string]bool // Filename and size, and name_date, of the job's photos
+	photos   map[string]bool // IDs of the job's photos
+	targets  []target
+	manifest *manifest
+}
+
+// Run processes every .jpg/.jpeg file under dir.
+func (u *Uploader) Run(ctx context.Context, dir string) (*Report, error) {
+	if u.Job == nil {
+		return nil, errors.New("bulkupload: Uploader.Job is nil")
+	}
+	paths, err := jpegs(dir)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := u.Job.Photos().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bulkupload: list photos: %w", err)
+	}
+	mpath := u.Manifest
+	if mpath == "" {
+		mpath = filepath.Join(dir, DefaultManifest)
+	}
+	m, err := loadManifest(mpath)
+	if err != nil {
+		return nil, err
+	}
+	seen, photos := map[string]bool{}, map[string]bool{}
+	for _, p := range existing {
+		photos[p.ID] = true
+		if p.Filename != "" && p.OriginalSize > 0 {
+			seen[sizeKey(p.Filename, p.OriginalSize)] = true
+		}
+		if p.NameDate != "" {
+			seen[p.NameDate] = true
+		}
+	}
+	targets, err := u.targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st := &run{dir: dir, seen: seen, photos: photos, targets: targets, manifest: m}
+
+	report := &Report{JobID: u.Job.JobID(), Results: make([]Result, len(paths))}
+	work := make(chan file)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	workers := u.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range work {
+				res := u.process(ctx, f.path, st)
+				mu.Lock()
+				report.Results[f.index] = res
+				if u.OnResult != nil {
+					u.OnResult(res)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i, p := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- file{index: i, path: p}
+	}
+	close(work)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func (u *Uploader) process(ctx context.Context, path string, st *run) Result {
+	res := Result{Path: path}
+	fail := func(err error) Result {
+		res.Status, res.Error = StatusFailed, err.Error()
+		return res
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fail(err)
+	}
+	info, err := exif.Decode(bytes.NewReader(data))
+	if err != nil && !errors.Is(err, exif.ErrNoExif) {
+		return fail(err)
+	}
+	base := filepath.Base(path)
+	if info != nil {
+		if !info.Time.IsZero() {
+			res.DateTaken = info.Time.Unix()
+		}
+		res.HasGPS, res.Latitude, res.Longitude = info.HasGPS, info.Latitude, info.Longitude
+	}
+	sum := sha256.Sum256(data)
+	entry := ManifestEntry{JobID: u.Job.JobID(), Path: relPath(st.dir, path), Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])}
+	// A file uploaded by an earlier run but not associated is associated now if a target is in range.
+	if prev, ok := st.manifest.lookup(entry.JobID, entry.Path, entry.Size, entry.SHA256); ok && st.photos[prev.PhotoID] {
+		entry, res.PhotoID = prev, prev.PhotoID
+		if prev.Associated {
+			res.Status = StatusSkipped
+			return res
+		}
+	} else if st.seen[sizeKey(base, int64(len(data)))] || (res.DateTaken != 0 && seenNameDate(st.seen, base, res.DateTaken)) {
+		res.Status = StatusSkipped
+		return res
+	}
+
+	var best *target
+	if res.HasGPS {
+		best, res.Distance = nearest(st.targets, res.Latitude, res.Longitude)
+		if best != nil && res.Distance <= u.maxDistance() {
+			res.NodeID, res.ConnectionID, res.SectionID = best.nodeID, best.connectionID, best.sectionID
+		} else {
+			best = nil
+		}
+	}
+	if res.PhotoID != "" && best == nil {
+		res.Status = StatusSkipped // Uploaded before, and still nothing to associate it with
+		return res
+	}
+	if u.DryRun {
+		res.Status = StatusPlanned
+		return res
+	}
+
+	if res.PhotoID == "" {
+		photo, err := u.Job.Photos().Upload(ctx, bytes.NewReader(data))
+		if err != nil {
+			return fail(fmt.Errorf("upload: %w", err))
+		}
+		res.PhotoID, entry.PhotoID = photo.ID, photo.ID
+		if err := st.manifest.record(entry); err != nil {
+			return fail(fmt.Errorf("uploaded as photo %s, but %w", photo.ID, err))
+		}
+	}
+	if best == nil {
+		res.Status = StatusUnmatched
+		return res
+	}
+	value := katapultpro.PtrPhotoAssociationTrue()
+	if u.Main {
+		value = katapultpro.PtrPhotoAssociationMain()
+	}
+	req := &katapultpro.AssociatePhotoRequest{AssociationValue: value, NodeID: best.nodeID}
+	if best.sectionID != "" {
+		req.ConnectionID, req.SectionID = best.connectionID, best.sectionID
+	}
+	if err := u.Job.Photos().Associate(ctx, res.PhotoID, req); err != nil {
+		return fail(fmt.Errorf("associate: %w", err))
+	}
+	entry.Associated = true
+	if err := st.manifest.record(entry); err != nil {
+		return fail(fmt.Errorf("associated photo %s, but %w", res.PhotoID, err))
+	}
+	res.Status = StatusAssociated
+	return res
+}
+
+func (u *Uploader) maxDistance() float64 {
+	if u.MaxDistance > 0 {
+		return u.MaxDistance
+	}
+	return DefaultMaxDistance
+}
+
+// targets lists the job's nodes and the sections embedded in its connections.
+func (u *Uploader) targets(ctx context.Context) ([]target, error) {
+	nodes, err := u.Job.Nodes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bulkupload: list nodes: %w", err)
+	}
+	conns, err := u.Job.Connections().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("bulkupload: list connections: %w", err)
+	}
+	var out []target
+	for _, n := range nodes {
+		if n.Latitude != 0 || n.Longitude != 0 {
+			out = append(out, target{nodeID: n.ID, lat: n.Latitude, lon: n.Longitude})
+		}
+	}
+	for _, c := range conns {
+		ids := make([]string, 0, len(c.Sections))
+		for id := range c.Sections {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			s := c.Sections[id]
+			if s.Latitude != 0 || s.Longitude != 0 {
+				out = append(out, target{connectionID: c.ID, sectionID: id, lat: s.Latitude, lon: s.Longitude})
+			}
+		}
+	}
+	return out, nil
+}
+
+// nearest returns the closest target and its distance in meters. Ties go to the earlier target,
+// so nodes win over sections at the same position.
+func nearest(targets []target, lat, lon float64) (*target, float64) {
+	var best *target
+	bestDist := math.Inf(1)
+	for i := range targets {
+		if d := distance(lat, lon, targets[i].lat, targets[i].lon); d < bestDist {
+			best, bestDist = &targets[i], d
+		}
+	}
+	if best == nil {
+		return nil, 0
+	}
+	return best, bestDist
+}
+
+// distance returns the great-circle distance in meters between two points.
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371008.8
+	rad := math.Pi / 180
+	dLat, dLon := (lat2-lat1)*rad, (lon2-lon1)*rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// seenNameDate matches name_date ("filename|timestamp"), whose timestamp may be in seconds or milliseconds.
+func seenNameDate(seen map[string]bool, name string, unix int64) bool {
+	return seen[name+"|"+strconv.FormatInt(unix, 10)] || seen[name+"|"+strconv.FormatInt(unix*1000, 10)]
+}
+
+// relPath returns path relative to dir with "/" separators, or path itself if it is not below dir.
+func relPath(dir, path string) string {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func sizeKey(name string, size int64) string {
+	return name + "\x00" + strconv.FormatInt(size, 10)
+}
+
+// jpegs returns the JPEG files under dir, sorted by path.
+func jpegs(dir string) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".jpg", ".jpeg":
+			out = append(out, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bulkupload: %w", err)
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,153 @@
+package bulkupload_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/bulkupload"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/exif"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+func writeJPEG(t *testing.T, dir, name string, info exif.Info) []byte {
+	t.Helper()
+	data := exif.Build(info)
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestUploader_AssociatesNearestAndReportsUnmatched(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Bulk"})
+	pole := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40.0, Longitude: -111.0})
+	far := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40.01, Longitude: -111.0})
+	conn := srv.AddConnection(job.ID, katapultpro.Connection{NodeID1: pole.ID, NodeID2: far.ID})
+	mid := srv.AddSection(job.ID, conn.ID, "", katapultpro.EmbeddedSection{Latitude: 40.005, Longitude: -111.0})
+
+	taken := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	writeJPEG(t, dir, "a/IMG_0001.JPG", exif.Info{Time: taken, HasGPS: true, Latitude: 40.00005, Longitude: -111.0}) // ~5m from pole
+	writeJPEG(t, dir, "a/IMG_0002.jpg", exif.Info{Time: taken, HasGPS: true, Latitude: 40.00502, Longitude: -111.0}) // ~2m from section
+	writeJPEG(t, dir, "b/IMG_0003.jpeg", exif.Info{Time: taken, HasGPS: true, Latitude: 40.02, Longitude: -111.0})   // ~1.1km from far
+	writeJPEG(t, dir, "b/IMG_0004.jpg", exif.Info{Time: taken})                                                      // no GPS
+	dup := writeJPEG(t, dir, "b/IMG_0005.jpg", exif.Info{Time: taken.Add(time.Minute)})
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv.AddPhoto(job.ID, katapultpro.Photo{Filename: "IMG_0005.jpg", OriginalSize: int64(len(dup))})
+
+	var progress int
+	u := &bulkupload.Uploader{Job: srv.Client().Job(job.ID), Concurrency: 2, OnResult: func(bulkupload.Result) { progress++ }}
+	report, err := u.Run(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != 5 || progress != 5 {
+		t.Fatalf("got %d results, %d progress calls", len(report.Results), progress)
+	}
+	want := []bulkupload.Status{bulkupload.StatusAssociated, bulkupload.StatusAssociated, bulkupload.StatusUnmatched, bulkupload.StatusUnmatched, bulkupload.StatusSkipped}
+	for i, res := range report.Results {
+		if res.Status != want[i] {
+			t.Errorf("%s: status %s (%s), want %s", res.Path, res.Status, res.Error, want[i])
+		}
+	}
+	if r := report.Results[0]; r.NodeID != pole.ID || r.DateTaken != taken.Unix() {
+		t.Errorf("first photo: %+v", r)
+	}
+	if r := report.Results[1]; r.SectionID != mid || r.ConnectionID != conn.ID {
+		t.Errorf("second photo: %+v", r)
+	}
+
+	client := srv.Client()
+	photo, err := client.Job(job.ID).Photos().Get(context.Background(), report.Results[0].PhotoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.AssociatedLocations[pole.ID] == "" {
+		t.Errorf("photo not associated to pole: %+v", photo.AssociatedLocations)
+	}
+	if got := len(report.Unmatched()); got != 2 {
+		t.Errorf("unmatched = %d", got)
+	}
+
+	var text bytes.Buffer
+	_ = report.WriteText(&text)
+	if !strings.Contains(text.String(), "2 associated, 2 unmatched, 1 skipped, 0 failed") || !strings.Contains(text.String(), "(no GPS)") {
+		t.Errorf("report text:\n%s", text.String())
+	}
+}
+
+func TestUploader_SecondRunSkipsEverything(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Bulk"})
+	srv.AddNode(job.ID, katapultpro.Node{Latitude: 40, Longitude: -111})
+	dir := t.TempDir()
+	taken := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	writeJPEG(t, dir, "IMG_0001.jpg", exif.Info{Time: taken, HasGPS: true, Latitude: 40, Longitude: -111})
+	writeJPEG(t, dir, "IMG_0002.jpg", exif.Info{Time: taken.Add(time.Minute)}) // unmatched
+	writeJPEG(t, dir, "sub/IMG_0003.jpg", exif.Info{Time: taken.Add(2 * time.Minute), HasGPS: true, Latitude: 40, Longitude: -111})
+	ctx := context.Background()
+	u := &bulkupload.Uploader{Job: srv.Client().Job(job.ID)}
+
+	first, err := u.Run(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range first.Results {
+		if r.Status == bulkupload.StatusSkipped || r.Status == bulkupload.StatusFailed {
+			t.Fatalf("first run: %+v", r)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, bulkupload.DefaultManifest)); err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+
+	second, err := u.Run(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range second.Results {
+		if r.Status != bulkupload.StatusSkipped || r.PhotoID != first.Results[i].PhotoID {
+			t.Errorf("second run: %+v", r)
+		}
+	}
+	photos, err := srv.Client().Job(job.ID).Photos().List(ctx)
+	if err != nil || len(photos) != 3 {
+		t.Errorf("job has %d photos (%v), want 3", len(photos), err)
+	}
+}
+
+func TestUploader_DryRunUploadsNothing(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Bulk"})
+	node := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40, Longitude: -111})
+	dir := t.TempDir()
+	writeJPEG(t, dir, "IMG.jpg", exif.Info{HasGPS: true, Latitude: 40, Longitude: -111})
+
+	report, err := (&bulkupload.Uploader{Job: srv.Client().Job(job.ID), DryRun: true}).Run(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := report.Results[0]; r.Status != bulkupload.StatusPlanned || r.NodeID != node.ID {
+		t.Errorf("got %+v", r)
+	}
+	for _, req := range srv.Requests() {
+		if req.Method != "GET" {
+			t.Errorf("dry run sent %s %s", req.Method, req.Path)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Package cache caches GET responses for a Katapult Pro client, so dashboards that list the same
+// job's nodes and connections over and over spend fewer API tokens.
+//
+// A Cache is a katapultpro.Doer that wraps another (usually the *katapultpro.Client), so it drives
+// the domain clients through katapultpro.NewJobScope:
+//
+//	c := cache.New(client, cache.NewLRU(1000), cache.WithTTL(2*time.Minute))
+//	job := katapultpro.NewJobScope(c, "job-123")
+//	nodes, err := job.Nodes().List(ctx) // from the API
+//	nodes, err = job.Nodes().List(ctx)  // from the cache
+//	_, err = job.Nodes().Update(ctx, nodeID, req, nil) // invalidates what it changed
+//	fmt.Printf("%.0f%% hits\n", 100*c.Stats().HitRatio())
+//
+// Entries expire after the TTL; there are no ETags to revalidate them with. Writes made through the
+// Cache invalidate the entries they affect as soon as they succeed: the entity written and
+// everything below it, and the lists and job above it. Deleting a node, or updating its
+// coordinates, also invalidates the job's connections. Writes made elsewhere (another client, the
+// web app) are only seen once entries expire, or after Invalidate.
+//
+// Store is the storage behind a Cache: LRU keeps entries in memory, and Disk keeps them in files.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// DefaultTTL is how long entries are kept unless WithTTL says otherwise.
+const DefaultTTL = time.Minute
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithTTL sets how long responses are cached. Zero or less keeps the default.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithClock sets the clock used for expiry, for tests.
+func WithClock(now func() time.Time) Option {
+	return func(c *Cache) {
+		if now != nil {
+			c.now = now
+		}
+	}
+}
+
+// Cache is a katapultpro.Doer that caches GET responses from next in a Store. It is safe for
+// concurrent use if next and the Store are.
+type Cache struct {
+	next  katapultpro.Doer
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+
+	hits, misses, invalidated, storeErrors atomic.Int64
+}
+
+var _ katapultpro.Doer = (*Cache)(nil)
+
+// New returns a Cache that sends requests to next and keeps responses in store.
+func New(next katapultpro.Doer, store Store, opts ...Option) *Cache {
+	c := &Cache{next: next, store: store, ttl: DefaultTTL, now: time.Now}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Stats are a Cache's counters since it was created.
+type Stats struct {
+	Hits        int64 `json:"hits"`
+	Misses      int64 `json:"misses"`
+	Invalidated int64 `json:"invalidated"`  // Entries removed by writes and Invalidate
+	StoreErrors int64 `json:"store_errors"` // Store failures; the request went to the API instead
+}
+
+// HitRatio returns the fraction of cacheable requests served from the cache, or 0 before any.
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Stats returns the cache's counters.
+func (c *Cache) Stats() Stats {
+	return Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Invalidated: c.invalidated.Load(), StoreErrors: c.storeErrors.Load()}
+}
+
+// Do implements katapultpro.Doer. GET requests are served from the store while fresh; other
+// requests are sent on, and invalidate affected entries when they succeed.
+func (c *Cache) Do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	if method != http.MethodGet {
+		if err := c.next.Do(ctx, method, path, query, body, out); err != nil {
+			return err
+		}
+		c.invalidate(ctx, invalidations(method, path, body))
+		return nil
+	}
+	key := strings.TrimPrefix(path, "/") + "?" + query.Encode()
+	e, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.storeErrors.Add(1)
+	}
+	if ok && c.now().Before(e.Expires) {
+		c.hits.Add(1)
+		return decode(e.Data, out)
+	}
+	c.misses.Add(1)
+	var raw json.RawMessage
+	if err := c.next.Do(ctx, method, path, query, body, &raw); err != nil {
+		return err
+	}
+	if err := c.store.Set(ctx, key, Entry{Data: raw, Expires: c.now().Add(c.ttl)}); err != nil {
+		c.storeErrors.Add(1)
+	}
+	return decode(raw, out)
+}
+
+func decode(data json.RawMessage, out any) error {
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// DoWithBody implements katapultpro.Doer. Raw-body requests are uploads, so they are never cached
+// and invalidate like other writes.
+func (c *Cache) DoWithBody(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	if err := c.next.DoWithBody(ctx, method, path, query, contentType, body, out); err != nil {
+		return err
+	}
+	if method != http.MethodGet {
+		c.invalidate(ctx, invalidations(method, path, nil))
+	}
+	return nil
+}
+
+// Invalidate removes the entries for the given paths (with any query) and everything below them,
+// such as "v3/jobs/job-123" for a whole job after it was changed outside this Cache.
+func (c *Cache) Invalidate(ctx context.Context, paths ...string) error {
+	var prefixes []string
+	for _, p := range paths {
+		p = strings.Trim(p, "/")
+		prefixes = append(prefixes, p+"?", p+"/")
+	}
+	return c.deletePrefixes(ctx, prefixes)
+}
+
+func (c *Cache) invalidate(ctx context.Context, prefixes []string) {
+	if err := c.deletePrefixes(ctx, prefixes); err != nil {
+		c.storeErrors.Add(1)
+	}
+}
+
+func (c *Cache) deletePrefixes(ctx context.Context, prefixes []string) error {
+	for _, p := range prefixes {
+		n, err := c.store.DeletePrefix(ctx, p)
+		c.invalidated.Add(int64(n))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invalidations returns the key prefixes a successful write to path makes stale: the path itself
+// and everything below it, each path above it up to the job (without what is below them), and
+// related lists. Writes to connections and photo uploads and associations also clear the job-level
+// section routes.
+func invalidations(method, path string, body any) []string {
+	path = strings.Trim(path, "/")
+	prefixes := []string{path + "?", path + "/"}
+	segs := strings.Split(path, "/")
+	if len(segs) < 3 || segs[0] != "v3" || segs[1] != "jobs" {
+		return prefixes
+	}
+	for i := len(segs) - 1; i >= 3; i-- {
+		prefixes = append(prefixes, strings.Join(segs[:i], "/")+"?")
+	}
+	job := strings.Join(segs[:3], "/")
+	switch {
+	case len(segs) == 3 || segs[3] == "status":
+		// The job list shows names, metadata, and status.
+		prefixes = append(prefixes, "v3/jobs?")
+	case len(segs) == 5 && segs[3] == "nodes" && (method == http.MethodDelete || movesNode(body)):
+		// Connections hold their nodes' positions.
+		prefixes = append(prefixes, job+"/connections")
+	case len(segs) == 6 && segs[3] == "photos" && segs[5] == "associate":
+		// Associations are stored on the nodes and sections.
+		prefixes = append(prefixes, job+"/nodes", job+"/connections")
+	case segs[len(segs)-1] == "photos":
+		// Photos uploaded to a node or section also appear in the job's photo list.
+		prefixes = append(prefixes, job+"/photos?")
+	}
+	if len(segs) > 3 && (segs[3] == "connections" || segs[3] == "photos" && (len(segs) == 4 || segs[len(segs)-1] == "associate")) {
+		// The job-level section routes show the sections of every connection, with their photos.
+		prefixes = append(prefixes, job+"/sections")
+	}
+	return prefixes
+}
+
+// movesNode reports whether a node write sets its coordinates.
+func movesNode(body any) bool {
+	if body == nil {
+		return false
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return true // Can't tell; assume it does
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(b, &fields) != nil {
+		return true
+	}
+	_, lat := fields["latitude"]
+	_, lng := fields["longitude"]
+	return lat || lng
+}
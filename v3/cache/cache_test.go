@@ -0,0 +1,184 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/cache"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+// countingDoer counts the GET requests that reach the API.
+type countingDoer struct {
+	katapultpro.Doer
+	gets map[string]int
+}
+
+func (d *countingDoer) Do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	if method == "GET" {
+		d.gets[path]++
+	}
+	return d.Doer.Do(ctx, method, path, query, body, out)
+}
+
+func (d *countingDoer) DoWithBody(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
+	return d.Doer.DoWithBody(ctx, method, path, query, contentType, body, out)
+}
+
+func TestCache(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Cached"})
+	n1 := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40, Longitude: -80})
+	n2 := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40.001, Longitude: -80})
+	srv.AddConnection(job.ID, katapultpro.Connection{NodeID1: n1.ID, NodeID2: n2.ID})
+	ctx := context.Background()
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	api := &countingDoer{Doer: srv.Client(), gets: map[string]int{}}
+	c := cache.New(api, cache.NewLRU(100), cache.WithTTL(time.Minute), cache.WithClock(func() time.Time { return now }))
+	scope := katapultpro.NewJobScope(c, job.ID)
+	nodesPath, connsPath := "v3/jobs/"+job.ID+"/nodes", "v3/jobs/"+job.ID+"/connections"
+
+	list := func() {
+		t.Helper()
+		if nodes, err := scope.Nodes().List(ctx); err != nil || len(nodes) != 2 {
+			t.Fatalf("nodes = %v, %v", nodes, err)
+		}
+		if conns, err := scope.Connections().List(ctx); err != nil || len(conns) != 1 {
+			t.Fatalf("connections = %v, %v", conns, err)
+		}
+	}
+	list()
+	list()
+	if api.gets[nodesPath] != 1 || api.gets[connsPath] != 1 {
+		t.Fatalf("gets = %v", api.gets)
+	}
+
+	// Attribute changes leave connections cached; moving a node does not.
+	if _, err := scope.Nodes().Update(ctx, n1.ID, &katapultpro.UpdateNodeRequest{AddAttributes: map[string]interface{}{"pole_tag": "T-1"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	list()
+	if api.gets[nodesPath] != 2 || api.gets[connsPath] != 1 {
+		t.Fatalf("after attribute update: gets = %v", api.gets)
+	}
+	if _, err := scope.Nodes().Update(ctx, n1.ID, &katapultpro.UpdateNodeRequest{Latitude: 40.0005}, nil); err != nil {
+		t.Fatal(err)
+	}
+	list()
+	if api.gets[nodesPath] != 3 || api.gets[connsPath] != 2 {
+		t.Fatalf("after move: gets = %v", api.gets)
+	}
+
+	now = now.Add(2 * time.Minute)
+	list()
+	if api.gets[nodesPath] != 4 {
+		t.Fatalf("after expiry: gets = %v", api.gets)
+	}
+
+	if err := c.Invalidate(ctx, "v3/jobs/"+job.ID); err != nil {
+		t.Fatal(err)
+	}
+	list()
+	st := c.Stats()
+	if st.Hits != 3 || st.Misses != 9 || st.Invalidated == 0 || st.StoreErrors != 0 {
+		t.Errorf("stats = %+v", st)
+	}
+	if r := st.HitRatio(); r != 0.25 {
+		t.Errorf("hit ratio = %v", r)
+	}
+}
+
+func TestCache_JobLevelSections(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Sections"})
+	n1 := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40, Longitude: -80})
+	n2 := srv.AddNode(job.ID, katapultpro.Node{Latitude: 40.001, Longitude: -80})
+	conn := srv.AddConnection(job.ID, katapultpro.Connection{NodeID1: n1.ID, NodeID2: n2.ID})
+	photo := srv.AddPhoto(job.ID, katapultpro.Photo{})
+	ctx := context.Background()
+
+	c := cache.New(srv.Client(), cache.NewLRU(100), cache.WithTTL(time.Minute))
+	scope := katapultpro.NewJobScope(c, job.ID)
+	if secs, err := scope.Sections().List(ctx); err != nil || len(secs) != 0 {
+		t.Fatalf("sections = %v, %v", secs, err)
+	}
+
+	sec, err := scope.Connections().Sections(conn.ID).Create(ctx, &katapultpro.CreateSectionRequest{Latitude: 40.0005, Longitude: -80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secs, err := scope.Sections().List(ctx); err != nil || len(secs) != 1 {
+		t.Fatalf("sections after create = %v, %v", secs, err)
+	}
+	if _, err := scope.Sections().Get(ctx, sec.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &katapultpro.AssociatePhotoRequest{SectionID: sec.ID, ConnectionID: conn.ID, AssociationValue: katapultpro.PtrPhotoAssociationTrue()}
+	if err := scope.Photos().Associate(ctx, photo.ID, req); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := scope.Sections().Get(ctx, sec.ID); err != nil || len(got.Photos) != 1 {
+		t.Fatalf("section after associate = %+v, %v", got, err)
+	}
+
+	if err := scope.Connections().Sections(conn.ID).Delete(ctx, sec.ID); err != nil {
+		t.Fatal(err)
+	}
+	if secs, err := scope.Sections().List(ctx); err != nil || len(secs) != 0 {
+		t.Fatalf("sections after delete = %v, %v", secs, err)
+	}
+}
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	l := cache.NewLRU(2)
+	_ = l.Set(ctx, "a?", cache.Entry{Data: json.RawMessage(`1`)})
+	_ = l.Set(ctx, "b?", cache.Entry{Data: json.RawMessage(`2`)})
+	_, _, _ = l.Get(ctx, "a?")
+	_ = l.Set(ctx, "c?", cache.Entry{Data: json.RawMessage(`3`)})
+	if _, ok, _ := l.Get(ctx, "b?"); ok {
+		t.Error("least recently used entry not evicted")
+	}
+	if e, ok, _ := l.Get(ctx, "a?"); !ok || string(e.Data) != "1" || l.Len() != 2 {
+		t.Errorf("a = %s, %v; len %d", e.Data, ok, l.Len())
+	}
+}
+
+func TestDisk(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	d := &cache.Disk{Dir: dir}
+	exp := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, key := range []string{"v3/jobs/j1/nodes?", "v3/jobs/j1/nodes/n1?", "v3/jobs/j2/nodes?"} {
+		if err := d.Set(ctx, key, cache.Entry{Data: json.RawMessage(`{"k":"` + key + `"}`), Expires: exp}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d = &cache.Disk{Dir: dir} // A fresh instance sees the same entries
+	e, ok, err := d.Get(ctx, "v3/jobs/j1/nodes?")
+	if err != nil || !ok || string(e.Data) != `{"k":"v3/jobs/j1/nodes?"}` || !e.Expires.Equal(exp) {
+		t.Fatalf("get = %+v, %v, %v", e, ok, err)
+	}
+	if n, err := d.DeletePrefix(ctx, "v3/jobs/j1/"); err != nil || n != 2 {
+		t.Fatalf("delete = %d, %v", n, err)
+	}
+	if _, ok, _ := d.Get(ctx, "v3/jobs/j1/nodes/n1?"); ok {
+		t.Error("deleted entry still there")
+	}
+	if _, ok, _ := d.Get(ctx, "v3/jobs/j2/nodes?"); !ok {
+		t.Error("other job's entry deleted")
+	}
+	if _, ok, err := (&cache.Disk{Dir: dir + "/missing"}).Get(ctx, "x?"); ok || err != nil {
+		t.Errorf("missing dir: %v, %v", ok, err)
+	}
+}
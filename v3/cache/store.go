@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a cached response: the envelope's data field as returned by the API.
+type Entry struct {
+	Data    json.RawMessage `json:"data"`
+	Expires time.Time       `json:"expires"`
+}
+
+// Store holds cache entries by key. Keys are request paths followed by "?" and the encoded query,
+// so the entries for a path, with any query, share the prefix path+"?".
+type Store interface {
+	// Get returns the entry for key, if there is one. Expired entries may be returned; the Cache
+	// checks expiry itself.
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	// Set inserts or replaces the entry for key.
+	Set(ctx context.Context, key string, e Entry) error
+	// DeletePrefix removes every entry whose key starts with prefix and returns how many it removed.
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// LRU is an in-memory Store that holds at most a fixed number of entries, evicting the least
+// recently used. It is safe for concurrent use.
+type LRU struct {
+	max int
+
+	mu    sync.Mutex
+	order *list.List // Front is most recently used; values are *lruItem
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry Entry
+}
+
+// NewLRU returns an LRU holding at most max entries. A max of zero or less means no limit.
+func NewLRU(max int) *LRU {
+	return &LRU{max: max, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get implements Store.
+func (l *LRU) Get(_ context.Context, key string) (Entry, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true, nil
+}
+
+// Set implements Store.
+func (l *LRU) Set(_ context.Context, key string, e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruItem).entry = e
+		l.order.MoveToFront(el)
+		return nil
+	}
+	l.items[key] = l.order.PushFront(&lruItem{key: key, entry: e})
+	for l.max > 0 && l.order.Len() > l.max {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+// DeletePrefix implements Store.
+func (l *LRU) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for key, el := range l.items {
+		if strings.HasPrefix(key, prefix) {
+			l.order.Remove(el)
+			delete(l.items, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Len returns the number of entries held.
+func (l *LRU) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// Disk is a Store that keeps one JSON file per entry in Dir, so a cache survives restarts and can
+// be shared by processes on one machine. The directory is created on first write. DeletePrefix
+// reads every file, so Disk suits caches of hundreds of entries rather than millions.
+type Disk struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+// diskEntry is the file format; the key is kept so DeletePrefix can match it.
+type diskEntry struct {
+	Key string `json:"key"`
+	Entry
+}
+
+func (d *Disk) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.Dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// Get implements Store. Unreadable files are treated as missing.
+func (d *Disk) Get(_ context.Context, key string) (Entry, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	b, err := os.ReadFile(d.file(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("cache: %w", err)
+	}
+	var e diskEntry
+	if json.Unmarshal(b, &e) != nil || e.Key != key {
+		return Entry{}, false, nil
+	}
+	return e.Entry, true, nil
+}
+
+// Set implements Store. The file is written to a temporary name and renamed into place.
+func (d *Disk) Set(_ context.Context, key string, e Entry) error {
+	b, err := json.Marshal(diskEntry{Key: key, Entry: e})
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.MkdirAll(d.Dir, 0o755); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(d.Dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), d.file(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("cache: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix implements Store.
+func (d *Disk) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	names, err := filepath.Glob(filepath.Join(d.Dir, "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("cache: %w", err)
+	}
+	n := 0
+	for _, name := range names {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		var e diskEntry
+		if json.Unmarshal(b, &e) == nil && !strings.HasPrefix(e.Key, prefix) {
+			continue
+		}
+		// Matching and unreadable entries are removed alike.
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return n, fmt.Errorf("cache: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,316 @@
+// Package calibration computes heights from positions in calibrated photos.
+//
+// A photo of a pole maps each height on the pole to a vertical position in the image. For a
+// pinhole camera that mapping is projective:
+//
+//	height = (A·y + B) / (C·y + 1)
+//
+// where y is the PercentY of a PixelSelection (0 at the top of the image, 100 at the bottom) and
+// height is in inches. FitAnchors recovers A, B, and C from calibration anchors (points of known height);
+// with only two anchors, or when the projective fit is unstable, C is fixed at 0 (a linear model).
+//
+//	fit, err := calibration.FitAnchors(anchors)
+//	h, err := fit.Model.Height(katapultpro.PixelSelection{PercentX: 50, PercentY: 31.5})
+//	for _, r := range fit.Residuals { fmt.Println(r.AnchorID, r.Error) }
+//
+// Use CheckElements to compare the API's _measured_height values with the model, and Height to
+// compute heights for new elements before writing them.
+package calibration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// ErrTooFewAnchors is returned by the fit functions when fewer than two usable anchors are given.
+var ErrTooFewAnchors = errors.New("calibration: at least two anchors at different heights are needed")
+
+// ErrNoPixelSelection is returned when an element or anchor has no pixel selection.
+var ErrNoPixelSelection = errors.New("calibration: no pixel selection")
+
+// Kind is the form of a Model.
+type Kind string
+
+const (
+	KindLinear     Kind = "linear"     // C is 0: height is linear in y.
+	KindProjective Kind = "projective" // Full perspective model.
+)
+
+// Model maps an image position to a height in inches: (A·y + B) / (C·y + 1), with y in percent.
+type Model struct {
+	A, B, C float64
+	Kind    Kind
+}
+
+// HeightAt returns the height at vertical position y (PercentY). It fails for positions on or
+// beyond the model's horizon, where the denominator is not positive.
+func (m *Model) HeightAt(y float64) (katapultpro.Height, error) {
+	d := m.C*y + 1
+	if d <= 1e-9 {
+		return 0, fmt.Errorf("calibration: y=%g is beyond the model's horizon", y)
+	}
+	return katapultpro.Inches((m.A*y + m.B) / d), nil
+}
+
+// Height returns the height at a pixel selection. Only PercentY is used.
+func (m *Model) Height(p katapultpro.PixelSelection) (katapultpro.Height, error) {
+	return m.HeightAt(p.PercentY)
+}
+
+// PercentY returns the vertical position (PercentY) of height h, the inverse of HeightAt. Use it to
+// place a new element at a known height.
+func (m *Model) PercentY(h katapultpro.Height) (float64, error) {
+	den := m.A - m.C*h.Inches()
+	if math.Abs(den) < 1e-12 {
+		return 0, fmt.Errorf("calibration: height %s is at the model's horizon", h)
+	}
+	y := (h.Inches() - m.B) / den
+	if m.C*y+1 <= 1e-9 {
+		return 0, fmt.Errorf("calibration: height %s is not visible in the model", h)
+	}
+	return y, nil
+}
+
+// Anchor is a point of known height.
+type Anchor struct {
+	ID     string
+	Y      float64 // PercentY
+	Height katapultpro.Height
+}
+
+// Residual compares a known height with the model's height at the same position.
+type Residual struct {
+	AnchorID string
+	Y        float64
+	Known    katapultpro.Height
+	Fitted   katapultpro.Height
+	Error    katapultpro.Height // Fitted - Known
+}
+
+// Fit is a fitted model and how well it matches the anchors it was fitted to.
+type Fit struct {
+	Model     Model
+	Residuals []Residual
+	RMS       katapultpro.Height // Root mean square of the residual errors.
+	MaxError  katapultpro.Height // Largest absolute residual error.
+}
+
+// FitPoints fits a model to the anchors by least squares. With three or more anchors the projective
+// model is tried first; it is kept only if it is monotonic over the whole image (0–100), otherwise
+// the linear model is used. The linear model is exact for two anchors; with three anchors the
+// projective model is exact, so check the residuals of fits with four or more.
+func FitPoints(anchors []Anchor) (*Fit, error) {
+	distinct := map[float64]bool{}
+	for _, a := range anchors {
+		distinct[a.Y] = true
+	}
+	if len(distinct) < 2 {
+		return nil, ErrTooFewAnchors
+	}
+	var model *Model
+	if len(distinct) >= 3 {
+		model = fitProjective(anchors)
+	}
+	if model == nil {
+		model = fitLinear(anchors)
+	}
+	if model == nil {
+		return nil, ErrTooFewAnchors
+	}
+	return Evaluate(model, anchors)
+}
+
+// Evaluate computes the residuals of model at the anchors. Use it to check a model obtained
+// elsewhere, such as FromStickAlign, against a photo's anchors.
+func Evaluate(model *Model, anchors []Anchor) (*Fit, error) {
+	fit := &Fit{Model: *model}
+	var sum float64
+	for _, a := range anchors {
+		h, err := model.HeightAt(a.Y)
+		if err != nil {
+			return nil, fmt.Errorf("anchor %s: %w", a.ID, err)
+		}
+		e := h - a.Height
+		fit.Residuals = append(fit.Residuals, Residual{AnchorID: a.ID, Y: a.Y, Known: a.Height, Fitted: h, Error: e})
+		sum += e.Inches() * e.Inches()
+		if math.Abs(e.Inches()) > fit.MaxError.Inches() {
+			fit.MaxError = katapultpro.Inches(math.Abs(e.Inches()))
+		}
+	}
+	if len(anchors) > 0 {
+		fit.RMS = katapultpro.Inches(math.Sqrt(sum / float64(len(anchors))))
+	}
+	return fit, nil
+}
+
+// FitAnchors fits a model to a photo's calibration anchors. Anchors without a pixel selection are
+// ignored.
+func FitAnchors(anchors []katapultpro.PhotoCalibrationAnchor) (*Fit, error) {
+	var pts []Anchor
+	for _, a := range anchors {
+		if len(a.PixelSelection) == 0 {
+			continue
+		}
+		pts = append(pts, Anchor{ID: a.ID, Y: a.PixelSelection[0].PercentY, Height: a.HeightValue()})
+	}
+	return FitPoints(pts)
+}
+
+// FitPhoto fits a model to the anchor calibration stored in a photo's photofirst_data. If the
+// photo has fewer than two anchors but has a stick alignment, the stick alignment model is
+// returned instead, with residuals for the remaining anchor, if any.
+func FitPhoto(photo *katapultpro.Photo) (*Fit, error) {
+	var pts []Anchor
+	if photo.PhotofirstData != nil {
+		for id, a := range photo.PhotofirstData.AnchorCalibration {
+			if len(a.PixelSelection) == 0 {
+				continue
+			}
+			pts = append(pts, Anchor{ID: id, Y: a.PixelSelection[0].PercentY, Height: a.HeightValue()})
+		}
+	}
+	sort.Slice(pts, func(i, j int) bool { return pts[i].ID < pts[j].ID })
+	fit, err := FitPoints(pts)
+	if errors.Is(err, ErrTooFewAnchors) && photo.StickAlign != nil {
+		model, serr := FromStickAlign(photo.StickAlign)
+		if serr != nil {
+			return nil, serr
+		}
+		return Evaluate(model, pts)
+	}
+	return fit, err
+}
+
+// FromStickAlign builds a model from a photo's stick alignment coefficients.
+//
+// The API does not document the stick alignment fields. This function assumes A, B, and C are the
+// coefficients of the projective model in image pixels (y from 0 to StickAlign.Height) giving
+// decimal feet: feet = (A·y + B) / (C·y + 1). When Height is 0, y is taken as PercentY. Before
+// relying on the result, compare it with the photo's anchors using Evaluate.
+func FromStickAlign(sa *katapultpro.StickAlign) (*Model, error) {
+	if sa == nil || (sa.A == 0 && sa.B == 0) {
+		return nil, errors.New("calibration: photo has no stick alignment")
+	}
+	scale := 1.0
+	if sa.Height > 0 {
+		scale = float64(sa.Height) / 100
+	}
+	m := &Model{A: 12 * sa.A * scale, B: 12 * sa.B, C: sa.C * scale, Kind: KindProjective}
+	if sa.C == 0 {
+		m.Kind = KindLinear
+	}
+	return m, nil
+}
+
+// ElementCheck compares an element's measured height with the model.
+type ElementCheck struct {
+	ElementID string
+	Measured  katapultpro.Height
+	Computed  katapultpro.Height
+	Diff      katapultpro.Height // Measured - Computed
+	OK        bool               // |Diff| <= tolerance
+	Err       error              // Set when the height could not be computed; OK is false.
+}
+
+// CheckElements computes the height of each element with a pixel selection and a measured height
+// and compares it with the element's _measured_height.
+func (m *Model) CheckElements(elements []katapultpro.PhotoElement, tolerance katapultpro.Height) []ElementCheck {
+	var out []ElementCheck
+	for _, e := range elements {
+		if e.MeasuredHeight == 0 || len(e.PixelSelection) == 0 {
+			continue
+		}
+		c := ElementCheck{ElementID: e.ID, Measured: e.MeasuredHeightValue()}
+		c.Computed, c.Err = m.Height(e.PixelSelection[0])
+		if c.Err == nil {
+			c.Diff = c.Measured - c.Computed
+			c.OK = math.Abs(c.Diff.Inches()) <= math.Abs(tolerance.Inches())
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// ElementHeight returns the model's height for an element's (first) pixel selection.
+func (m *Model) ElementHeight(e katapultpro.PhotoElement) (katapultpro.Height, error) {
+	if len(e.PixelSelection) == 0 {
+		return 0, ErrNoPixelSelection
+	}
+	return m.Height(e.PixelSelection[0])
+}
+
+// fitLinear solves height = A·y + B by least squares.
+func fitLinear(anchors []Anchor) *Model {
+	var n, sy, sh, syy, syh float64
+	for _, a := range anchors {
+		y, h := a.Y, a.Height.Inches()
+		n++
+		sy += y
+		sh += h
+		syy += y * y
+		syh += y * h
+	}
+	den := n*syy - sy*sy
+	if math.Abs(den) < 1e-12 {
+		return nil
+	}
+	a := (n*syh - sy*sh) / den
+	return &Model{A: a, B: (sh - a*sy) / n, Kind: KindLinear}
+}
+
+// fitProjective solves A·y + B - C·y·h = h by linear least squares. It returns nil if the system
+// is singular or the fitted model has a horizon or turns back within the image.
+func fitProjective(anchors []Anchor) *Model {
+	var m [3][4]float64
+	for _, a := range anchors {
+		y, h := a.Y, a.Height.Inches()
+		row := [3]float64{y, 1, -y * h}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				m[i][j] += row[i] * row[j]
+			}
+			m[i][3] += row[i] * h
+		}
+	}
+	x, ok := solve3(m)
+	if !ok {
+		return nil
+	}
+	model := &Model{A: x[0], B: x[1], C: x[2], Kind: KindProjective}
+	// The denominator is 1 at the top of the image; it must stay positive down to the bottom. The
+	// derivative (A - B·C)/(C·y+1)² then keeps one sign, so the model is monotonic.
+	if model.C*100+1 <= 1e-9 {
+		return nil
+	}
+	return model
+}
+
+// solve3 solves a 3x3 augmented system by Gaussian elimination with partial pivoting.
+func solve3(m [3][4]float64) ([3]float64, bool) {
+	for col := 0; col < 3; col++ {
+		p := col
+		for r := col + 1; r < 3; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[p][col]) {
+				p = r
+			}
+		}
+		if math.Abs(m[p][col]) < 1e-12 {
+			return [3]float64{}, false
+		}
+		m[col], m[p] = m[p], m[col]
+		for r := 0; r < 3; r++ {
+			if r == col {
+				continue
+			}
+			f := m[r][col] / m[col][col]
+			for c := col; c < 4; c++ {
+				m[r][c] -= f * m[col][c]
+			}
+		}
+	}
+	return [3]float64{m[0][3] / m[0][0], m[1][3] / m[1][1], m[2][3] / m[2][2]}, true
+}
@@ -0,0 +1,111 @@
+package calibration_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/calibration"
+)
+
+// camera is a known projective model used to generate anchors: 50 ft at the top of the image
+// down to 0 ft near the bottom, with perspective.
+var camera = calibration.Model{A: -8.2, B: 600, C: 0.0025, Kind: calibration.KindProjective}
+
+func anchorAt(t *testing.T, id string, y float64) katapultpro.PhotoCalibrationAnchor {
+	t.Helper()
+	h, err := camera.HeightAt(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return katapultpro.PhotoCalibrationAnchor{ID: id, Height: h.Feet(), PixelSelection: []katapultpro.PixelSelection{{PercentX: 50, PercentY: y}}}
+}
+
+func near(a, b katapultpro.Height, tol float64) bool { return math.Abs(a.Inches()-b.Inches()) <= tol }
+
+func TestFitAnchors_Projective(t *testing.T) {
+	anchors := []katapultpro.PhotoCalibrationAnchor{anchorAt(t, "a1", 10), anchorAt(t, "a2", 40), anchorAt(t, "a3", 70), anchorAt(t, "a4", 90)}
+	fit, err := calibration.FitAnchors(anchors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Model.Kind != calibration.KindProjective || fit.MaxError.Inches() > 1e-6 || len(fit.Residuals) != 4 {
+		t.Fatalf("fit = %+v", fit)
+	}
+	want, _ := camera.HeightAt(55)
+	got, err := fit.Model.Height(katapultpro.PixelSelection{PercentY: 55})
+	if err != nil || !near(got, want, 1e-6) {
+		t.Errorf("height at 55%% = %v, %v; want %v", got, err, want)
+	}
+	y, err := fit.Model.PercentY(want)
+	if err != nil || math.Abs(y-55) > 1e-6 {
+		t.Errorf("PercentY(%v) = %v, %v", want, y, err)
+	}
+
+	// A mis-entered anchor shows up in the residuals.
+	anchors = append(anchors, anchorAt(t, "bad", 50))
+	anchors[4].Height += 1.5
+	fit, err = calibration.FitAnchors(anchors)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worst := fit.Residuals[0]
+	for _, r := range fit.Residuals {
+		if math.Abs(r.Error.Inches()) > math.Abs(worst.Error.Inches()) {
+			worst = r
+		}
+	}
+	if worst.AnchorID != "bad" || fit.RMS.Inches() == 0 || fit.MaxError != katapultpro.Inches(math.Abs(worst.Error.Inches())) {
+		t.Errorf("worst residual %+v, rms %v, max %v", worst, fit.RMS, fit.MaxError)
+	}
+}
+
+func TestFitAnchors_LinearAndTooFew(t *testing.T) {
+	fit, err := calibration.FitAnchors([]katapultpro.PhotoCalibrationAnchor{
+		{ID: "top", Height: 30, PixelSelection: []katapultpro.PixelSelection{{PercentY: 20}}},
+		{ID: "bottom", Height: 10, PixelSelection: []katapultpro.PixelSelection{{PercentY: 80}}},
+		{ID: "unplaced", Height: 99},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fit.Model.Kind != calibration.KindLinear || len(fit.Residuals) != 2 {
+		t.Fatalf("fit = %+v", fit)
+	}
+	if h, _ := fit.Model.HeightAt(50); !near(h, katapultpro.Feet(20), 1e-9) {
+		t.Errorf("midpoint = %v", h)
+	}
+
+	_, err = calibration.FitAnchors([]katapultpro.PhotoCalibrationAnchor{anchorAt(t, "only", 30)})
+	if !errors.Is(err, calibration.ErrTooFewAnchors) {
+		t.Errorf("one anchor: got %v", err)
+	}
+}
+
+func TestFitPhoto_StickAlignFallbackAndCheckElements(t *testing.T) {
+	// Stick alignment in pixels of a 4000px-tall image, in feet: the same camera model.
+	sa := &katapultpro.StickAlign{A: camera.A / 12 / 40, B: camera.B / 12, C: camera.C / 40, Height: 4000}
+	photo := &katapultpro.Photo{StickAlign: sa}
+	fit, err := calibration.FitPhoto(photo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := camera.HeightAt(35)
+	if got, _ := fit.Model.HeightAt(35); !near(got, want, 1e-9) {
+		t.Errorf("stick align height = %v, want %v", got, want)
+	}
+
+	elements := []katapultpro.PhotoElement{
+		{ID: "ok", MeasuredHeight: want.Inches() + 0.5, PixelSelection: []katapultpro.PixelSelection{{PercentY: 35}}},
+		{ID: "off", MeasuredHeight: want.Inches() + 12, PixelSelection: []katapultpro.PixelSelection{{PercentY: 35}}},
+		{ID: "unmeasured", PixelSelection: []katapultpro.PixelSelection{{PercentY: 35}}},
+	}
+	checks := fit.Model.CheckElements(elements, katapultpro.Inches(2))
+	if len(checks) != 2 || !checks[0].OK || checks[1].OK || !near(checks[1].Diff, katapultpro.Inches(12), 1e-9) {
+		t.Errorf("checks = %+v", checks)
+	}
+	if _, err := fit.Model.ElementHeight(katapultpro.PhotoElement{}); !errors.Is(err, calibration.ErrNoPixelSelection) {
+		t.Errorf("no selection: got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/cassette"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v3/jobs/j1/photos/p1/calibration_anchors":
+			_, _ = w.Write([]byte(`{"status":"success","data":[{"id":"a1","height":"12.5","uploaded_by":"someone@example.com"}],"meta":{"token_count":9999,"last_refill_time":0}}`))
+		default:
+			_, _ = w.Write([]byte(`{"status":"success","data":{"id":"n1","latitude":1,"longitude":2},"meta":{"token_count":9990,"last_refill_time":0}}`))
+		}
+	}))
+	defer ts.Close()
+	ctx := context.Background()
+	redact := cassette.WithRedactFields("uploaded_by", "note")
+
+	rec := cassette.NewRecorder(nil, redact)
+	client, _ := katapultpro.NewClient("secret-key", katapultpro.WithBaseURL(ts.URL), katapultpro.WithHTTPClient(rec.HTTPClient()))
+	if _, err := client.Job("j1").Photos().Photo("p1").Anchors().List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Job("j1").Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Latitude: 1, Longitude: 2, AddAttributes: map[string]interface{}{"note": "private"}}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "testdata", "anchors.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	golden, _ := os.ReadFile(path)
+	for _, leaked := range []string{"secret-key", "someone@example.com", "private"} {
+		if strings.Contains(string(golden), leaked) {
+			t.Errorf("cassette contains %q:\n%s", leaked, golden)
+		}
+	}
+
+	rep, err := cassette.Load(path, redact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offline, _ := katapultpro.NewClient("other-key", katapultpro.WithBaseURL("http://127.0.0.1:1"), katapultpro.WithHTTPClient(rep.HTTPClient()))
+	anchors, err := offline.Job("j1").Photos().Photo("p1").Anchors().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anchors) != 1 || anchors[0].Height != 12.5 {
+		t.Errorf("replayed anchors: %+v", anchors)
+	}
+	// The body matches after redaction even though the live value differs from the recorded one.
+	if _, err := offline.Job("j1").Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Longitude: 2, Latitude: 1, AddAttributes: map[string]interface{}{"note": "different"}}); err != nil {
+		t.Fatal(err)
+	}
+	if offline.LastMeta == nil || offline.LastMeta.TokenCount != 9990 {
+		t.Errorf("replayed meta: %+v", offline.LastMeta)
+	}
+	if _, err := offline.Job("j1").Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Latitude: 3, Longitude: 4}); !errors.Is(err, cassette.ErrNoInteraction) {
+		t.Errorf("expected ErrNoInteraction, got %v", err)
+	}
+	if rep.Remaining() != 0 {
+		t.Errorf("remaining: %d", rep.Remaining())
+	}
+}
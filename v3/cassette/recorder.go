@@ -0,0 +1,95 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Recorder is an http.RoundTripper that captures every request/response pair it forwards.
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	next http.RoundTripper
+	opts *options
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder that forwards requests to next (http.DefaultTransport if nil).
+func NewRecorder(next http.RoundTripper, opts ...Option) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, opts: newOptions(opts)}
+}
+
+// HTTPClient returns an http.Client that records through r, for katapultpro.WithHTTPClient.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request: Request{
+			Method:      req.Method,
+			Path:        req.URL.Path,
+			Query:       normalizeQuery(req.URL.Query()),
+			ContentType: req.Header.Get("Content-Type"),
+			Body:        newBody(reqBody),
+		},
+		Response: Response{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        newBody(respBody),
+		},
+	}
+	r.opts.redact(&in)
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Cassette returns the interactions recorded so far.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Cassette{Interactions: append([]Interaction(nil), r.interactions...)}
+}
+
+// Save writes the recorded interactions to path.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().WriteFile(path)
+}
+
+// readBody reads and restores req.Body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	b, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	return b, nil
+}
@@ -0,0 +1,117 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrNoInteraction is returned (wrapped) by Replayer.RoundTrip when no unused recorded interaction matches a request.
+var ErrNoInteraction = errors.New("cassette: no matching interaction")
+
+// Replayer is an http.RoundTripper that serves recorded interactions. Each interaction is served
+// once, in recorded order among interactions that match the same request. A Replayer is safe for
+// concurrent use.
+type Replayer struct {
+	opts *options
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a Replayer for c. Pass the same redaction options used when recording so
+// that live requests are redacted the same way before matching.
+func NewReplayer(c *Cassette, opts ...Option) *Replayer {
+	return &Replayer{opts: newOptions(opts), interactions: c.Interactions, used: make([]bool, len(c.Interactions))}
+}
+
+// Load reads the cassette at path and returns a Replayer for it.
+func Load(path string, opts ...Option) (*Replayer, error) {
+	c, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(c, opts...), nil
+}
+
+// HTTPClient returns an http.Client that replays through r, for katapultpro.WithHTTPClient.
+func (r *Replayer) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Remaining returns the number of interactions not yet served.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, u := range r.used {
+		if !u {
+			n++
+		}
+	}
+	return n
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	live := Interaction{Request: Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  normalizeQuery(req.URL.Query()),
+		Body:   newBody(body),
+	}}
+	r.opts.redact(&live)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || !matches(in.Request, live.Request) {
+			continue
+		}
+		r.used[i] = true
+		header := http.Header{}
+		if in.Response.ContentType != "" {
+			header.Set("Content-Type", in.Response.ContentType)
+		}
+		b := in.Response.Body.Bytes()
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(b)),
+			ContentLength: int64(len(b)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w for %s %s?%s", ErrNoInteraction, live.Request.Method, live.Request.Path, live.Request.Query)
+}
+
+// matches compares method, path, normalized query, and body. JSON bodies are compared
+// semantically so key order and whitespace do not matter.
+func matches(rec, live Request) bool {
+	if rec.Method != live.Method || rec.Path != live.Path || rec.Query != live.Query {
+		return false
+	}
+	if rec.Body.JSON != nil && live.Body.JSON != nil {
+		var a, b any
+		if json.Unmarshal(rec.Body.JSON, &a) != nil || json.Unmarshal(live.Body.JSON, &b) != nil {
+			return false
+		}
+		ab, _ := json.Marshal(a)
+		bb, _ := json.Marshal(b)
+		return bytes.Equal(ab, bb)
+	}
+	return bytes.Equal(rec.Body.Bytes(), live.Body.Bytes())
+}
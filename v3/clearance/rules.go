@@ -0,0 +1,307 @@
+// Package clearance checks attachment separations and heights against a configurable rule set.
+//
+// Rules are usually loaded from JSON. Heights may be written in inches or in any notation
+// katapultpro.ParseHeight accepts:
+//
+//	{
+//	  "span_type_attribute": "midspan_type",
+//	  "separations": [
+//	    {"name": "comm to comm", "upper": {"class": "comm"}, "lower": {"class": "comm"}, "min": 12, "different_owners": true},
+//	    {"name": "power to comm", "upper": {"class": "power"}, "lower": {"class": "comm"}, "min": "3-4", "at": "node"}
+//	  ],
+//	  "min_heights": [
+//	    {"name": "comm over road", "match": {"class": "comm"}, "span_types": ["road"], "min": "15-6", "at": "midspan"}
+//	  ],
+//	  "classes": {"comm": ["Telco Com", "CATV Com", "Fiber Optic Com"], "power": ["Primary", "Neutral", "Secondary"]}
+//	}
+//
+// Evaluate applies the rules to an attachments.Report (node and midspan attachment heights):
+//
+//	rules, err := clearance.LoadRules(f)
+//	violations, err := clearance.Check(ctx, client.Job("job-123"), rules)
+//
+// The package encodes no standard's values; the rule set is the whole policy.
+package clearance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/attachments"
+)
+
+// Location kinds for rules and violations.
+const (
+	AtNode    = "node"
+	AtMidspan = "midspan"
+)
+
+// Rules is a clearance rule set.
+type Rules struct {
+	// SpanTypeAttribute is the section or connection attribute holding the span type (e.g. "road",
+	// "pedestrian") that MinHeight rules filter on. Sections are checked first.
+	SpanTypeAttribute string `json:"span_type_attribute,omitempty"`
+	// Classes groups cable types (trace cable_type values) under names such as "comm" and "power".
+	Classes     map[string][]string `json:"classes,omitempty"`
+	Separations []SeparationRule    `json:"separations,omitempty"`
+	MinHeights  []HeightRule        `json:"min_heights,omitempty"`
+}
+
+// Match selects attachments. Empty fields match anything; list fields match any listed value.
+// Comparisons ignore case.
+type Match struct {
+	Class      string   `json:"class,omitempty"` // A key of Rules.Classes
+	CableTypes []string `json:"cable_types,omitempty"`
+	Owners     []string `json:"owners,omitempty"` // Trace companies
+}
+
+// SeparationRule requires at least Min between an attachment matching Upper and a lower one
+// matching Lower at the same location.
+type SeparationRule struct {
+	Name  string             `json:"name"`
+	Upper Match              `json:"upper"`
+	Lower Match              `json:"lower"`
+	Min   katapultpro.Height `json:"min"`
+	// At limits the rule to AtNode or AtMidspan; empty applies it at both.
+	At string `json:"at,omitempty"`
+	// DifferentOwners skips pairs owned by the same company.
+	DifferentOwners bool `json:"different_owners,omitempty"`
+}
+
+// HeightRule requires attachments matching Match to be at least Min above ground.
+type HeightRule struct {
+	Name  string             `json:"name"`
+	Match Match              `json:"match"`
+	Min   katapultpro.Height `json:"min"`
+	// At limits the rule to AtNode or AtMidspan; empty applies it at both.
+	At string `json:"at,omitempty"`
+	// SpanTypes limits the rule to midspans with one of these span types; empty means any span.
+	SpanTypes []string `json:"span_types,omitempty"`
+}
+
+// LoadRules decodes a JSON rule set and validates it. Unknown fields are rejected.
+func LoadRules(r io.Reader) (*Rules, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var rules Rules
+	if err := dec.Decode(&rules); err != nil {
+		return nil, fmt.Errorf("clearance: decode rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// Validate reports rules that name unknown classes or locations, or have no positive minimum.
+func (rs *Rules) Validate() error {
+	check := func(kind, name string, at string, min katapultpro.Height, ms ...Match) error {
+		if at != "" && at != AtNode && at != AtMidspan {
+			return fmt.Errorf("clearance: %s rule %q: at must be %q or %q", kind, name, AtNode, AtMidspan)
+		}
+		if min <= 0 {
+			return fmt.Errorf("clearance: %s rule %q: min must be positive", kind, name)
+		}
+		for _, m := range ms {
+			if _, ok := rs.Classes[m.Class]; m.Class != "" && !ok {
+				return fmt.Errorf("clearance: %s rule %q: unknown class %q", kind, name, m.Class)
+			}
+		}
+		return nil
+	}
+	for _, r := range rs.Separations {
+		if err := check("separation", r.Name, r.At, r.Min, r.Upper, r.Lower); err != nil {
+			return err
+		}
+	}
+	for _, r := range rs.MinHeights {
+		if err := check("height", r.Name, r.At, r.Min, r.Match); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rs *Rules) matches(m Match, a attachments.Attachment) bool {
+	if m.Class != "" && !containsFold(rs.Classes[m.Class], a.CableType) {
+		return false
+	}
+	if len(m.CableTypes) > 0 && !containsFold(m.CableTypes, a.CableType) {
+		return false
+	}
+	if len(m.Owners) > 0 && !containsFold(m.Owners, a.Owner) {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool { return strings.EqualFold(v, s) })
+}
+
+// Violation is a failed rule at one location.
+type Violation struct {
+	Rule string `json:"rule"`
+	Kind string `json:"kind"` // "separation" or "height"
+	At   string `json:"at"`   // AtNode or AtMidspan
+	// Location: NodeID at a node; ConnectionID and SectionID at a midspan.
+	NodeID       string `json:"node_id,omitempty"`
+	ConnectionID string `json:"connection_id,omitempty"`
+	SectionID    string `json:"section_id,omitempty"`
+	SpanType     string `json:"span_type,omitempty"`
+	// Attachments are the attachments involved: the upper then the lower for separations.
+	Attachments []attachments.Attachment `json:"attachments"`
+	Required    katapultpro.Height       `json:"required_in"`
+	Actual      katapultpro.Height       `json:"actual_in"`
+}
+
+// Shortfall returns how much the actual separation or height is below the requirement.
+func (v Violation) Shortfall() katapultpro.Height { return v.Required - v.Actual }
+
+// PhotoIDs returns the distinct photos the involved measurements come from.
+func (v Violation) PhotoIDs() []string {
+	var ids []string
+	for _, a := range v.Attachments {
+		if !slices.Contains(ids, a.PhotoID) {
+			ids = append(ids, a.PhotoID)
+		}
+	}
+	return ids
+}
+
+func (v Violation) String() string {
+	loc := "node " + v.NodeID
+	if v.At == AtMidspan {
+		loc = "section " + v.ConnectionID + "/" + v.SectionID
+	}
+	return fmt.Sprintf("%s at %s: %s %s, need %s (short %s)", v.Rule, loc, v.Kind, v.Actual, v.Required, v.Shortfall())
+}
+
+// location is a node or midspan with its attachments.
+type location struct {
+	at, nodeID, connectionID, sectionID, spanType string
+	atts                                          []attachments.Attachment
+}
+
+// Evaluate applies the rules to the report. conns supplies span types for midspan height rules
+// and may be nil when no rule filters on span type. Violations are ordered by location (nodes,
+// then midspans, as in the report), then by rule.
+func (rs *Rules) Evaluate(report *attachments.Report, conns []katapultpro.Connection) []Violation {
+	var locs []location
+	for _, n := range report.Nodes {
+		locs = append(locs, location{at: AtNode, nodeID: n.NodeID, atts: n.Attachments})
+	}
+	connByID := map[string]*katapultpro.Connection{}
+	for i := range conns {
+		connByID[conns[i].ID] = &conns[i]
+	}
+	for _, s := range report.Sections {
+		locs = append(locs, location{at: AtMidspan, connectionID: s.ConnectionID, sectionID: s.SectionID,
+			spanType: rs.spanType(connByID[s.ConnectionID], s.SectionID), atts: s.Attachments})
+	}
+
+	var out []Violation
+	for _, loc := range locs {
+		for _, r := range rs.Separations {
+			if r.At == "" || r.At == loc.at {
+				out = append(out, rs.separations(r, loc)...)
+			}
+		}
+		for _, r := range rs.MinHeights {
+			if r.At != "" && r.At != loc.at {
+				continue
+			}
+			if len(r.SpanTypes) > 0 && (loc.at != AtMidspan || !containsFold(r.SpanTypes, loc.spanType)) {
+				continue
+			}
+			for _, a := range loc.atts {
+				if a.HasHeight && rs.matches(r.Match, a) && a.Height < r.Min {
+					out = append(out, loc.violation(r.Name, "height", r.Min, a.Height, a))
+				}
+			}
+		}
+	}
+	return out
+}
+
+// separations checks every pair of measured attachments at a location. Measurements of the same
+// trace (for example from two photos of the pole) are not compared with each other.
+func (rs *Rules) separations(r SeparationRule, loc location) []Violation {
+	var out []Violation
+	for i, hi := range loc.atts {
+		for _, lo := range loc.atts[i+1:] {
+			if !hi.HasHeight || !lo.HasHeight {
+				continue
+			}
+			upper, lower := hi, lo
+			if lower.Height > upper.Height {
+				upper, lower = lower, upper
+			}
+			if upper.TraceID != "" && upper.TraceID == lower.TraceID {
+				continue
+			}
+			if r.DifferentOwners && strings.EqualFold(upper.Owner, lower.Owner) {
+				continue
+			}
+			if !rs.matches(r.Upper, upper) || !rs.matches(r.Lower, lower) {
+				continue
+			}
+			if sep := upper.Height - lower.Height; sep < r.Min {
+				out = append(out, loc.violation(r.Name, "separation", r.Min, sep, upper, lower))
+			}
+		}
+	}
+	return out
+}
+
+func (loc location) violation(rule, kind string, required, actual katapultpro.Height, atts ...attachments.Attachment) Violation {
+	return Violation{Rule: rule, Kind: kind, At: loc.at, NodeID: loc.nodeID, ConnectionID: loc.connectionID, SectionID: loc.sectionID,
+		SpanType: loc.spanType, Attachments: atts, Required: required, Actual: actual}
+}
+
+// spanType reads SpanTypeAttribute from the section's attributes, then the connection's.
+func (rs *Rules) spanType(conn *katapultpro.Connection, sectionID string) string {
+	if rs.SpanTypeAttribute == "" || conn == nil {
+		return ""
+	}
+	if v := firstValue(conn.Sections[sectionID].MultiAttributes[rs.SpanTypeAttribute]); v != "" {
+		return v
+	}
+	return firstValue(conn.Attributes[rs.SpanTypeAttribute])
+}
+
+// firstValue returns the value of the lowest instance ID as a string.
+func firstValue(instances map[string]interface{}) string {
+	keys := make([]string, 0, len(instances))
+	for k := range instances {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if s := fmt.Sprint(instances[k]); s != "" && instances[k] != nil {
+			return s
+		}
+	}
+	return ""
+}
+
+// Check builds the job's attachment report and evaluates the rules against it.
+func Check(ctx context.Context, job *katapultpro.JobScope, rules *Rules) ([]Violation, error) {
+	report, err := (&attachments.Builder{Job: job}).Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var conns []katapultpro.Connection
+	if rules.SpanTypeAttribute != "" {
+		if conns, err = job.Connections().List(ctx); err != nil {
+			return nil, fmt.Errorf("clearance: list connections: %w", err)
+		}
+	}
+	return rules.Evaluate(report, conns), nil
+}
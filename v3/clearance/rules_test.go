@@ -0,0 +1,94 @@
+package clearance_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/attachments"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/clearance"
+)
+
+const rulesJSON = `{
+  "span_type_attribute": "midspan_type",
+  "classes": {"comm": ["Telco Com", "CATV Com"], "power": ["Primary", "Neutral"]},
+  "separations": [
+    {"name": "comm to comm", "upper": {"class": "comm"}, "lower": {"class": "comm"}, "min": 12, "different_owners": true},
+    {"name": "power to comm", "upper": {"class": "power"}, "lower": {"class": "comm"}, "min": "3-4", "at": "node"}
+  ],
+  "min_heights": [
+    {"name": "comm over road", "match": {"class": "comm"}, "span_types": ["road"], "min": "15-6", "at": "midspan"}
+  ]
+}`
+
+func att(photo, trace, owner, cable, height string) attachments.Attachment {
+	h, err := katapultpro.ParseHeight(height)
+	if err != nil {
+		panic(err)
+	}
+	return attachments.Attachment{PhotoID: photo, ElementID: trace + "-" + photo, TraceID: trace, Owner: owner, CableType: cable, Height: h, HasHeight: true}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules, err := clearance.LoadRules(strings.NewReader(rulesJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &attachments.Report{
+		Nodes: []attachments.NodeReport{{NodeID: "pole1", Attachments: []attachments.Attachment{
+			att("p1", "neutral", "PowerCo", "Neutral", "25-0"),
+			att("p1", "telco", "Brightspeed", "Telco Com", "22-0"),  // 36in below neutral: short of 40in
+			att("p2", "telco", "Brightspeed", "Telco Com", "21-11"), // same trace, other photo: not compared
+			att("p1", "catv", "Comcast", "CATV Com", "21-4"),        // 8in below telco: short of 12in
+			att("p1", "drop", "Comcast", "CATV Com", "21-0"),        // same owner as catv: not compared with it
+		}}},
+		Sections: []attachments.SectionReport{
+			{ConnectionID: "c1", SectionID: "s1", Attachments: []attachments.Attachment{att("p3", "telco", "Brightspeed", "Telco Com", "15-0")}},
+			{ConnectionID: "c2", SectionID: "s2", Attachments: []attachments.Attachment{att("p4", "telco", "Brightspeed", "Telco Com", "15-0")}},
+		},
+	}
+	conns := []katapultpro.Connection{
+		{ID: "c1", Sections: map[string]katapultpro.EmbeddedSection{"s1": {MultiAttributes: katapultpro.EntityAttributeList{"midspan_type": {"-a": "road"}}}}},
+		{ID: "c2", Attributes: katapultpro.EntityAttributeList{"midspan_type": {"-a": "yard"}}},
+	}
+
+	got := rules.Evaluate(report, conns)
+	var summary []string
+	for _, v := range got {
+		summary = append(summary, v.Rule+"@"+v.NodeID+v.SectionID+"="+v.Actual.String())
+	}
+	want := []string{
+		"comm to comm@pole1=0-8", "comm to comm@pole1=0-7", "comm to comm@pole1=0-11",
+		"power to comm@pole1=3-0", "power to comm@pole1=3-1",
+		"comm over road@s1=15-0",
+	}
+	if strings.Join(summary, " ") != strings.Join(want, " ") {
+		t.Fatalf("violations:\n got %v\nwant %v", summary, want)
+	}
+	v := got[3]
+	if v.Shortfall() != katapultpro.Inches(4) || v.Attachments[0].TraceID != "neutral" || v.Attachments[1].TraceID != "telco" ||
+		strings.Join(v.PhotoIDs(), ",") != "p1" {
+		t.Errorf("power to comm violation = %+v", v)
+	}
+	road := got[5]
+	if road.SpanType != "road" || road.At != clearance.AtMidspan || road.ConnectionID != "c1" {
+		t.Errorf("road violation = %+v", road)
+	}
+	if s := road.String(); !strings.Contains(s, "at section c1/s1") || !strings.Contains(s, "short 0-6") {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	for _, js := range []string{
+		`{"separations": [{"name": "x", "upper": {"class": "nope"}, "min": 12}]}`,
+		`{"min_heights": [{"name": "x", "min": 0}]}`,
+		`{"min_heights": [{"name": "x", "min": 10, "at": "pole"}]}`,
+		`{"min_heights": [{"name": "x", "min": "tall"}]}`,
+		`{"bogus": true}`,
+	} {
+		if _, err := clearance.LoadRules(strings.NewReader(js)); err == nil {
+			t.Errorf("LoadRules(%s): want error", js)
+		}
+	}
+}
@@ -8,10 +8,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/envelope"
 	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/request"
-	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/transport"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/singleflight"
 )
 
 const defaultBaseURL = "https://katapultpro.com/api"
@@ -25,13 +26,22 @@ type Interface interface {
 	Delete(ctx context.Context, path string) error
 }
 
+// Doer performs a single API request and decodes the v3 envelope. *Client implements Doer, and the
+// domain clients (nodes.NewClient, photos.NewClient, ...) and NewJobScope accept any Doer, so you can
+// wrap the client (caching, logging) or replace it in tests.
+type Doer = request.Doer
+
 // Client is the Katapult Pro API client. A Client is safe for concurrent use by multiple goroutines.
 // LastMeta is set after each successful request and holds the API's token_count and last_refill_time for rate-limit awareness.
+// Read it with Meta when requests may be running on other goroutines.
 type Client struct {
 	baseURL    *url.URL
 	apiKey     string
 	httpClient *http.Client
-	LastMeta   *Meta // Set after each request; nil before the first call or if the response had no meta.
+	photoURLs  urlCache
+	inflight   *singleflight.Group[response] // Set by WithRequestCoalescing
+	metaMu     sync.Mutex                    // Guards LastMeta
+	LastMeta   *Meta                         // Set after each request; nil before the first call or if the response had no meta.
 }
 
 // Ensure Client implements Interface and request.Doer at compile time.
@@ -51,6 +61,7 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 		baseURL:    baseURL,
 		apiKey:     apiKey,
 		httpClient: http.DefaultClient,
+		photoURLs:  urlCache{ttl: DefaultPhotoURLTTL},
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -62,7 +73,7 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 // Domain packages use this via the internal request.Doer interface.
 // query is optional; when non-nil it is set as the request URL's RawQuery.
 func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
-	c.LastMeta = nil
+	c.setMeta(nil)
 	var bodyReader io.Reader
 	if body != nil {
 		b, err := json.Marshal(body)
@@ -71,7 +82,7 @@ func (c *Client) Do(ctx context.Context, method, path string, query url.Values,
 		}
 		bodyReader = bytes.NewReader(b)
 	}
-	statusCode, slurp, err := transport.Do(ctx, method, c.baseURL, path, query, bodyReader, "application/json", c.apiKey, c.httpClient)
+	statusCode, slurp, err := c.roundTrip(ctx, method, path, query, bodyReader, "application/json")
 	if err != nil {
 		return err
 	}
@@ -91,11 +102,11 @@ func (c *Client) doWithBody(ctx context.Context, method, path string, query url.
 // DoWithBody performs a request with a raw body and optional content type (e.g. image/jpeg for photo upload).
 // Domain packages use this via the internal request.Doer interface.
 func (c *Client) DoWithBody(ctx context.Context, method, path string, query url.Values, contentType string, body io.Reader, out any) error {
-	c.LastMeta = nil
+	c.setMeta(nil)
 	if contentType == "" {
 		contentType = "application/json"
 	}
-	statusCode, slurp, err := transport.Do(ctx, method, c.baseURL, path, query, body, contentType, c.apiKey, c.httpClient)
+	statusCode, slurp, err := c.roundTrip(ctx, method, path, query, body, contentType)
 	if err != nil {
 		return err
 	}
@@ -123,7 +134,7 @@ func (c *Client) handleResponse(statusCode int, slurp []byte, out any) error {
 	if env.Status == "error" {
 		return &APIError{StatusCode: statusCode, Message: env.Message, Type: env.Type, Meta: meta}
 	}
-	c.LastMeta = meta
+	c.setMeta(meta)
 	if out != nil && len(env.Data) > 0 {
 		if err := json.Unmarshal(env.Data, out); err != nil {
 			return fmt.Errorf("decode response data: %w", err)
@@ -132,6 +143,19 @@ func (c *Client) handleResponse(statusCode int, slurp []byte, out any) error {
 	return nil
 }
 
+// Meta returns LastMeta. Unlike reading the field, it is safe while other requests are running.
+func (c *Client) Meta() *Meta {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+	return c.LastMeta
+}
+
+func (c *Client) setMeta(m *Meta) {
+	c.metaMu.Lock()
+	c.LastMeta = m
+	c.metaMu.Unlock()
+}
+
 // Get sends a GET request to path and decodes the response body into out.
 // out may be nil to discard the body.
 func (c *Client) Get(ctx context.Context, path string, out any) error {
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/schema"
+)
+
+// config controls generation.
+type config struct {
+	Package string
+	Source  string            // Schema file name, for the header and doc comments
+	Structs map[string]string // Struct name per entity kind; empty skips the kind
+}
+
+// requests names the create and update request types of each kind.
+var requests = []struct{ kind, create, update string }{
+	{schema.KindNode, "CreateNodeRequest", "UpdateNodeRequest"},
+	{schema.KindConnection, "CreateConnectionRequest", "UpdateConnectionRequest"},
+	{schema.KindSection, "CreateSectionRequest", "UpdateSectionRequest"},
+}
+
+// goTypes maps schema attribute types to field element types.
+var goTypes = map[string]string{
+	schema.TypeAny:     "interface{}",
+	schema.TypeString:  "string",
+	schema.TypeNumber:  "float64",
+	schema.TypeInteger: "int64",
+	schema.TypeBoolean: "bool",
+}
+
+// initialisms are attribute name parts written in upper case in field names.
+var initialisms = map[string]bool{
+	"api": true, "gps": true, "html": true, "http": true, "id": true, "ip": true, "json": true,
+	"mr": true, "scid": true, "uid": true, "url": true, "utc": true, "uuid": true, "xml": true,
+}
+
+type field struct {
+	name, attr, typ string
+	multiple        bool
+	doc             string
+}
+
+// generate returns the formatted source of the structs for s.
+func generate(s *schema.Schema, cfg config) ([]byte, error) {
+	if !token.IsIdentifier(cfg.Package) {
+		return nil, fmt.Errorf("attrgen: invalid package name %q", cfg.Package)
+	}
+	entities := map[string]*schema.Entity{schema.KindNode: &s.Node, schema.KindConnection: &s.Connection, schema.KindSection: &s.Section}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by attrgen from %s. DO NOT EDIT.\n\n", cfg.Source)
+	fmt.Fprintf(&b, "package %s\n\n", cfg.Package)
+	b.WriteString("import (\n\t\"github.com/romer-pro/katapultpro-go-sdk/v3\"\n\t\"github.com/romer-pro/katapultpro-go-sdk/v3/attrs\"\n)\n")
+	seen := map[string]string{}
+	n := 0
+	for _, r := range requests {
+		name, e := cfg.Structs[r.kind], entities[r.kind]
+		if name == "" || len(e.Attributes) == 0 {
+			continue
+		}
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("attrgen: invalid struct name %q for %s attributes", name, r.kind)
+		}
+		if other, dup := seen[name]; dup {
+			return nil, fmt.Errorf("attrgen: %s and %s attributes both use struct %s", other, r.kind, name)
+		}
+		seen[name] = r.kind
+		fields, err := fieldsOf(r.kind, e)
+		if err != nil {
+			return nil, err
+		}
+		writeStruct(&b, name, r.kind, r.create, r.update, cfg.Source, fields)
+		n++
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("attrgen: %s has no attributes to generate", cfg.Source)
+	}
+	code, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("attrgen: format: %w\n%s", err, b.Bytes())
+	}
+	return code, nil
+}
+
+func fieldsOf(kind string, e *schema.Entity) ([]field, error) {
+	attrNames := make([]string, 0, len(e.Attributes))
+	for name := range e.Attributes {
+		attrNames = append(attrNames, name)
+	}
+	sort.Strings(attrNames)
+
+	var fields []field
+	byField := map[string]string{}
+	for _, attr := range attrNames {
+		a := e.Attributes[attr]
+		name := a.GoName
+		if name == "" {
+			name = fieldName(attr)
+		} else if !token.IsIdentifier(name) || !token.IsExported(name) {
+			return nil, fmt.Errorf("attrgen: %s attribute %s: go_name %q is not an exported identifier", kind, attr, name)
+		}
+		if other, dup := byField[name]; dup {
+			return nil, fmt.Errorf("attrgen: %s attributes %s and %s are both field %s; set go_name on one", kind, other, attr, name)
+		}
+		byField[name] = attr
+		fields = append(fields, field{name: name, attr: attr, typ: goTypes[a.Type], multiple: a.Multiple, doc: describe(attr, a)})
+	}
+	return fields, nil
+}
+
+// fieldName converts an attribute name such as pole_height or "Pole Tag" to a field name.
+func fieldName(attr string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(attr, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if initialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		r := []rune(part)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		name = "Attr" + name
+	}
+	return name
+}
+
+// describe summarizes an attribute's constraints for its field comment.
+func describe(attr string, a *schema.Attribute) string {
+	parts := []string{attr}
+	if len(a.Enum) > 0 {
+		parts = append(parts, "one of "+strings.Join(a.Enum, ", "))
+	}
+	if a.Pattern != "" {
+		parts = append(parts, "matches "+a.Pattern)
+	}
+	num := func(f *float64) string { return strconv.FormatFloat(*f, 'g', -1, 64) }
+	switch {
+	case a.Min != nil && a.Max != nil:
+		parts = append(parts, num(a.Min)+" to "+num(a.Max))
+	case a.Min != nil:
+		parts = append(parts, "at least "+num(a.Min))
+	case a.Max != nil:
+		parts = append(parts, "at most "+num(a.Max))
+	}
+	if a.Required {
+		parts = append(parts, "required")
+	}
+	return strings.Join(strings.Fields(strings.Join(parts, "; ")), " ")
+}
+
+func writeStruct(b *bytes.Buffer, name, kind, create, update, source string, fields []field) {
+	p := func(format string, args ...any) { fmt.Fprintf(b, format, args...) }
+
+	p("\n// %s holds the %s attributes of %s. Unset fields are nil.\n", name, kind, source)
+	p("type %s struct {\n", name)
+	for _, f := range fields {
+		if f.multiple {
+			p("\t%s []%s // %s\n", f.name, f.typ, f.doc)
+		} else {
+			p("\t%s *%s // %s\n", f.name, f.typ, f.doc)
+		}
+	}
+	p("}\n")
+
+	p("\n// UnmarshalAttributes sets a from list, replacing all of its fields.\n")
+	p("func (a *%s) UnmarshalAttributes(list katapultpro.EntityAttributeList) error {\n", name)
+	p("\tvar (\n\t\tv   %s\n\t\terr error\n\t)\n", name)
+	for _, f := range fields {
+		fn := "One"
+		if f.multiple {
+			fn = "Many"
+		}
+		p("\tif v.%s, err = attrs.%s[%s](list, %q); err != nil {\n\t\treturn err\n\t}\n", f.name, fn, f.typ, f.attr)
+	}
+	p("\t*a = v\n\treturn nil\n}\n")
+
+	p("\n// MarshalAttributes returns the fields that are set as an attribute list, with instance IDs\n")
+	p("// chosen by s. current is the %s's current attribute list, or nil.\n", kind)
+	p("func (a *%s) MarshalAttributes(s attrs.Strategy, current katapultpro.EntityAttributeList) katapultpro.EntityAttributeList {\n", name)
+	p("\tlist := katapultpro.EntityAttributeList{}\n")
+	for _, f := range fields {
+		fn := "SetOne"
+		if f.multiple {
+			fn = "SetMany"
+		}
+		p("\tattrs.%s(list, %q, a.%s, s, current)\n", fn, f.attr, f.name)
+	}
+	p("\treturn list\n}\n")
+
+	p("\n// ApplyCreate adds the fields that are set to req.\n")
+	p("func (a *%s) ApplyCreate(req *katapultpro.%s, s attrs.Strategy) {\n", name, create)
+	p("\tattrs.Merge(&req.Attributes, a.MarshalAttributes(s, nil))\n}\n")
+
+	p("\n// ApplyUpdate adds the fields that are set to req, replacing all instances of those attributes.\n")
+	p("// current is the %s's current attribute list; when it is nil the attributes are also listed in\n", kind)
+	p("// RemoveAttributes. Unset fields leave their attributes unchanged.\n")
+	p("func (a *%s) ApplyUpdate(req *katapultpro.%s, s attrs.Strategy, current katapultpro.EntityAttributeList) {\n", name, update)
+	p("\tattrs.Update(&req.RemoveAttributes, &req.Attributes, a.MarshalAttributes(s, current), current)\n}\n")
+}
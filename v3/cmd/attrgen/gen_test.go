@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/schema"
+)
+
+func load(t *testing.T, src string) *schema.Schema {
+	t.Helper()
+	s, err := schema.Load(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// TestGenerate_Example checks that the generated example in package attrs, which its tests
+// compile and run, is up to date.
+func TestGenerate_Example(t *testing.T) {
+	src, err := os.ReadFile("../../attrs/testdata/schema.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := generate(load(t, string(src)), config{
+		Package: "attrs_test",
+		Source:  "schema.yaml",
+		Structs: map[string]string{schema.KindNode: "PoleAttributes", schema.KindConnection: "SpanAttributes", schema.KindSection: "MidspanAttributes"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("../../attrs/example_gen_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(code) != string(want) {
+		t.Error("attrs/example_gen_test.go is stale; run go generate ./attrs")
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	for attr, want := range map[string]string{
+		"pole_height": "PoleHeight",
+		"scid":        "SCID",
+		"node_id":     "NodeID",
+		"Pole Tag":    "PoleTag",
+		"mrState":     "MrState",
+		"2nd_owner":   "Attr2ndOwner",
+	} {
+		if got := fieldName(attr); got != want {
+			t.Errorf("fieldName(%q) = %q, want %q", attr, got, want)
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	structs := map[string]string{schema.KindNode: "NodeAttributes", schema.KindConnection: "ConnectionAttributes"}
+	for _, tc := range []struct{ name, schema, err string }{
+		{"collision", "node:\n  attributes:\n    pole_tag: {}\n    pole-tag: {}", "node attributes pole-tag and pole_tag are both field PoleTag"},
+		{"go_name", "node:\n  attributes:\n    x: {go_name: lower}", `go_name "lower" is not an exported identifier`},
+		{"empty", "node:\n  attributes: {}", "has no attributes to generate"},
+	} {
+		_, err := generate(load(t, tc.schema), config{Package: "p", Source: "s.yaml", Structs: structs})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.err)
+		}
+	}
+	s := load(t, "node:\n  attributes:\n    x: {}\nconnection:\n  attributes:\n    y: {}")
+	if _, err := generate(s, config{Package: "p", Source: "s.yaml", Structs: map[string]string{schema.KindNode: "A", schema.KindConnection: "A"}}); err == nil {
+		t.Error("duplicate struct name: expected error")
+	}
+}
@@ -0,0 +1,63 @@
+// Command attrgen generates typed attribute structs from an attribute schema (see package schema).
+// Each entity kind with attributes in the schema gets a struct with a field per attribute, methods to
+// read it from and write it to an EntityAttributeList, and helpers to apply it to create and update
+// requests. Run it with go generate:
+//
+//	//go:generate go run github.com/romer-pro/katapultpro-go-sdk/v3/cmd/attrgen -schema attributes.yaml -node PoleAttributes -connection SpanAttributes -out attributes_gen.go
+//
+// Single-valued attributes become pointer fields, nil when absent; multiple ones become slices.
+// string, number, integer, and boolean attributes are string, float64, int64, and bool fields, and
+// attributes of any type are interface{}. Field names are the attribute names in Go style
+// (pole_height is PoleHeight, scid is SCID) unless the schema sets go_name. The runtime support,
+// including the instance ID strategies, is in package attrs.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/schema"
+)
+
+func main() {
+	src := flag.String("schema", "", "schema file, YAML or JSON")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name (default $GOPACKAGE, set by go generate)")
+	out := flag.String("out", "attributes_gen.go", "output file")
+	node := flag.String("node", "NodeAttributes", "struct for node attributes; empty skips them")
+	connection := flag.String("connection", "ConnectionAttributes", "struct for connection attributes; empty skips them")
+	section := flag.String("section", "SectionAttributes", "struct for section attributes; empty skips them")
+	flag.Parse()
+	if *src == "" {
+		log.Fatal("attrgen: -schema is required")
+	}
+	if *pkg == "" {
+		abs, err := filepath.Abs(filepath.Dir(*out))
+		if err != nil {
+			log.Fatal(err)
+		}
+		*pkg = filepath.Base(abs)
+	}
+
+	f, err := os.Open(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := schema.Load(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(s, config{
+		Package: *pkg,
+		Source:  filepath.Base(*src),
+		Structs: map[string]string{schema.KindNode: *node, schema.KindConnection: *connection, schema.KindSection: *section},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,701 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/bulkupload"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/folders"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/lint"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/photomirror"
+)
+
+// dispatch runs verb on resource and returns the value to print, or nil for no output.
+func (c *cli) dispatch(ctx context.Context, resource, verb string, ids []string) (any, error) {
+	switch resource {
+	case "jobs", "job":
+		return c.jobs(ctx, verb, ids)
+	case "nodes", "node":
+		return c.nodes(ctx, verb, ids)
+	case "connections", "connection":
+		return c.connections(ctx, verb, ids)
+	case "sections", "section":
+		return c.sections(ctx, verb, ids)
+	case "photos", "photo":
+		return c.photos(ctx, verb, ids)
+	case "elements", "element":
+		return c.elements(ctx, verb, ids)
+	case "anchors", "anchor":
+		return c.anchors(ctx, verb, ids)
+	case "traces", "trace":
+		return c.traces(ctx, verb, ids)
+	}
+	return nil, usagef("unknown resource %q", resource)
+}
+
+func (c *cli) jobs(ctx context.Context, verb string, ids []string) (any, error) {
+	switch verb {
+	case "list":
+		filter, err := katapultpro.ParseMetadataFilter(c.metadataFilter)
+		if err != nil {
+			return nil, usagef("-metadata-filter: %v", err)
+		}
+		return c.client.ListJobs(ctx, &katapultpro.ListJobsOptions{
+			IncludeArchived: c.includeArchived,
+			MetadataFilter:  filter.String(),
+		})
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var opts *katapultpro.GetJobOptions
+		if c.paths != "" {
+			opts = &katapultpro.GetJobOptions{}
+			for _, p := range strings.Split(c.paths, ",") {
+				opts.Paths = append(opts.Paths, katapultpro.JobPath(strings.TrimSpace(p)))
+			}
+		}
+		return c.client.GetJob(ctx, id, opts)
+	case "create":
+		var req katapultpro.CreateJobRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return c.client.CreateJob(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdateJobRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return c.client.UpdateJob(ctx, id, &req)
+	case "status":
+		if len(ids) == 2 {
+			status := katapultpro.JobStatus(ids[1])
+			if status != katapultpro.JobStatusActive && status != katapultpro.JobStatusArchived {
+				return nil, usagef("status must be active or archived, got %q", ids[1])
+			}
+			if err := c.client.UpdateJobStatus(ctx, ids[0], status); err != nil {
+				return nil, err
+			}
+			return map[string]any{"status": status}, nil
+		}
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		status, err := c.client.GetJobStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"status": status}, nil
+	case "lint":
+		if len(ids) > 1 {
+			return nil, usagef("lint takes at most one ID")
+		}
+		if len(ids) == 1 {
+			c.job = ids[0]
+		}
+		job, err := c.jobScope()
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := c.lintConfig()
+		if err != nil {
+			return nil, err
+		}
+		if !c.fix {
+			return (&lint.Linter{Job: job, Config: cfg, Concurrency: c.concurrency}).Run(ctx)
+		}
+		return c.lintFix(ctx, &lint.Fixer{Job: job, Config: cfg, Commit: c.commit, Concurrency: c.concurrency})
+	case "tree":
+		if len(ids) > 1 {
+			return nil, usagef("tree takes at most one folder")
+		}
+		root, err := folders.Load(ctx, c.client)
+		if err != nil {
+			return nil, err
+		}
+		if f := root.Find(strings.Join(ids, "")); f != nil {
+			return f, nil
+		}
+		return nil, fmt.Errorf("no folder %q", ids[0])
+	case "archive":
+		if len(ids) != 1 {
+			return nil, usagef("archive takes one folder (\"/\" for the root)")
+		}
+		return c.archiveFolder(ctx, ids[0])
+	}
+	return nil, unknownVerb("jobs", verb)
+}
+
+// archiveFolder archives the active jobs of a folder (a dry run without -commit).
+func (c *cli) archiveFolder(ctx context.Context, folder string) (any, error) {
+	sel := folders.Selection{Folder: folder, Recursive: c.recursive}
+	if c.olderThan != "" {
+		age, err := parseAge(c.olderThan)
+		if err != nil {
+			return nil, usagef("-older-than: %v", err)
+		}
+		if c.dateAttribute == "" {
+			return nil, usagef("-older-than needs -date-attribute")
+		}
+		sel.OlderThan, sel.Date = age, folders.MetadataDate(c.dateAttribute)
+	}
+	root, err := folders.Load(ctx, c.client)
+	if err != nil {
+		return nil, err
+	}
+	return (&folders.Archiver{Client: c.client, Commit: c.commit}).Archive(ctx, root, sel)
+}
+
+// parseAge parses a duration, also accepting whole days such as "90d".
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+	return d, nil
+}
+
+// lintConfig loads the -rules file, or returns nil for the default checks.
+func (c *cli) lintConfig() (*lint.Config, error) {
+	if c.rules == "" {
+		return nil, nil
+	}
+	f, err := os.Open(c.rules)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg, err := lint.LoadConfig(f)
+	if err != nil {
+		return nil, usagef("%v", err)
+	}
+	return cfg, nil
+}
+
+// lintFix plans the fixes and applies them (a dry run without -commit), appending to the -audit log.
+func (c *cli) lintFix(ctx context.Context, fixer *lint.Fixer) (any, error) {
+	if c.audit != "" {
+		f, err := os.OpenFile(c.audit, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		fixer.Audit = f
+	}
+	plan, err := fixer.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fixer.Apply(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []lint.AuditEntry{} // Print [] rather than null
+	}
+	return entries, nil
+}
+
+func (c *cli) nodes(ctx context.Context, verb string, ids []string) (any, error) {
+	job, err := c.jobScope()
+	if err != nil {
+		return nil, err
+	}
+	nodes := job.Nodes()
+	switch verb {
+	case "list":
+		return nodes.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nodes.Get(ctx, id)
+	case "create":
+		var req katapultpro.CreateNodeRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return nodes.Create(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdateNodeRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return nodes.Update(ctx, id, &req, &katapultpro.UpdateNodeOptions{OnlyIfExists: c.onlyIfExists})
+	case "delete":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nil, nodes.Delete(ctx, id)
+	case "upload":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		assoc, err := c.associationQuery()
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.input()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return nodes.UploadPhoto(ctx, id, r, &katapultpro.UploadNodePhotoOptions{AssociationValue: assoc})
+	}
+	return nil, unknownVerb("nodes", verb)
+}
+
+func (c *cli) connections(ctx context.Context, verb string, ids []string) (any, error) {
+	job, err := c.jobScope()
+	if err != nil {
+		return nil, err
+	}
+	conns := job.Connections()
+	switch verb {
+	case "list":
+		return conns.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return conns.Get(ctx, id)
+	case "create":
+		var req katapultpro.CreateConnectionRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return conns.Create(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdateConnectionRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return conns.Update(ctx, id, &req, &katapultpro.UpdateConnectionOptions{OnlyIfExists: c.onlyIfExists})
+	case "delete":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nil, conns.Delete(ctx, id)
+	}
+	return nil, unknownVerb("connections", verb)
+}
+
+func (c *cli) sections(ctx context.Context, verb string, ids []string) (any, error) {
+	job, err := c.jobScope()
+	if err != nil {
+		return nil, err
+	}
+	if c.connection == "" {
+		switch verb {
+		case "list":
+			return job.Sections().List(ctx)
+		case "get":
+			id, err := oneID(verb, ids)
+			if err != nil {
+				return nil, err
+			}
+			return job.Sections().Get(ctx, id)
+		}
+		return nil, usagef("sections %s requires -connection", verb)
+	}
+	sections := job.Connections().Sections(c.connection)
+	switch verb {
+	case "list":
+		return sections.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return sections.Get(ctx, id)
+	case "create":
+		var req katapultpro.CreateSectionRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return sections.Create(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdateSectionRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return sections.Update(ctx, id, &req, &katapultpro.UpdateSectionOptions{OnlyIfExists: c.onlyIfExists})
+	case "delete":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nil, sections.Delete(ctx, id)
+	case "upload":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		assoc, err := c.associationQuery()
+		if err != nil {
+			return nil, err
+		}
+		r, err := c.input()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return sections.UploadPhoto(ctx, id, r, &katapultpro.UploadSectionPhotoOptions{AssociationValue: assoc})
+	}
+	return nil, unknownVerb("sections", verb)
+}
+
+func (c *cli) photos(ctx context.Context, verb string, ids []string) (any, error) {
+	job, err := c.jobScope()
+	if err != nil {
+		return nil, err
+	}
+	photos := job.Photos()
+	switch verb {
+	case "list":
+		return photos.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return photos.Get(ctx, id)
+	case "upload":
+		r, err := c.input()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return photos.Upload(ctx, r)
+	case "associate":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		req, err := c.associateBody()
+		if err != nil {
+			return nil, err
+		}
+		return nil, photos.Associate(ctx, id, req)
+	case "bulk-upload":
+		dir, err := oneArg(verb, "directory", ids)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := c.associationQuery(); err != nil {
+			return nil, err
+		}
+		u := &bulkupload.Uploader{
+			Job:         job,
+			MaxDistance: c.maxDistance,
+			Concurrency: c.concurrency,
+			Main:        c.association == "main",
+			DryRun:      c.dryRun,
+		}
+		return u.Run(ctx, dir)
+	case "download":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		size, err := c.photoSize()
+		if err != nil {
+			return nil, err
+		}
+		return nil, c.download(ctx, photos.Photo(id), size)
+	case "mirror":
+		dir, err := oneArg(verb, "directory", ids)
+		if err != nil {
+			return nil, err
+		}
+		size, err := c.photoSize()
+		if err != nil {
+			return nil, err
+		}
+		m := &photomirror.Mirror{Job: job, Dir: dir, Size: size, Concurrency: c.concurrency}
+		return m.Run(ctx)
+	}
+	return nil, unknownVerb("photos", verb)
+}
+
+// photoSize validates -size.
+func (c *cli) photoSize() (katapultpro.PhotoSize, error) {
+	size := katapultpro.PhotoSize(c.size)
+	if !size.IsValid() {
+		return "", usagef("invalid -size %q: want full, extra_large, large, small or tiny", c.size)
+	}
+	return size, nil
+}
+
+// download writes a photo image to -out, removing a partly written file on failure.
+func (c *cli) download(ctx context.Context, photo *katapultpro.PhotoScope, size katapultpro.PhotoSize) error {
+	if c.out == "-" {
+		_, err := photo.Download(ctx, size, c.stdout)
+		return err
+	}
+	f, err := os.Create(c.out)
+	if err != nil {
+		return err
+	}
+	_, err = photo.Download(ctx, size, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(c.out)
+	}
+	return err
+}
+
+func (c *cli) elements(ctx context.Context, verb string, ids []string) (any, error) {
+	photo, err := c.photoScope()
+	if err != nil {
+		return nil, err
+	}
+	elements := photo.Elements()
+	switch verb {
+	case "list":
+		return elements.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return elements.Get(ctx, id)
+	case "create":
+		var req katapultpro.CreatePhotoElementRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return elements.Create(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdatePhotoElementRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return elements.Update(ctx, id, &req, &katapultpro.UpdatePhotoElementOptions{OnlyIfExists: c.onlyIfExists})
+	case "delete":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nil, elements.Delete(ctx, id)
+	}
+	return nil, unknownVerb("elements", verb)
+}
+
+func (c *cli) anchors(ctx context.Context, verb string, ids []string) (any, error) {
+	photo, err := c.photoScope()
+	if err != nil {
+		return nil, err
+	}
+	anchors := photo.Anchors()
+	switch verb {
+	case "list":
+		return anchors.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return anchors.Get(ctx, id)
+	case "create":
+		var req katapultpro.CreatePhotoCalibrationAnchorRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return anchors.Create(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdatePhotoCalibrationAnchorRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return anchors.Update(ctx, id, &req, &katapultpro.UpdatePhotoCalibrationAnchorOptions{OnlyIfExists: c.onlyIfExists})
+	case "delete":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nil, anchors.Delete(ctx, id)
+	}
+	return nil, unknownVerb("anchors", verb)
+}
+
+func (c *cli) traces(ctx context.Context, verb string, ids []string) (any, error) {
+	job, err := c.jobScope()
+	if err != nil {
+		return nil, err
+	}
+	traces := job.Traces()
+	switch verb {
+	case "list":
+		return traces.List(ctx)
+	case "get":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return traces.Get(ctx, id)
+	case "create":
+		var req katapultpro.CreateTraceRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return traces.Create(ctx, &req)
+	case "update":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		var req katapultpro.UpdateTraceRequest
+		if err := c.body(&req); err != nil {
+			return nil, err
+		}
+		return traces.Update(ctx, id, &req, &katapultpro.UpdateTraceOptions{OnlyIfExists: c.onlyIfExists})
+	case "delete":
+		id, err := oneID(verb, ids)
+		if err != nil {
+			return nil, err
+		}
+		return nil, traces.Delete(ctx, id)
+	}
+	return nil, unknownVerb("traces", verb)
+}
+
+func (c *cli) jobScope() (*katapultpro.JobScope, error) {
+	if c.job == "" {
+		return nil, usagef("-job is required (or set KATAPULT_JOB_ID)")
+	}
+	return c.client.Job(c.job), nil
+}
+
+func (c *cli) photoScope() (*katapultpro.PhotoScope, error) {
+	job, err := c.jobScope()
+	if err != nil {
+		return nil, err
+	}
+	if c.photo == "" {
+		return nil, usagef("-photo is required")
+	}
+	return job.Photos().Photo(c.photo), nil
+}
+
+func oneID(verb string, ids []string) (string, error) {
+	return oneArg(verb, "ID", ids)
+}
+
+func oneArg(verb, what string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", usagef("%s takes exactly one %s", verb, what)
+	}
+	return args[0], nil
+}
+
+func unknownVerb(resource, verb string) error {
+	return usagef("unknown verb %q for %s", verb, resource)
+}
+
+// input opens the -f file, or stdin for "-".
+func (c *cli) input() (io.ReadCloser, error) {
+	if c.file == "" || c.file == "-" {
+		return io.NopCloser(c.stdin), nil
+	}
+	return os.Open(c.file)
+}
+
+// body decodes the JSON request body into v, rejecting unknown fields.
+func (c *cli) body(v any) error {
+	r, err := c.input()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return usagef("request body: %v", err)
+	}
+	return nil
+}
+
+// associateBody decodes an associate request; association_value is "main", true, or null.
+func (c *cli) associateBody() (*katapultpro.AssociatePhotoRequest, error) {
+	var raw struct {
+		NodeID           string          `json:"node_id"`
+		SectionID        string          `json:"section_id"`
+		ConnectionID     string          `json:"connection_id"`
+		AssociationValue json.RawMessage `json:"association_value"`
+	}
+	if err := c.body(&raw); err != nil {
+		return nil, err
+	}
+	req := &katapultpro.AssociatePhotoRequest{NodeID: raw.NodeID, SectionID: raw.SectionID, ConnectionID: raw.ConnectionID}
+	switch v := strings.TrimSpace(string(raw.AssociationValue)); v {
+	case `"main"`:
+		req.AssociationValue = katapultpro.PtrPhotoAssociationMain()
+	case "true":
+		req.AssociationValue = katapultpro.PtrPhotoAssociationTrue()
+	case "", "null":
+	default:
+		return nil, usagef("association_value must be \"main\", true or null, got %s", v)
+	}
+	return req, nil
+}
+
+func (c *cli) associationQuery() (katapultpro.PhotoAssociationQuery, error) {
+	switch c.association {
+	case "":
+		return "", nil
+	case "main":
+		return katapultpro.PhotoAssociationQueryMain, nil
+	case "true":
+		return katapultpro.PhotoAssociationQueryTrue, nil
+	}
+	return "", usagef("-association must be main or true, got %q", c.association)
+}
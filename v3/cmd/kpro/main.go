@@ -0,0 +1,306 @@
+// Command kpro is a command-line client for the Katapult Pro API v3, built on the v3 SDK.
+//
+// Usage:
+//
+//	kpro <resource> <verb> [ids...] [flags]
+//
+// Resources and verbs:
+//
+//	jobs         list | get ID | create | update ID | status ID [active|archived] | lint [ID]
+//	             tree [FOLDER] | archive FOLDER
+//	nodes        list | get ID | create | update ID | delete ID | upload ID     (-job)
+//	connections  list | get ID | create | update ID | delete ID                 (-job)
+//	sections     list | get ID | create | update ID | delete ID | upload ID     (-job, -connection)
+//	photos       list | get ID | upload | associate ID | bulk-upload DIR        (-job)
+//	             download ID | mirror DIR
+//	elements     list | get ID | create | update ID | delete ID                 (-job, -photo)
+//	anchors      list | get ID | create | update ID | delete ID                 (-job, -photo)
+//	traces       list | get ID | create | update ID | delete ID                 (-job)
+//
+// Request bodies for create, update, and associate are JSON read from -f, or stdin when -f is
+// omitted or "-". Uploads read the JPEG the same way. Without -connection, sections list and get
+// use the job-level section routes. bulk-upload uploads every JPEG under DIR that is not already
+// in the job and associates it with the nearest node or section by EXIF GPS (see package bulkupload).
+// download writes one photo image (-size) to -out, or stdout; mirror downloads every photo of the
+// job into DIR, resuming partial downloads and skipping photos already there (see package photomirror).
+// jobs lint runs the data quality checks of package lint over a job, configured by the JSON file
+// given with -rules. With -fix it prints the mechanical fixes instead, and applies them only with
+// -commit; -audit appends a JSON line per change to a log file. jobs tree prints the project folder
+// tree with job counts (see package folders); jobs archive archives the active jobs of a folder,
+// with -recursive its subfolders, and with -older-than only jobs whose -date-attribute metadata
+// date is that old. It is a dry run unless -commit is given.
+//
+// The API key is read from KATAPULT_API_KEY, or from a profile in the config file
+// ($XDG_CONFIG_HOME/kpro/config.json or the OS equivalent; override with -config):
+//
+//	{"default_profile": "work", "profiles": {"work": {"api_key": "...", "base_url": "..."}}}
+//
+// An explicit -profile wins over the environment. Requests are rate limited to the API's
+// 1 call per 50ms unless -rate 0 is given.
+//
+// Output is JSON by default; use -o yaml or -o table. -q selects part of the result with a
+// jq-like path (".[].id", ".attributes.scid", ".[0]"), and -fields keeps only the given
+// comma-separated dotted fields (also the table columns).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/bulkupload"
+)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+// cli holds the parsed flags and I/O for one invocation.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	client *katapultpro.Client
+
+	job, connection, photo string
+	file                   string
+	onlyIfExists           bool
+	includeArchived        bool
+	metadataFilter         string
+	paths                  string
+	association            string
+	maxDistance            float64
+	concurrency            int
+	dryRun                 bool
+	size                   string
+	out                    string
+	rules                  string
+	fix, commit            bool
+	audit                  string
+	recursive              bool
+	olderThan              string
+	dateAttribute          string
+}
+
+// usageError is an error in the command line; it exits with status 2.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// run executes one command and returns the process exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	c := &cli{stdin: stdin, stdout: stdout}
+	fs := flag.NewFlagSet("kpro", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		output, fields, q string
+		profile, config   string
+		baseURL           string
+		rate              time.Duration
+	)
+	fs.StringVar(&output, "o", "json", "output format: json, yaml or table")
+	fs.StringVar(&fields, "fields", "", "comma-separated dotted fields to keep")
+	fs.StringVar(&q, "q", "", "jq-like path to select from the result (e.g. .[].id)")
+	fs.StringVar(&profile, "profile", "", "config profile to use")
+	fs.StringVar(&config, "config", "", "config file path")
+	fs.StringVar(&baseURL, "base-url", "", "API base URL")
+	fs.DurationVar(&rate, "rate", katapultpro.DefaultRateLimitInterval, "minimum interval between requests (0 disables)")
+	fs.StringVar(&c.job, "job", getenv("KATAPULT_JOB_ID"), "job ID (default $KATAPULT_JOB_ID)")
+	fs.StringVar(&c.connection, "connection", "", "connection ID (sections)")
+	fs.StringVar(&c.photo, "photo", "", "photo ID (elements, anchors)")
+	fs.StringVar(&c.file, "f", "-", "request body or photo file; - reads stdin")
+	fs.BoolVar(&c.onlyIfExists, "only-if-exists", false, "update only if the item exists")
+	fs.BoolVar(&c.includeArchived, "include-archived", false, "include archived jobs (jobs list)")
+	fs.StringVar(&c.metadataFilter, "metadata-filter", "", "metadata filter attr:value,... (jobs list)")
+	fs.StringVar(&c.paths, "paths", "", "comma-separated job paths (jobs get)")
+	fs.StringVar(&c.association, "association", "", "association value for uploads: main or true")
+	fs.Float64Var(&c.maxDistance, "max-distance", bulkupload.DefaultMaxDistance, "bulk-upload: max meters from photo to node or section")
+	fs.IntVar(&c.concurrency, "concurrency", bulkupload.DefaultConcurrency, "bulk-upload, mirror: transfers in flight; jobs lint: element listings in flight")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "bulk-upload: match files without uploading")
+	fs.StringVar(&c.size, "size", string(katapultpro.PhotoSizeFull), "download, mirror: full, extra_large, large, small or tiny")
+	fs.StringVar(&c.out, "out", "-", "download: output file; - writes stdout")
+	fs.StringVar(&c.rules, "rules", "", "jobs lint: JSON file configuring the checks")
+	fs.BoolVar(&c.fix, "fix", false, "jobs lint: plan fixes for the findings that have one (dry run)")
+	fs.BoolVar(&c.commit, "commit", false, "jobs lint -fix: apply the fixes; jobs archive: archive the jobs")
+	fs.StringVar(&c.audit, "audit", "", "jobs lint -fix: append a JSON line per change to this file")
+	fs.BoolVar(&c.recursive, "recursive", false, "jobs archive: include subfolders")
+	fs.StringVar(&c.olderThan, "older-than", "", "jobs archive: only jobs at least this old (e.g. 90d or 720h)")
+	fs.StringVar(&c.dateAttribute, "date-attribute", "", "jobs archive: job metadata attribute holding the date for -older-than")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: kpro <resource> <verb> [ids...] [flags]\n\nresources: jobs, nodes, connections, sections, photos, elements, anchors, traces\n\nflags:")
+		fs.PrintDefaults()
+	}
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if len(pos) < 2 {
+		fs.Usage()
+		return 2
+	}
+	if err := checkFormat(output); err != nil {
+		fmt.Fprintln(stderr, "kpro:", err)
+		return 2
+	}
+
+	key, url, err := credentials(getenv, profile, config)
+	if err != nil {
+		fmt.Fprintln(stderr, "kpro:", err)
+		return 1
+	}
+	if baseURL != "" {
+		url = baseURL
+	}
+	opts := []katapultpro.ClientOption{katapultpro.WithRateLimit(rate)}
+	if url != "" {
+		opts = append(opts, katapultpro.WithBaseURL(url))
+	}
+	if c.client, err = katapultpro.NewClient(key, opts...); err != nil {
+		fmt.Fprintln(stderr, "kpro: no API key: set KATAPULT_API_KEY or configure a profile")
+		return 1
+	}
+
+	result, err := c.dispatch(ctx, pos[0], pos[1], pos[2:])
+	if err == nil && result != nil {
+		err = c.print(result, output, fields, q)
+	}
+	if err != nil {
+		var ue *usageError
+		if errors.As(err, &ue) {
+			fmt.Fprintln(stderr, "kpro:", err)
+			return 2
+		}
+		fmt.Fprintln(stderr, "kpro:", err)
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed parses flags that may appear before, between, or after positional arguments.
+// Only defined flags are treated as flags, because IDs may start with '-'; "--" ends flag parsing.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var flags, pos []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			pos = append(pos, args[i+1:]...)
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		name, _, hasValue := strings.Cut(name, "=")
+		f := fs.Lookup(name)
+		if !strings.HasPrefix(arg, "-") || (f == nil && name != "h" && name != "help") {
+			pos = append(pos, arg)
+			continue
+		}
+		if f == nil || hasValue || isBoolFlag(f) || i+1 == len(args) {
+			flags = append(flags, arg)
+			continue
+		}
+		// Join the value so the flag package cannot mistake an ID like "-abc" for a flag.
+		i++
+		flags = append(flags, "-"+name+"="+args[i])
+	}
+	if err := fs.Parse(flags); err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+func (c *cli) print(result any, output, fields, q string) error {
+	v, err := toGeneric(result)
+	if err != nil {
+		return err
+	}
+	if q != "" {
+		if v, err = query(v, q); err != nil {
+			return usagef("%v", err)
+		}
+	}
+	var cols []string
+	if fields != "" {
+		cols = strings.Split(fields, ",")
+		v = project(v, cols)
+	}
+	return render(c.stdout, output, v, cols)
+}
+
+// config is the kpro config file.
+type config struct {
+	DefaultProfile string             `json:"default_profile"`
+	Profiles       map[string]profile `json:"profiles"`
+}
+
+type profile struct {
+	APIKey  string `json:"api_key"`
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+func defaultConfigPath(getenv func(string) string) string {
+	if dir := getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "kpro", "config.json")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kpro", "config.json")
+}
+
+// credentials resolves the API key and base URL: an explicit profile, then KATAPULT_API_KEY,
+// then the config file's default profile.
+func credentials(getenv func(string) string, name, path string) (key, baseURL string, err error) {
+	if name == "" {
+		name = getenv("KPRO_PROFILE")
+	}
+	if name == "" {
+		if key := getenv("KATAPULT_API_KEY"); key != "" {
+			return key, getenv("KATAPULT_BASE_URL"), nil
+		}
+	}
+	if path == "" {
+		path = defaultConfigPath(getenv)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && name == "" {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("read config: %w", err)
+	}
+	var cfg config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return "", "", fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if name == "" {
+		name = cfg.DefaultProfile
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		if name == "" {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("profile %q not found in %s", name, path)
+	}
+	return p.APIKey, p.BaseURL, nil
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
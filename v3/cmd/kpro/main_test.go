@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+type kpro struct {
+	t   *testing.T
+	env map[string]string
+}
+
+// run invokes the command and returns stdout, failing the test on a nonzero exit.
+func (k kpro) run(stdin string, args ...string) string {
+	k.t.Helper()
+	out, errOut, code := k.exec(stdin, args...)
+	if code != 0 {
+		k.t.Fatalf("kpro %s: exit %d: %s", strings.Join(args, " "), code, errOut)
+	}
+	return out
+}
+
+func (k kpro) exec(stdin string, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr, func(key string) string { return k.env[key] })
+	return stdout.String(), stderr.String(), code
+}
+
+func TestRun_NodesCRUDAndOutput(t *testing.T) {
+	srv := katapulttest.NewServer(katapulttest.WithAPIKey("secret"))
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "CLI job"})
+	k := kpro{t: t, env: map[string]string{"KATAPULT_API_KEY": "secret", "KATAPULT_BASE_URL": srv.URL, "KATAPULT_JOB_ID": job.ID}}
+
+	out := k.run(`{"latitude":40,"longitude":-111,"add_attributes":{"pole_tag":"T-1"}}`, "nodes", "create", "-rate", "0")
+	var node katapultpro.Node
+	if err := json.Unmarshal([]byte(out), &node); err != nil || node.ID == "" {
+		t.Fatalf("create output %q: %v", out, err)
+	}
+
+	out = k.run("", "nodes", "list", "-q", ".[].id", "-o", "yaml")
+	if want := "- " + yamlScalar(node.ID) + "\n"; out != want {
+		t.Errorf("list yaml = %q", out)
+	}
+
+	k.run(`{"latitude":41}`, "nodes", "update", node.ID, "-only-if-exists")
+	out = k.run("", "nodes", "get", node.ID, "-fields", "id,latitude", "-o", "table")
+	if !strings.Contains(out, "latitude  41") {
+		t.Errorf("table output:\n%s", out)
+	}
+
+	if out := k.run("", "nodes", "delete", node.ID); out != "" {
+		t.Errorf("delete printed %q", out)
+	}
+	if _, errOut, code := k.exec("", "nodes", "get", node.ID); code != 1 || !strings.Contains(errOut, "404") {
+		t.Errorf("get deleted node: exit %d, stderr %q", code, errOut)
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	k := kpro{t: t, env: map[string]string{"KATAPULT_API_KEY": "k", "KATAPULT_BASE_URL": "http://127.0.0.1:1"}}
+	for _, args := range [][]string{
+		{"nodes"},
+		{"widgets", "list"},
+		{"nodes", "list"}, // no -job
+		{"nodes", "create", "-job", "j"},
+		{"jobs", "get"},
+		{"photos", "download", "p", "-job", "j", "-size", "huge"},
+		{"-o"},
+		{"jobs", "list", "-o", "xml"}, // rejected before the (unreachable) API is called
+	} {
+		if _, _, code := k.exec(`{"bogus":1}`, args...); code != 2 {
+			t.Errorf("kpro %v: exit %d, want 2", args, code)
+		}
+	}
+}
+
+func TestCredentials_Profiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := `{"default_profile":"work","profiles":{"work":{"api_key":"wk"},"test":{"api_key":"tk","base_url":"http://test"}}}`
+	if err := os.WriteFile(path, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"KATAPULT_API_KEY": "env"}
+	getenv := func(k string) string { return env[k] }
+
+	for _, tc := range []struct{ profile, key, url string }{
+		{"", "env", ""},
+		{"test", "tk", "http://test"},
+	} {
+		key, url, err := credentials(getenv, tc.profile, path)
+		if err != nil || key != tc.key || url != tc.url {
+			t.Errorf("profile %q: got %q %q %v", tc.profile, key, url, err)
+		}
+	}
+	delete(env, "KATAPULT_API_KEY")
+	if key, _, _ := credentials(getenv, "", path); key != "wk" {
+		t.Errorf("default profile key = %q", key)
+	}
+	if _, _, err := credentials(getenv, "missing", path); err == nil {
+		t.Error("expected error for missing profile")
+	}
+}
+
+func TestRun_JobsLint(t *testing.T) {
+	srv := katapulttest.NewServer(katapulttest.WithAPIKey("secret"))
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Lint job"})
+	srv.AddNode(job.ID, katapultpro.Node{ID: "n1"})
+	k := kpro{t: t, env: map[string]string{"KATAPULT_API_KEY": "secret", "KATAPULT_BASE_URL": srv.URL}}
+
+	rules := filepath.Join(t.TempDir(), "lint.json")
+	if err := os.WriteFile(rules, []byte(`{"required_node_attributes":["pole_tag"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out := k.run("", "jobs", "lint", job.ID, "-rules", rules, "-rate", "0", "-q", ".findings[0].check")
+	if out != "\"node-required-attributes\"\n" {
+		t.Errorf("lint output %q", out)
+	}
+	if _, _, code := k.exec("", "jobs", "lint", job.ID, "-rules", rules+".missing"); code != 1 {
+		t.Errorf("missing rules file: exit %d", code)
+	}
+}
+
+func TestRun_JobsLintFix(t *testing.T) {
+	srv := katapulttest.NewServer(katapulttest.WithAPIKey("secret"))
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Fix job"})
+	trace := srv.AddTrace(job.ID, katapultpro.Trace{TraceType: "cable"})
+	k := kpro{t: t, env: map[string]string{"KATAPULT_API_KEY": "secret", "KATAPULT_BASE_URL": srv.URL, "KATAPULT_JOB_ID": job.ID}}
+
+	if out := k.run("", "jobs", "lint", "-fix", "-q", ".[0].status"); out != "\"dry_run\"\n" {
+		t.Errorf("dry run output %q", out)
+	}
+	audit := filepath.Join(t.TempDir(), "audit.jsonl")
+	k.run("", "jobs", "lint", "-fix", "-commit", "-audit", audit)
+	b, err := os.ReadFile(audit)
+	if err != nil || !strings.Contains(string(b), `"status":"applied"`) || !strings.Contains(string(b), trace.ID) {
+		t.Errorf("audit log %q, %v", b, err)
+	}
+	if out := k.run("", "jobs", "lint", "-fix"); out != "[]\n" {
+		t.Errorf("second run output %q", out)
+	}
+}
+
+func TestRun_JobsTreeAndArchive(t *testing.T) {
+	srv := katapulttest.NewServer(katapulttest.WithAPIKey("secret"))
+	defer srv.Close()
+	old := srv.AddJob(katapultpro.Job{Name: "Old", ProjectFolder: "Utilities/2023", Metadata: map[string]interface{}{"last_upload": "2020-01-01"}})
+	srv.AddJob(katapultpro.Job{Name: "Other", ProjectFolder: "Telecom"})
+	k := kpro{t: t, env: map[string]string{"KATAPULT_API_KEY": "secret", "KATAPULT_BASE_URL": srv.URL}}
+
+	if out := k.run("", "jobs", "tree", "Utilities", "-q", ".folders[0].jobs[0].id"); out != "\""+old.ID+"\"\n" {
+		t.Errorf("tree output %q", out)
+	}
+	if out := k.run("", "jobs", "tree", "-q", ".counts.jobs"); out != "2\n" {
+		t.Errorf("tree counts %q", out)
+	}
+	args := []string{"jobs", "archive", "Utilities", "-recursive", "-older-than", "365d", "-date-attribute", "last_upload", "-q", ".results[0].status"}
+	if out := k.run("", args...); out != "\"dry_run\"\n" {
+		t.Errorf("dry run output %q", out)
+	}
+	if out := k.run("", append(args, "-commit")...); out != "\"archived\"\n" {
+		t.Errorf("commit output %q", out)
+	}
+	if _, _, code := k.exec("", "jobs", "archive", "Utilities", "-older-than", "90d"); code != 2 {
+		t.Errorf("-older-than without -date-attribute: exit %d", code)
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// toGeneric converts an SDK value to plain JSON values (map[string]any, []any, json.Number, ...).
+func toGeneric(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// query evaluates a jq-like path: ".a.b", ".[]" (iterate), ".[N]" (index). An iterated path
+// yields an array of the results.
+func query(v any, path string) (any, error) {
+	path = strings.TrimSpace(path)
+	if path == "" || path == "." {
+		return v, nil
+	}
+	if !strings.HasPrefix(path, ".") {
+		return nil, fmt.Errorf("query %q: must start with '.'", path)
+	}
+	rest := path[1:]
+	var seg string
+	switch {
+	case strings.HasPrefix(rest, "["):
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("query %q: missing ']'", path)
+		}
+		seg, rest = rest[:end+1], rest[end+1:]
+	default:
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		seg, rest = rest[:end], rest[end:]
+	}
+	if rest != "" && !strings.HasPrefix(rest, ".") {
+		rest = "." + rest
+	}
+	switch {
+	case seg == "[]":
+		var items []any
+		switch t := v.(type) {
+		case []any:
+			items = t
+		case map[string]any:
+			for _, k := range sortedKeys(t) {
+				items = append(items, t[k])
+			}
+		default:
+			return nil, fmt.Errorf("query %q: cannot iterate over %T", path, v)
+		}
+		out := make([]any, 0, len(items))
+		for _, item := range items {
+			r, err := query(item, rest)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+		return out, nil
+	case strings.HasPrefix(seg, "["):
+		n, err := strconv.Atoi(seg[1 : len(seg)-1])
+		if err != nil {
+			return nil, fmt.Errorf("query %q: bad index %s", path, seg)
+		}
+		arr, _ := v.([]any)
+		if n < 0 {
+			n += len(arr)
+		}
+		if n < 0 || n >= len(arr) {
+			return query(nil, rest)
+		}
+		return query(arr[n], rest)
+	default:
+		m, _ := v.(map[string]any)
+		return query(m[seg], rest)
+	}
+}
+
+// project keeps only the given dotted fields of an object, or of each object in an array.
+func project(v any, fields []string) any {
+	if arr, ok := v.([]any); ok {
+		out := make([]any, len(arr))
+		for i, item := range arr {
+			out[i] = project(item, fields)
+		}
+		return out
+	}
+	out := map[string]any{}
+	for _, f := range fields {
+		r, _ := query(v, "."+f)
+		out[f] = r
+	}
+	return out
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// render writes v in the given format: json, yaml, or table. fields orders table columns.
+func render(w io.Writer, format string, v any, fields []string) error {
+	switch format {
+	case "", "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "yaml":
+		var b strings.Builder
+		writeYAML(&b, v, "")
+		_, err := io.WriteString(w, b.String())
+		return err
+	case "table":
+		return writeTable(w, v, fields)
+	}
+	return checkFormat(format)
+}
+
+// checkFormat returns a usage error for an output format render does not know.
+func checkFormat(format string) error {
+	switch format {
+	case "", "json", "yaml", "table":
+		return nil
+	}
+	return usagef("unknown output format %q (want json, yaml or table)", format)
+}
+
+// writeYAML writes v as a block-style YAML document.
+func writeYAML(b *strings.Builder, v any, indent string) {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(t) == 0 {
+			b.WriteString(indent + "{}\n")
+			return
+		}
+		for _, k := range sortedKeys(t) {
+			b.WriteString(indent + yamlScalar(k) + ":")
+			writeYAMLValue(b, t[k], indent)
+		}
+	case []any:
+		if len(t) == 0 {
+			b.WriteString(indent + "[]\n")
+			return
+		}
+		for _, item := range t {
+			b.WriteString(indent + "-")
+			writeYAMLItem(b, item, indent)
+		}
+	default:
+		b.WriteString(indent + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLValue writes the value of a mapping key whose "key:" has already been written.
+func writeYAMLValue(b *strings.Builder, v any, indent string) {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(t) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, t, indent+"  ")
+	case []any:
+		if len(t) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, t, indent)
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLItem writes a sequence item whose "-" has already been written.
+func writeYAMLItem(b *strings.Builder, v any, indent string) {
+	switch t := v.(type) {
+	case map[string]any:
+		if len(t) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		for i, k := range sortedKeys(t) {
+			if i == 0 {
+				b.WriteString(" " + yamlScalar(k) + ":")
+			} else {
+				b.WriteString(indent + "  " + yamlScalar(k) + ":")
+			}
+			writeYAMLValue(b, t[k], indent+"  ")
+		}
+	case []any:
+		if len(t) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAML(b, t, indent+"  ")
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+// yamlScalar formats a scalar, quoting strings that YAML would otherwise read as another type.
+func yamlScalar(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(t)
+	case json.Number:
+		return t.String()
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	case string:
+		if yamlNeedsQuote(t) {
+			return strconv.Quote(t)
+		}
+		return t
+	}
+	return strconv.Quote(fmt.Sprint(v))
+}
+
+func yamlNeedsQuote(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.ContainsAny(s, "\n\r\t") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTable writes an array of objects as rows, or a single object as KEY/VALUE rows.
+func writeTable(w io.Writer, v any, fields []string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	switch t := v.(type) {
+	case []any:
+		cols := fields
+		if len(cols) == 0 {
+			cols = tableColumns(t)
+		}
+		upper := make([]string, len(cols))
+		for i, c := range cols {
+			upper[i] = strings.ToUpper(c)
+		}
+		fmt.Fprintln(tw, strings.Join(upper, "\t"))
+		for _, row := range t {
+			if _, ok := row.(map[string]any); !ok {
+				fmt.Fprintln(tw, cellText(row))
+				continue
+			}
+			cells := make([]string, len(cols))
+			for i, c := range cols {
+				cell, _ := query(row, "."+c)
+				cells[i] = cellText(cell)
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	case map[string]any:
+		keys := fields
+		if len(keys) == 0 {
+			keys = sortedKeys(t)
+		}
+		fmt.Fprintln(tw, "KEY\tVALUE")
+		for _, k := range keys {
+			cell, _ := query(t, "."+k)
+			fmt.Fprintf(tw, "%s\t%s\n", k, cellText(cell))
+		}
+	default:
+		fmt.Fprintln(tw, cellText(v))
+	}
+	return tw.Flush()
+}
+
+// tableColumns returns the union of top-level keys of the rows, with "id" first.
+func tableColumns(rows []any) []string {
+	seen := map[string]any{}
+	for _, r := range rows {
+		if m, ok := r.(map[string]any); ok {
+			for k := range m {
+				seen[k] = nil
+			}
+		}
+	}
+	cols := sortedKeys(seen)
+	for i, c := range cols {
+		if c == "id" {
+			cols = append(append([]string{"id"}, cols[:i]...), cols[i+1:]...)
+			break
+		}
+	}
+	if len(cols) == 0 {
+		cols = []string{"value"}
+	}
+	return cols
+}
+
+func cellText(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case map[string]any, []any:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+	return yamlScalar(v)
+}
@@ -0,0 +1,50 @@
+package katapultpro
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/singleflight"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/transport"
+)
+
+// WithRequestCoalescing makes concurrent identical GET requests (same path and query) share one
+// HTTP round trip, and so one token charge. Each caller decodes the shared response into its own
+// value, so results are never aliased between callers. Requests are only shared while one is in
+// flight; nothing is cached. A caller whose context ends stops waiting without affecting the others.
+func WithRequestCoalescing() ClientOption {
+	return func(c *Client) {
+		c.inflight = &singleflight.Group[response]{}
+	}
+}
+
+// response is a raw API response, as shared between coalesced requests.
+type response struct {
+	statusCode int
+	body       []byte
+}
+
+// roundTrip sends a request with transport.Do, sharing GET requests without a body with any
+// identical one in flight when coalescing is on.
+func (c *Client) roundTrip(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (int, []byte, error) {
+	if c.inflight == nil || method != http.MethodGet || body != nil {
+		return transport.Do(ctx, method, c.baseURL, path, query, body, contentType, c.apiKey, c.httpClient)
+	}
+	key := strings.TrimPrefix(path, "/") + "?" + query.Encode()
+	for {
+		r, err, shared := c.inflight.Do(ctx, key, func() (response, error) {
+			statusCode, slurp, err := transport.Do(ctx, method, c.baseURL, path, query, nil, contentType, c.apiKey, c.httpClient)
+			return response{statusCode: statusCode, body: slurp}, err
+		})
+		// The shared request ended because the caller that started it gave up, but this caller
+		// has not, so send it again.
+		if shared && ctx.Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			continue
+		}
+		return r.statusCode, r.body, err
+	}
+}
@@ -0,0 +1,107 @@
+package katapultpro_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// gatedServer answers photo lists once release is closed, counting the requests it received.
+func gatedServer(t *testing.T) (srv *httptest.Server, calls *atomic.Int32, release chan struct{}) {
+	calls, release = &atomic.Int32{}, make(chan struct{})
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":[{"id":"p1","tags":["a"]}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, calls, release
+}
+
+func TestWithRequestCoalescing(t *testing.T) {
+	srv, calls, release := gatedServer(t)
+	client, err := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL), katapultpro.WithRequestCoalescing())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	const n = 8
+	results := make([][]katapultpro.Photo, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = client.ListPhotos(ctx, "job-1")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // Let every caller join the request in flight
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+	for i := range n {
+		if errs[i] != nil || len(results[i]) != 1 || results[i][0].ID != "p1" {
+			t.Fatalf("caller %d: %v, %v", i, results[i], errs[i])
+		}
+	}
+	results[0][0].ID = "changed"
+	if results[1][0].ID != "p1" {
+		t.Error("callers share one decoded result")
+	}
+
+	// Requests that don't overlap are sent separately.
+	if _, err := client.ListPhotos(ctx, "job-1"); err != nil || calls.Load() != 2 {
+		t.Errorf("later request: %v, %d calls", err, calls.Load())
+	}
+}
+
+func TestWithRequestCoalescing_LeaderCanceled(t *testing.T) {
+	srv, calls, release := gatedServer(t)
+	client, err := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL), katapultpro.WithRequestCoalescing())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := client.ListPhotos(leaderCtx, "job-1")
+		leaderErr <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := client.ListPhotos(context.Background(), "job-1")
+		followerErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; err == nil {
+		t.Error("canceled leader: expected error")
+	}
+	close(release)
+	if err := <-followerErr; err != nil {
+		t.Errorf("follower: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server saw %d requests, want 2 (the follower retries)", got)
+	}
+}
@@ -10,7 +10,7 @@
 // (e.g. /v3/jobs, /v3/jobs/:job_id/nodes). The client unwraps the API's response envelope
 // ({ status, data, meta }) so your out value receives the contents of data. LastMeta on
 // the client is updated after each request with token_count and last_refill_time for
-// rate-limit awareness.
+// rate-limit awareness; read it with Meta when other goroutines share the client.
 //
 // # Usage
 //
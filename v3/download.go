@@ -0,0 +1,189 @@
+package katapultpro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/photos"
+)
+
+// DefaultPhotoURLTTL is how long a signed photo URL is reused when the URL itself carries no expiry.
+const DefaultPhotoURLTTL = 30 * time.Minute
+
+// photoURLMargin is subtracted from a signed URL's expiry so it is not used just as it lapses.
+const photoURLMargin = 30 * time.Second
+
+// WithPhotoURLTTL sets how long signed photo URLs are cached when the URL carries no expiry.
+// Zero or negative disables caching. The default is DefaultPhotoURLTTL.
+func WithPhotoURLTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) { c.photoURLs.ttl = ttl }
+}
+
+// DoStream performs a request and returns the raw response; the caller must close its body.
+// A relative path is resolved against the base URL and authenticated like Do; an absolute URL
+// (such as a signed photo URL) is requested as is. Non-2xx responses are returned, not converted to errors.
+func (c *Client) DoStream(ctx context.Context, method, path string, query url.Values, header http.Header) (*http.Response, error) {
+	var u *url.URL
+	if abs, err := url.Parse(path); err == nil && abs.IsAbs() {
+		u = abs
+	} else {
+		u = c.baseURL.JoinPath(strings.Split(strings.TrimPrefix(path, "/"), "/")...)
+		if query == nil {
+			query = url.Values{}
+		}
+		query.Set("api_key", c.apiKey)
+	}
+	if len(query) > 0 {
+		q := u.Query()
+		for k, v := range query {
+			q[k] = v
+		}
+		u.RawQuery = q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+	return resp, nil
+}
+
+// PhotoURL returns a signed, temporary URL for a photo image, using the v2 photoURL endpoint with the
+// client's base URL, key, and HTTP client. URLs are cached per photo and size until they expire.
+func (c *Client) PhotoURL(ctx context.Context, jobID, photoID string, size PhotoSize) (string, error) {
+	key := jobID + "/" + photoID + "/" + string(size)
+	if u, ok := c.photoURLs.get(key); ok {
+		return u, nil
+	}
+	q := url.Values{}
+	if size.IsValid() {
+		q.Set("file_size", size.String())
+	}
+	resp, err := c.DoStream(ctx, http.MethodGet, "v2/jobs/"+jobID+"/photoURL/"+photoID, q, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", &APIError{StatusCode: resp.StatusCode, Message: string(body)}
+	}
+	var out struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if out.URL == "" {
+		return "", fmt.Errorf("decode response: no url for photo %s", photoID)
+	}
+	c.photoURLs.put(key, out.URL)
+	return out.URL, nil
+}
+
+// InvalidatePhotoURL drops a cached signed URL, e.g. after the storage host rejected it.
+func (c *Client) InvalidatePhotoURL(jobID, photoID string, size PhotoSize) {
+	c.photoURLs.remove(jobID + "/" + photoID + "/" + string(size))
+}
+
+// DownloadPhoto streams the photo image at size to w and returns the number of bytes written (v3).
+func (c *Client) DownloadPhoto(ctx context.Context, jobID, photoID string, size PhotoSize, w io.Writer) (int64, error) {
+	return photos.NewClient(c, jobID).Photo(photoID).Download(ctx, size, w)
+}
+
+// urlCache holds signed URLs until they expire. The zero value is ready to use with no TTL.
+type urlCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedURL
+	now     func() time.Time
+}
+
+type cachedURL struct {
+	url     string
+	expires time.Time
+}
+
+func (c *urlCache) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+func (c *urlCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.clock().Before(e.expires) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return e.url, true
+}
+
+func (c *urlCache) put(key, u string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl <= 0 {
+		return
+	}
+	now := c.clock()
+	expires := now.Add(c.ttl)
+	if t, ok := signedExpiry(u); ok && t.Add(-photoURLMargin).Before(expires) {
+		expires = t.Add(-photoURLMargin)
+	}
+	if !now.Before(expires) {
+		return
+	}
+	if c.entries == nil {
+		c.entries = map[string]cachedURL{}
+	}
+	c.entries[key] = cachedURL{url: u, expires: expires}
+}
+
+func (c *urlCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// signedExpiry reads the expiry of a signed storage URL: Expires (Unix seconds) or
+// X-Goog-Date plus X-Goog-Expires (seconds).
+func signedExpiry(raw string) (time.Time, bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	q := u.Query()
+	if s := q.Get("Expires"); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Unix(n, 0), true
+		}
+	}
+	if d, e := q.Get("X-Goog-Date"), q.Get("X-Goog-Expires"); d != "" && e != "" {
+		start, err1 := time.Parse("20060102T150405Z", d)
+		secs, err2 := strconv.ParseInt(e, 10, 64)
+		if err1 == nil && err2 == nil {
+			return start.Add(time.Duration(secs) * time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -29,6 +29,15 @@ const (
 	JobPathSharing       = jobs.JobPathSharing
 )
 
+// Job sharing permissions (re-exported from jobs).
+type Permission = jobs.Permission
+
+const (
+	PermissionRead  = jobs.PermissionRead
+	PermissionWrite = jobs.PermissionWrite
+	PermissionAdmin = jobs.PermissionAdmin
+)
+
 // Photo association enums (re-exported from photos).
 type (
 	PhotoAssociationQuery  = photos.PhotoAssociationQuery
@@ -45,6 +54,35 @@ var (
 	PhotoAssociationTrue = photos.PhotoAssociationTrue
 )
 
+// Photo image size variants (re-exported from photos).
+type PhotoSize = photos.PhotoSize
+
+const (
+	PhotoSizeFull       = photos.PhotoSizeFull
+	PhotoSizeExtraLarge = photos.PhotoSizeExtraLarge
+	PhotoSizeLarge      = photos.PhotoSizeLarge
+	PhotoSizeSmall      = photos.PhotoSizeSmall
+	PhotoSizeTiny       = photos.PhotoSizeTiny
+)
+
+// Photo element types (re-exported from photos). Types outside the catalog are valid too.
+type ElementType = photos.ElementType
+
+const (
+	ElementTypeWire       = photos.ElementTypeWire
+	ElementTypePoleTop    = photos.ElementTypePoleTop
+	ElementTypePoleHeight = photos.ElementTypePoleHeight
+)
+
+// Element attribute kinds (re-exported from photos).
+type AttributeKind = photos.AttributeKind
+
+const (
+	AttributeBool   = photos.AttributeBool
+	AttributeString = photos.AttributeString
+	AttributeNumber = photos.AttributeNumber
+)
+
 // PtrPhotoAssociationMain returns a pointer suitable for AssociatePhotoRequest.AssociationValue ("main").
 func PtrPhotoAssociationMain() *PhotoAssociationValue {
 	return photos.PtrPhotoAssociationMain()
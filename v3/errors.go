@@ -31,5 +31,10 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("katapultpro api error %d: %s", e.StatusCode, e.Message)
 }
 
+// Retryable reports whether the request may succeed if repeated: rate limiting (429) and server errors (5xx).
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
 // Ensure APIError is recognized by errors.As.
 var _ error = (*APIError)(nil)
@@ -0,0 +1,208 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
+)
+
+// Result statuses.
+const (
+	StatusDryRun   = "dry_run"  // Would be archived; Archiver.Commit is off
+	StatusArchived = "archived" // Archived
+	StatusDeclined = "declined" // Archiver.Confirm said no
+	StatusSkipped  = "skipped"  // Not archived; see Reason
+	StatusFailed   = "failed"   // UpdateJobStatus failed; see Error
+)
+
+// StatusUpdater sets a job's status. *katapultpro.Client implements it.
+type StatusUpdater interface {
+	UpdateJobStatus(ctx context.Context, jobID string, status katapultpro.JobStatus) error
+}
+
+// Selection picks the active jobs of a folder to archive.
+type Selection struct {
+	Folder    string // Path of the folder; empty for the root
+	Recursive bool   // Include subfolders
+	// OlderThan, when set, keeps only jobs whose Date is at least this old. Jobs without a date
+	// are skipped.
+	OlderThan time.Duration
+	Date      func(katapultpro.Job) (time.Time, bool)
+}
+
+// Archiver archives selected jobs. It is a dry run unless Commit is set.
+type Archiver struct {
+	Client StatusUpdater
+	Commit bool
+	// Confirm, when set, is asked once with the jobs about to be archived before anything is
+	// changed. Returning false declines them all. It is not called on dry runs or with no jobs.
+	Confirm func(ctx context.Context, jobs []katapultpro.Job) (bool, error)
+	Now     func() time.Time // Defaults to time.Now
+}
+
+// ArchiveResult is the outcome for one job.
+type ArchiveResult struct {
+	JobID  string    `json:"job_id"`
+	Name   string    `json:"name"`
+	Folder string    `json:"folder"`
+	Date   time.Time `json:"date,omitzero"`
+	Status string    `json:"status"`
+	Reason string    `json:"reason,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ArchiveReport lists the outcome for every active job the selection considered.
+type ArchiveReport struct {
+	Folder  string          `json:"folder"`
+	Cutoff  time.Time       `json:"cutoff,omitzero"`
+	Results []ArchiveResult `json:"results"`
+}
+
+// Count returns the number of results with the given status.
+func (r *ArchiveReport) Count(status string) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Archive archives the active jobs sel picks from the tree under root. Failures do not stop the
+// run; they are recorded in the report and returned joined. The tree is not updated.
+func (a *Archiver) Archive(ctx context.Context, root *Folder, sel Selection) (*ArchiveReport, error) {
+	f := root.Find(sel.Folder)
+	if f == nil {
+		return nil, fmt.Errorf("folders: no folder %q", sel.Folder)
+	}
+	if sel.OlderThan > 0 && sel.Date == nil {
+		return nil, errors.New("folders: OlderThan needs a Date function")
+	}
+	now := time.Now
+	if a.Now != nil {
+		now = a.Now
+	}
+	report := &ArchiveReport{Folder: f.Path, Results: []ArchiveResult{}}
+	if sel.OlderThan > 0 {
+		report.Cutoff = now().Add(-sel.OlderThan)
+	}
+
+	var jobs []katapultpro.Job
+	var picked []int
+	add := func(d *Folder) error {
+		for _, j := range d.Jobs {
+			if j.Status == katapultpro.JobStatusArchived {
+				continue
+			}
+			res := ArchiveResult{JobID: j.ID, Name: j.Name, Folder: d.Path, Status: StatusDryRun}
+			if sel.OlderThan > 0 {
+				date, ok := sel.Date(j)
+				switch {
+				case !ok:
+					res.Status, res.Reason = StatusSkipped, "no date"
+				case date.After(report.Cutoff):
+					res.Date, res.Status, res.Reason = date, StatusSkipped, "too recent"
+				default:
+					res.Date = date
+				}
+			}
+			if res.Status == StatusDryRun {
+				jobs = append(jobs, j)
+				picked = append(picked, len(report.Results))
+			}
+			report.Results = append(report.Results, res)
+		}
+		return nil
+	}
+	if sel.Recursive {
+		_ = f.Walk(add)
+	} else {
+		_ = add(f)
+	}
+	if !a.Commit || len(jobs) == 0 {
+		return report, nil
+	}
+
+	if a.Confirm != nil {
+		ok, err := a.Confirm(ctx, jobs)
+		if err != nil {
+			return nil, fmt.Errorf("folders: confirm: %w", err)
+		}
+		if !ok {
+			for _, i := range picked {
+				report.Results[i].Status = StatusDeclined
+			}
+			return report, nil
+		}
+	}
+	var errs []error
+	for _, i := range picked {
+		res := &report.Results[i]
+		if err := a.Client.UpdateJobStatus(ctx, res.JobID, katapultpro.JobStatusArchived); err != nil {
+			res.Status, res.Error = StatusFailed, err.Error()
+			errs = append(errs, fmt.Errorf("folders: archive job %s: %w", res.JobID, err))
+			continue
+		}
+		res.Status = StatusArchived
+	}
+	return report, errors.Join(errs...)
+}
+
+// WriteText writes a summary line and one line per job.
+func (r *ArchiveReport) WriteText(w io.Writer) error {
+	folder := r.Folder
+	if folder == "" {
+		folder = "/"
+	}
+	if _, err := fmt.Fprintf(w, "archive %s: %d archived, %d dry run, %d skipped, %d declined, %d failed\n", folder,
+		r.Count(StatusArchived), r.Count(StatusDryRun), r.Count(StatusSkipped), r.Count(StatusDeclined), r.Count(StatusFailed)); err != nil {
+		return err
+	}
+	for _, res := range r.Results {
+		line := fmt.Sprintf("  %-8s %s  %s", res.Status, res.JobID, res.Name)
+		if !res.Date.IsZero() {
+			line += "  " + res.Date.Format(time.DateOnly)
+		}
+		if res.Reason != "" {
+			line += "  (" + res.Reason + ")"
+		}
+		if res.Error != "" {
+			line += "  " + res.Error
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetadataDate returns a Selection.Date that reads the job metadata attribute key. Numbers are Unix
+// times in seconds, or milliseconds when too large to be seconds; strings may also be RFC 3339
+// timestamps or YYYY-MM-DD dates.
+func MetadataDate(key string) func(katapultpro.Job) (time.Time, bool) {
+	return func(j katapultpro.Job) (time.Time, bool) {
+		switch v := j.Metadata[key].(type) {
+		case float64:
+			return jobs.UnixTime(v), true
+		case string:
+			v = strings.TrimSpace(v)
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				return jobs.UnixTime(n), true
+			}
+			for _, layout := range []string{time.RFC3339, time.DateOnly} {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t, true
+				}
+			}
+		}
+		return time.Time{}, false
+	}
+}
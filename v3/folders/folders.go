@@ -0,0 +1,239 @@
+// Package folders shows jobs as the project folder tree they are filed in, with per-folder counts
+// and status rollups, and archives jobs a folder at a time.
+//
+//	root, err := folders.Load(ctx, client) // every job, archived included
+//	f := root.Find("Utilities/2023")
+//	fmt.Println(f.Counts().Active)
+//
+//	report, err := (&folders.Archiver{Client: client, Commit: true}).Archive(ctx, root, folders.Selection{
+//		Folder: "Utilities/2023", Recursive: true,
+//		OlderThan: 90 * 24 * time.Hour, Date: folders.MetadataDate("last_upload"),
+//	})
+//
+// Folders are the "/"-separated Job.ProjectFolder paths; jobs without one are in the root folder.
+// Job list entries do not contain the full job data, so Load fetches each job's folder, status,
+// done flag, and metadata separately.
+// The API does not report when a job was created or last changed, so selecting jobs by age reads a
+// date from each job with Selection.Date, such as a metadata attribute with MetadataDate.
+package folders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
+)
+
+// Folder is a node of the folder tree.
+type Folder struct {
+	Name    string            `json:"name"` // Last path segment; empty for the root
+	Path    string            `json:"path"` // Full path; empty for the root
+	Folders []*Folder         `json:"folders,omitempty"`
+	Jobs    []katapultpro.Job `json:"jobs,omitempty"` // Jobs filed directly in this folder, by name
+
+	parent *Folder
+}
+
+// MarshalJSON adds the folder's rolled-up Counts as "counts".
+func (f *Folder) MarshalJSON() ([]byte, error) {
+	type folder Folder
+	return json.Marshal(struct {
+		*folder
+		Counts Counts `json:"counts"`
+	}{(*folder)(f), f.Counts()})
+}
+
+// Counts are job counts rolled up over a folder and its subfolders.
+type Counts struct {
+	Folders  int `json:"folders"` // Subfolders at any depth
+	Jobs     int `json:"jobs"`
+	Active   int `json:"active"`
+	Archived int `json:"archived"`
+	Done     int `json:"done"`
+}
+
+// Build files jobs into a folder tree and returns its root. Subfolders and jobs are sorted by name.
+// The jobs need their ProjectFolder and Status, and Done and Metadata for Counts and Selection.Date;
+// entries from ListJobs lack them (see Load).
+func Build(jobs []katapultpro.Job) *Folder {
+	root := &Folder{}
+	for _, j := range jobs {
+		f := root
+		for _, seg := range segments(j.ProjectFolder) {
+			f = f.child(seg)
+		}
+		f.Jobs = append(f.Jobs, j)
+	}
+	root.sort()
+	return root
+}
+
+// jobPaths are the job fields Build, Counts, and Archiver read.
+var jobPaths = []katapultpro.JobPath{
+	katapultpro.JobPathName, katapultpro.JobPathProjectFolder, katapultpro.JobPathStatus,
+	katapultpro.JobPathDone, katapultpro.JobPathMetadata,
+}
+
+// Load lists every job the API key can see, archived ones included, fetches the fields the tree
+// needs for each with jobs.FetchAll, and builds the tree. c is usually a *katapultpro.Client; a
+// katapultmock.ClientAPI works in tests.
+func Load(ctx context.Context, c jobs.Fetcher) (*Folder, error) {
+	all, err := jobs.FetchAll(ctx, c, &katapultpro.ListJobsOptions{IncludeArchived: true}, jobPaths)
+	if err != nil {
+		return nil, fmt.Errorf("folders: %w", err)
+	}
+	return Build(all), nil
+}
+
+// segments splits a folder path, ignoring empty segments and surrounding space.
+func segments(path string) []string {
+	var out []string
+	for _, s := range strings.Split(path, "/") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func (f *Folder) child(name string) *Folder {
+	for _, c := range f.Folders {
+		if c.Name == name {
+			return c
+		}
+	}
+	path := name
+	if f.Path != "" {
+		path = f.Path + "/" + name
+	}
+	c := &Folder{Name: name, Path: path, parent: f}
+	f.Folders = append(f.Folders, c)
+	return c
+}
+
+func (f *Folder) sort() {
+	sort.Slice(f.Folders, func(i, j int) bool { return f.Folders[i].Name < f.Folders[j].Name })
+	sort.SliceStable(f.Jobs, func(i, j int) bool {
+		if f.Jobs[i].Name != f.Jobs[j].Name {
+			return f.Jobs[i].Name < f.Jobs[j].Name
+		}
+		return f.Jobs[i].ID < f.Jobs[j].ID
+	})
+	for _, c := range f.Folders {
+		c.sort()
+	}
+}
+
+// Parent returns the folder containing f, or nil for the root.
+func (f *Folder) Parent() *Folder { return f.parent }
+
+// Find returns the folder at path below f, or nil. The empty path is f itself.
+func (f *Folder) Find(path string) *Folder {
+	for _, seg := range segments(path) {
+		var next *Folder
+		for _, c := range f.Folders {
+			if c.Name == seg {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		f = next
+	}
+	return f
+}
+
+// SkipFolder can be returned by a Walk function to skip a folder's subfolders.
+var SkipFolder = errors.New("skip this folder")
+
+// Walk calls fn for f and each folder below it, parents before children, in name order. If fn
+// returns SkipFolder, the folder's subfolders are skipped; any other error stops the walk.
+func (f *Folder) Walk(fn func(*Folder) error) error {
+	err := f.walk(fn)
+	if err == SkipFolder {
+		return nil
+	}
+	return err
+}
+
+func (f *Folder) walk(fn func(*Folder) error) error {
+	if err := fn(f); err != nil {
+		return err
+	}
+	for _, c := range f.Folders {
+		if err := c.walk(fn); err != nil && err != SkipFolder {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllJobs returns the jobs in f and its subfolders, in walk order.
+func (f *Folder) AllJobs() []katapultpro.Job {
+	var out []katapultpro.Job
+	_ = f.Walk(func(d *Folder) error {
+		out = append(out, d.Jobs...)
+		return nil
+	})
+	return out
+}
+
+// Counts rolls up the jobs in f and its subfolders.
+func (f *Folder) Counts() Counts {
+	var n Counts
+	_ = f.Walk(func(d *Folder) error {
+		if d != f {
+			n.Folders++
+		}
+		for _, j := range d.Jobs {
+			n.Jobs++
+			switch j.Status {
+			case katapultpro.JobStatusArchived:
+				n.Archived++
+			default: // Jobs built without a status are counted as active
+				n.Active++
+			}
+			if j.Done {
+				n.Done++
+			}
+		}
+		return nil
+	})
+	return n
+}
+
+// WriteText writes the tree below f with rolled-up counts, one folder per line. With jobs set, the
+// jobs in each folder are listed too.
+func (f *Folder) WriteText(w io.Writer, jobs bool) error {
+	var err error
+	_ = f.Walk(func(d *Folder) error {
+		depth := len(segments(d.Path)) - len(segments(f.Path))
+		name := d.Name
+		if d == f && name == "" {
+			name = "/"
+		}
+		n := d.Counts()
+		_, err = fmt.Fprintf(w, "%s%s  (%d jobs: %d active, %d archived)\n", strings.Repeat("  ", depth), name, n.Jobs, n.Active, n.Archived)
+		if err == nil && jobs {
+			for _, j := range d.Jobs {
+				status := j.Status
+				if status == "" {
+					status = katapultpro.JobStatusActive
+				}
+				if _, err = fmt.Fprintf(w, "%s- %s  %s  %s\n", strings.Repeat("  ", depth+1), j.Name, j.ID, status); err != nil {
+					break
+				}
+			}
+		}
+		return err
+	})
+	return err
+}
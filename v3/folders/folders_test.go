@@ -0,0 +1,178 @@
+package folders_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/folders"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapultmock"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+func TestBuild(t *testing.T) {
+	root := folders.Build([]katapultpro.Job{
+		{ID: "j1", Name: "Zeta", ProjectFolder: "Utilities/2023"},
+		{ID: "j2", Name: "Alpha", ProjectFolder: "/Utilities/2023/", Status: katapultpro.JobStatusArchived},
+		{ID: "j3", Name: "Beta", ProjectFolder: "Utilities", Done: true},
+		{ID: "j4", Name: "Loose"},
+		{ID: "j5", Name: "Gamma", ProjectFolder: "Telecom/East"},
+	})
+	var paths []string
+	_ = root.Walk(func(f *folders.Folder) error {
+		paths = append(paths, f.Path)
+		if f.Path == "Telecom" {
+			return folders.SkipFolder
+		}
+		return nil
+	})
+	if got := strings.Join(paths, ","); got != ",Telecom,Utilities,Utilities/2023" {
+		t.Errorf("walk = %q", got)
+	}
+
+	f := root.Find("Utilities/2023")
+	if f == nil || f.Jobs[0].ID != "j2" || f.Parent().Path != "Utilities" {
+		t.Fatalf("Find = %+v", f)
+	}
+	if n := root.Find("Utilities").Counts(); n != (folders.Counts{Folders: 1, Jobs: 3, Active: 2, Archived: 1, Done: 1}) {
+		t.Errorf("counts = %+v", n)
+	}
+	if root.Find("Nope") != nil || len(root.AllJobs()) != 5 {
+		t.Error("Find(Nope) or AllJobs")
+	}
+
+	var buf bytes.Buffer
+	if err := root.Find("Utilities").WriteText(&buf, true); err != nil {
+		t.Fatal(err)
+	}
+	want := `Utilities  (3 jobs: 2 active, 1 archived)
+  - Beta  j3  active
+  2023  (2 jobs: 1 active, 1 archived)
+    - Alpha  j2  archived
+    - Zeta  j1  active
+`
+	if buf.String() != want {
+		t.Errorf("text:\n%s", buf.String())
+	}
+
+	b, err := json.Marshal(root.Find("Utilities"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Counts  folders.Counts `json:"counts"`
+		Folders []struct {
+			Path   string         `json:"path"`
+			Counts folders.Counts `json:"counts"`
+		} `json:"folders"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded.Counts.Jobs != 3 || decoded.Folders[0].Counts.Archived != 1 {
+		t.Errorf("json = %s, %v", b, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	api := &katapultmock.ClientAPI{
+		ListJobsFunc: func(context.Context, *katapultpro.ListJobsOptions) ([]katapultpro.Job, error) {
+			return []katapultpro.Job{{ID: "j1", Name: "One"}, {ID: "j2", Name: "Two"}}, nil
+		},
+		GetJobFunc: func(_ context.Context, jobID string, _ *katapultpro.GetJobOptions) (*katapultpro.Job, error) {
+			if jobID == "j1" {
+				return &katapultpro.Job{Name: "One", ProjectFolder: "Utilities", Status: katapultpro.JobStatusActive}, nil
+			}
+			return &katapultpro.Job{Name: "Two", ProjectFolder: "Utilities", Done: true}, nil
+		},
+		GetJobStatusFunc: func(context.Context, string) (katapultpro.JobStatus, error) {
+			return katapultpro.JobStatusArchived, nil
+		},
+	}
+	root, err := folders.Load(context.Background(), api)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := root.Find("Utilities")
+	if f == nil || f.Counts() != (folders.Counts{Jobs: 2, Active: 1, Archived: 1, Done: 1}) || f.Jobs[1].ID != "j2" {
+		t.Fatalf("Utilities = %+v", f)
+	}
+	api.AssertCalled(t, "ListJobs", &katapultpro.ListJobsOptions{IncludeArchived: true})
+	api.AssertCalled(t, "GetJobStatus", "j2")
+}
+
+func TestArchiver(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := srv.AddJob(katapultpro.Job{Name: "Old", ProjectFolder: "Utilities/2023", Metadata: map[string]interface{}{"last_upload": "2023-01-15"}})
+	recent := srv.AddJob(katapultpro.Job{Name: "Recent", ProjectFolder: "Utilities/2023", Metadata: map[string]interface{}{"last_upload": float64(now.AddDate(0, 0, -5).UnixMilli())}})
+	undated := srv.AddJob(katapultpro.Job{Name: "Undated", ProjectFolder: "Utilities"})
+	srv.AddJob(katapultpro.Job{Name: "Done already", ProjectFolder: "Utilities", Status: katapultpro.JobStatusArchived})
+	client := srv.Client()
+	ctx := context.Background()
+
+	root, err := folders.Load(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// List entries lack the full job data, so every job is fetched with the fields the tree needs.
+	fetched := 0
+	for _, r := range srv.Requests() {
+		if r.Method == "GET" && strings.HasPrefix(r.Path, "/v3/jobs/") && r.Query.Get("paths") == "name,project_folder,status,done,metadata" {
+			fetched++
+		}
+	}
+	if fetched != 4 {
+		t.Errorf("fetched %d jobs, want 4", fetched)
+	}
+	sel := folders.Selection{Folder: "Utilities", Recursive: true, OlderThan: 90 * 24 * time.Hour, Date: folders.MetadataDate("last_upload")}
+	a := &folders.Archiver{Client: client, Now: func() time.Time { return now }}
+	report, err := a.Archive(ctx, root, sel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range report.Results {
+		got = append(got, r.Name+" "+r.Status+" "+r.Reason)
+	}
+	want := []string{"Undated skipped no date", "Old dry_run ", "Recent skipped too recent"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("dry run:\n%s", strings.Join(got, "\n"))
+	}
+
+	a.Commit = true
+	var asked []string
+	a.Confirm = func(_ context.Context, jobs []katapultpro.Job) (bool, error) {
+		for _, j := range jobs {
+			asked = append(asked, j.ID)
+		}
+		return false, nil
+	}
+	if report, err = a.Archive(ctx, root, sel); err != nil || report.Count(folders.StatusDeclined) != 1 || len(asked) != 1 || asked[0] != old.ID {
+		t.Fatalf("declined: %+v, %v", report, err)
+	}
+
+	a.Confirm = func(context.Context, []katapultpro.Job) (bool, error) { return true, nil }
+	if report, err = a.Archive(ctx, root, sel); err != nil || report.Count(folders.StatusArchived) != 1 {
+		t.Fatalf("commit: %+v, %v", report, err)
+	}
+	for id, want := range map[string]katapultpro.JobStatus{old.ID: katapultpro.JobStatusArchived, recent.ID: katapultpro.JobStatusActive, undated.ID: katapultpro.JobStatusActive} {
+		if status, err := client.GetJobStatus(ctx, id); err != nil || status != want {
+			t.Errorf("job %s status = %s, %v; want %s", id, status, err, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "archive Utilities: 1 archived, 0 dry run, 2 skipped, 0 declined, 0 failed\n") {
+		t.Errorf("text:\n%s", buf.String())
+	}
+
+	if _, err := a.Archive(ctx, root, folders.Selection{Folder: "Utilities", OlderThan: time.Hour}); err == nil {
+		t.Error("OlderThan without Date: expected error")
+	}
+}
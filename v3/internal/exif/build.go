@@ -0,0 +1,125 @@
+package exif
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// Build returns a minimal JPEG (SOI, APP1 EXIF, EOI) carrying info. It is the inverse of Decode
+// for the fields Info supports and exists for tests and fixtures; the result is not a viewable image.
+func Build(info Info) []byte {
+	order := binary.BigEndian
+	var exifIFD, gpsIFD, ifd0 []field
+	if info.Make != "" {
+		ifd0 = append(ifd0, ascii(tagMake, info.Make))
+	}
+	if info.Model != "" {
+		ifd0 = append(ifd0, ascii(tagModel, info.Model))
+	}
+	if !info.Time.IsZero() {
+		exifIFD = append(exifIFD, ascii(tagDateTimeOriginal, info.Time.Format(timeLayout)))
+		exifIFD = append(exifIFD, ascii(tagOffsetTimeOriginal, info.Time.Format("-07:00")))
+	}
+	if info.HasGPS {
+		latRef, lonRef := "N", "E"
+		if info.Latitude < 0 {
+			latRef = "S"
+		}
+		if info.Longitude < 0 {
+			lonRef = "W"
+		}
+		gpsIFD = append(gpsIFD,
+			ascii(tagGPSLatitudeRef, latRef), dms(tagGPSLatitude, math.Abs(info.Latitude)),
+			ascii(tagGPSLongitudeRef, lonRef), dms(tagGPSLongitude, math.Abs(info.Longitude)))
+	}
+
+	// Layout: header, IFD0, Exif IFD, GPS IFD, then out-of-line values.
+	const header = 8
+	ifdSize := func(fs []field) int {
+		if len(fs) == 0 {
+			return 0
+		}
+		return 2 + 12*len(fs) + 4
+	}
+	extra := 0
+	if exifIFD != nil {
+		extra++
+	}
+	if gpsIFD != nil {
+		extra++
+	}
+	ifd0Off := header
+	exifOff := ifd0Off + 2 + 12*(len(ifd0)+extra) + 4
+	gpsOff := exifOff + ifdSize(exifIFD)
+	if exifIFD != nil {
+		ifd0 = append(ifd0, long(tagExifIFD, uint32(exifOff)))
+	}
+	if gpsIFD != nil {
+		ifd0 = append(ifd0, long(tagGPSIFD, uint32(gpsOff)))
+	}
+	dataOff := gpsOff + ifdSize(gpsIFD)
+
+	var out, data bytes.Buffer
+	out.WriteString("MM")
+	_ = binary.Write(&out, order, uint16(42))
+	_ = binary.Write(&out, order, uint32(ifd0Off))
+	for _, fs := range [][]field{ifd0, exifIFD, gpsIFD} {
+		if len(fs) == 0 {
+			continue
+		}
+		sort.Slice(fs, func(i, j int) bool { return fs[i].tag < fs[j].tag })
+		_ = binary.Write(&out, order, uint16(len(fs)))
+		for _, f := range fs {
+			_ = binary.Write(&out, order, f.tag)
+			_ = binary.Write(&out, order, f.typ)
+			_ = binary.Write(&out, order, f.count)
+			if len(f.data) <= 4 {
+				var inline [4]byte
+				copy(inline[:], f.data)
+				out.Write(inline[:])
+				continue
+			}
+			_ = binary.Write(&out, order, uint32(dataOff+data.Len()))
+			data.Write(f.data)
+		}
+		_ = binary.Write(&out, order, uint32(0))
+	}
+	out.Write(data.Bytes())
+
+	var jpeg bytes.Buffer
+	jpeg.Write([]byte{0xFF, 0xD8, 0xFF, 0xE1})
+	_ = binary.Write(&jpeg, order, uint16(2+6+out.Len()))
+	jpeg.WriteString("Exif\x00\x00")
+	jpeg.Write(out.Bytes())
+	jpeg.Write([]byte{0xFF, 0xD9})
+	return jpeg.Bytes()
+}
+
+type field struct {
+	tag, typ uint16
+	count    uint32
+	data     []byte
+}
+
+func ascii(tag uint16, s string) field {
+	return field{tag: tag, typ: typeASCII, count: uint32(len(s) + 1), data: append([]byte(s), 0)}
+}
+
+func long(tag uint16, v uint32) field {
+	return field{tag: tag, typ: typeLong, count: 1, data: binary.BigEndian.AppendUint32(nil, v)}
+}
+
+// dms encodes decimal degrees as degrees, minutes, and seconds (to 1/10000 s).
+func dms(tag uint16, v float64) field {
+	d := math.Floor(v)
+	m := math.Floor((v - d) * 60)
+	s := ((v-d)*60 - m) * 60
+	var b []byte
+	for _, r := range [][2]uint32{{uint32(d), 1}, {uint32(m), 1}, {uint32(math.Round(s * 10000)), 10000}} {
+		b = binary.BigEndian.AppendUint32(b, r[0])
+		b = binary.BigEndian.AppendUint32(b, r[1])
+	}
+	return field{tag: tag, typ: typeRational, count: 3, data: b}
+}
@@ -0,0 +1,240 @@
+// Package exif reads the few EXIF fields the SDK needs from JPEG files: capture time, camera,
+// and GPS position. It is not a general EXIF library.
+package exif
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNoExif is returned when a JPEG has no EXIF segment.
+var ErrNoExif = errors.New("exif: no EXIF data")
+
+// Info holds the decoded fields. Zero values mean the field was absent.
+type Info struct {
+	Make  string
+	Model string
+	// Time is DateTimeOriginal (falling back to DateTime). EXIF times carry no zone, so they are
+	// returned in UTC unless OffsetTimeOriginal is present.
+	Time      time.Time
+	HasGPS    bool
+	Latitude  float64 // Decimal degrees, negative south
+	Longitude float64 // Decimal degrees, negative west
+}
+
+// TIFF tags and types used below.
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+
+	typeASCII    = 2
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+
+	timeLayout = "2006:01:02 15:04:05"
+)
+
+// Decode reads the EXIF segment of a JPEG.
+func Decode(r io.Reader) (*Info, error) {
+	br := bufio.NewReader(r)
+	var soi [2]byte
+	if _, err := io.ReadFull(br, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return nil, errors.New("exif: not a JPEG")
+	}
+	for {
+		var hdr [4]byte
+		if _, err := io.ReadFull(br, hdr[:2]); err != nil {
+			return nil, ErrNoExif
+		}
+		if hdr[0] != 0xFF {
+			return nil, errors.New("exif: bad JPEG marker")
+		}
+		marker := hdr[1]
+		if marker == 0xD9 || marker == 0xDA { // EOI, SOS: no metadata after this
+			return nil, ErrNoExif
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) { // markers without a length
+			continue
+		}
+		if _, err := io.ReadFull(br, hdr[2:]); err != nil {
+			return nil, ErrNoExif
+		}
+		n := int(binary.BigEndian.Uint16(hdr[2:])) - 2
+		if n < 0 {
+			return nil, errors.New("exif: bad segment length")
+		}
+		seg := make([]byte, n)
+		if _, err := io.ReadFull(br, seg); err != nil {
+			return nil, fmt.Errorf("exif: short segment: %w", err)
+		}
+		if marker == 0xE1 && len(seg) >= 6 && string(seg[:6]) == "Exif\x00\x00" {
+			return parseTIFF(seg[6:])
+		}
+	}
+}
+
+type tiff struct {
+	b     []byte
+	order binary.ByteOrder
+}
+
+type entry struct {
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+func parseTIFF(b []byte) (*Info, error) {
+	if len(b) < 8 {
+		return nil, errors.New("exif: short TIFF header")
+	}
+	t := &tiff{b: b}
+	switch string(b[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return nil, errors.New("exif: bad byte order")
+	}
+	if t.order.Uint16(b[2:]) != 42 {
+		return nil, errors.New("exif: bad TIFF magic")
+	}
+	ifd0, err := t.ifd(t.order.Uint32(b[4:]))
+	if err != nil {
+		return nil, err
+	}
+	info := &Info{Make: t.str(ifd0[tagMake]), Model: t.str(ifd0[tagModel])}
+	dateTime, offset := t.str(ifd0[tagDateTime]), ""
+	if e, ok := ifd0[tagExifIFD]; ok {
+		if sub, err := t.ifd(t.uint(e)); err == nil {
+			if s := t.str(sub[tagDateTimeOriginal]); s != "" {
+				dateTime = s
+			}
+			offset = t.str(sub[tagOffsetTimeOriginal])
+		}
+	}
+	info.Time = parseTime(dateTime, offset)
+	if e, ok := ifd0[tagGPSIFD]; ok {
+		if gps, err := t.ifd(t.uint(e)); err == nil {
+			lat, okLat := t.degrees(gps[tagGPSLatitude])
+			lon, okLon := t.degrees(gps[tagGPSLongitude])
+			if okLat && okLon {
+				if strings.HasPrefix(t.str(gps[tagGPSLatitudeRef]), "S") {
+					lat = -lat
+				}
+				if strings.HasPrefix(t.str(gps[tagGPSLongitudeRef]), "W") {
+					lon = -lon
+				}
+				info.HasGPS, info.Latitude, info.Longitude = true, lat, lon
+			}
+		}
+	}
+	return info, nil
+}
+
+// ifd reads the directory at off, keyed by tag.
+func (t *tiff) ifd(off uint32) (map[uint16]entry, error) {
+	if int64(off)+2 > int64(len(t.b)) {
+		return nil, errors.New("exif: IFD out of range")
+	}
+	n := int(t.order.Uint16(t.b[off:]))
+	out := make(map[uint16]entry, n)
+	for i := 0; i < n; i++ {
+		p := int(off) + 2 + 12*i
+		if p+12 > len(t.b) {
+			return nil, errors.New("exif: IFD entry out of range")
+		}
+		e := entry{typ: t.order.Uint16(t.b[p+2:]), count: t.order.Uint32(t.b[p+4:])}
+		size := int64(e.count) * typeSize(e.typ)
+		if size <= 4 {
+			e.data = t.b[p+8 : p+8+int(size)]
+		} else {
+			o := int64(t.order.Uint32(t.b[p+8:]))
+			if o+size > int64(len(t.b)) {
+				continue
+			}
+			e.data = t.b[o : o+size]
+		}
+		out[t.order.Uint16(t.b[p:])] = e
+	}
+	return out, nil
+}
+
+func typeSize(typ uint16) int64 {
+	switch typ {
+	case typeShort:
+		return 2
+	case typeLong:
+		return 4
+	case typeRational:
+		return 8
+	}
+	return 1
+}
+
+func (t *tiff) str(e entry) string {
+	if e.typ != typeASCII {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(e.data), "\x00"))
+}
+
+func (t *tiff) uint(e entry) uint32 {
+	switch {
+	case e.typ == typeLong && len(e.data) >= 4:
+		return t.order.Uint32(e.data)
+	case e.typ == typeShort && len(e.data) >= 2:
+		return uint32(t.order.Uint16(e.data))
+	}
+	return 0
+}
+
+// degrees converts a degrees/minutes/seconds rational triple to decimal degrees.
+func (t *tiff) degrees(e entry) (float64, bool) {
+	if e.typ != typeRational || e.count < 3 || len(e.data) < 24 {
+		return 0, false
+	}
+	var dms [3]float64
+	for i := range dms {
+		num, den := t.order.Uint32(e.data[8*i:]), t.order.Uint32(e.data[8*i+4:])
+		if den == 0 {
+			return 0, false
+		}
+		dms[i] = float64(num) / float64(den)
+	}
+	v := dms[0] + dms[1]/60 + dms[2]/3600
+	return v, !math.IsNaN(v) && v <= 180
+}
+
+func parseTime(s, offset string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	if offset != "" {
+		if tm, err := time.Parse(timeLayout+"-07:00", s+offset); err == nil {
+			return tm
+		}
+	}
+	tm, err := time.Parse(timeLayout, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return tm
+}
@@ -0,0 +1,40 @@
+package exif
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDecode_RoundTrip(t *testing.T) {
+	want := Info{
+		Make: "Canon", Model: "EOS R6",
+		Time:   time.Date(2025, 6, 3, 14, 5, 9, 0, time.FixedZone("", -6*3600)),
+		HasGPS: true, Latitude: 40.76123, Longitude: -111.89105,
+	}
+	got, err := Decode(bytes.NewReader(Build(want)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Make != want.Make || got.Model != want.Model || !got.Time.Equal(want.Time) || !got.HasGPS {
+		t.Errorf("got %+v", got)
+	}
+	if math.Abs(got.Latitude-want.Latitude) > 1e-6 || math.Abs(got.Longitude-want.Longitude) > 1e-6 {
+		t.Errorf("got position %v,%v", got.Latitude, got.Longitude)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	if _, err := Decode(bytes.NewReader([]byte("PNG"))); err == nil || errors.Is(err, ErrNoExif) {
+		t.Errorf("non-JPEG: got %v", err)
+	}
+	if _, err := Decode(bytes.NewReader([]byte{0xFF, 0xD8, 0xFF, 0xD9})); !errors.Is(err, ErrNoExif) {
+		t.Errorf("bare JPEG: got %v", err)
+	}
+	info, err := Decode(bytes.NewReader(Build(Info{Model: "X"})))
+	if err != nil || info.HasGPS || !info.Time.IsZero() {
+		t.Errorf("no GPS or time: got %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,57 @@
+// Package singleflight shares one call among concurrent callers with the same key.
+// It is used by the v3 client's WithRequestCoalescing option and is not part of the public API.
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errPanicked is what waiting callers get if fn panics.
+var errPanicked = errors.New("singleflight: shared call panicked")
+
+type call[T any] struct {
+	done chan struct{}
+	val  T
+	err  error
+}
+
+// Group runs at most one call per key at a time. The zero value is ready to use.
+type Group[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[T]
+}
+
+// Do runs fn for key, or, if a call for key is already running, waits for its result. shared
+// reports whether the result came from another caller's fn. A waiting caller whose ctx is done
+// returns ctx.Err() without waiting further; the running call is not affected.
+func (g *Group[T]) Do(ctx context.Context, key string, fn func() (T, error)) (val T, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.val, c.err, true
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err(), true
+		}
+	}
+	c := &call[T]{done: make(chan struct{})}
+	if g.calls == nil {
+		g.calls = map[string]*call[T]{}
+	}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.err = errPanicked
+	c.val, c.err = fn()
+	return c.val, c.err, false
+}
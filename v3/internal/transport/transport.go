@@ -31,6 +31,12 @@ func Do(ctx context.Context, method string, baseURL *url.URL, path string, query
 	if err != nil {
 		return 0, nil, fmt.Errorf("create request: %w", err)
 	}
+	// Streamed bodies (e.g. photo uploads) may know their size; -1 means unknown.
+	if l, ok := body.(interface{ ContentLength() int64 }); ok && req.ContentLength == 0 {
+		if n := l.ContentLength(); n >= 0 {
+			req.ContentLength = n
+		}
+	}
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	} else {
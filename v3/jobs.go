@@ -21,6 +21,19 @@ func (c *Client) GetJob(ctx context.Context, jobID string, opts *GetJobOptions)
 	return c.Jobs().Get(ctx, jobID, opts)
 }
 
+// GetJobAs fetches the given paths of a job and decodes the partial response into a T. Without
+// paths, they are derived from T's fields (see jobs.Projection).
+func GetJobAs[T any](ctx context.Context, c *Client, jobID string, paths ...JobPath) (*T, error) {
+	return jobs.GetAs[T](ctx, c.Jobs(), jobID, paths...)
+}
+
+// JobProjection returns the job paths T's fields decode (see jobs.Projection).
+func JobProjection[T any]() ([]JobPath, error) { return jobs.Projection[T]() }
+
+// NestedJobPath joins segments into a path inside a job field, such as metadata/region. The API's
+// support for nested paths is unverified (see jobs.NestedPath).
+func NestedJobPath(segments ...string) JobPath { return jobs.NestedPath(segments...) }
+
 // CreateJob creates a new job (v3).
 func (c *Client) CreateJob(ctx context.Context, req *CreateJobRequest) (*Job, error) {
 	return c.Jobs().Create(ctx, req)
@@ -40,3 +53,15 @@ func (c *Client) GetJobStatus(ctx context.Context, jobID string) (JobStatus, err
 func (c *Client) UpdateJobStatus(ctx context.Context, jobID string, status JobStatus) error {
 	return c.Jobs().UpdateStatus(ctx, jobID, status)
 }
+
+// NewMetadataPatch returns an empty job metadata patch (see jobs.MetadataPatch).
+func NewMetadataPatch() *MetadataPatch { return jobs.NewMetadataPatch() }
+
+// NewSharingPatch returns an empty job sharing patch (see jobs.SharingPatch).
+func NewSharingPatch() *SharingPatch { return jobs.NewSharingPatch() }
+
+// ParseSharing reads Job.Sharing into typed sharing settings.
+func ParseSharing(m map[string]interface{}) (Sharing, error) { return jobs.ParseSharing(m) }
+
+// ParseMetadataFilter parses a ListJobsOptions.MetadataFilter string.
+func ParseMetadataFilter(s string) (MetadataFilter, error) { return jobs.ParseMetadataFilter(s) }
@@ -44,21 +44,29 @@ func (c *Client) List(ctx context.Context, opts *ListJobsOptions) ([]Job, error)
 
 // Get returns partial or full job data for the given job ID (v3).
 func (c *Client) Get(ctx context.Context, jobID string, opts *GetJobOptions) (*Job, error) {
+	var job Job
+	if err := c.GetInto(ctx, jobID, opts, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetInto is Get decoding the response into out, for partial responses that do not fit Job. See GetAs for a typed version.
+func (c *Client) GetInto(ctx context.Context, jobID string, opts *GetJobOptions, out any) error {
 	path := "v3/jobs/" + jobID
 	var q url.Values
 	if opts != nil && len(opts.Paths) > 0 {
 		q = url.Values{}
 		parts := make([]string, len(opts.Paths))
 		for i, p := range opts.Paths {
+			if err := p.Validate(); err != nil {
+				return err
+			}
 			parts[i] = string(p)
 		}
 		q.Set("paths", strings.Join(parts, ","))
 	}
-	var job Job
-	if err := c.do.Do(ctx, http.MethodGet, path, q, nil, &job); err != nil {
-		return nil, err
-	}
-	return &job, nil
+	return c.do.Do(ctx, http.MethodGet, path, q, nil, out)
 }
 
 // Create creates a new job (v3).
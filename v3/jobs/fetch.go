@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Fetcher lists jobs and fetches their data. It is implemented by *katapultpro.Client and by the
+// ClientAPI interface and its mocks.
+type Fetcher interface {
+	ListJobs(ctx context.Context, opts *ListJobsOptions) ([]Job, error)
+	GetJob(ctx context.Context, jobID string, opts *GetJobOptions) (*Job, error)
+	GetJobStatus(ctx context.Context, jobID string) (JobStatus, error)
+}
+
+// FetchAll lists jobs with opts and fetches the given paths of each, since list entries do not
+// contain the full job data. Each job's ID is set from its list entry, and a job returned without
+// a status has it fetched with GetJobStatus. Errors are not prefixed; callers add their own.
+func FetchAll(ctx context.Context, c Fetcher, opts *ListJobsOptions, paths []JobPath) ([]Job, error) {
+	list, err := c.ListJobs(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("list jobs: %w", err)
+	}
+	jobs := make([]Job, 0, len(list))
+	for _, entry := range list {
+		j, err := c.GetJob(ctx, entry.ID, &GetJobOptions{Paths: paths})
+		if err != nil {
+			return nil, fmt.Errorf("get job %s: %w", entry.ID, err)
+		}
+		j.ID = entry.ID
+		if j.Status == "" {
+			if j.Status, err = c.GetJobStatus(ctx, entry.ID); err != nil {
+				return nil, fmt.Errorf("get status of job %s: %w", entry.ID, err)
+			}
+		}
+		jobs = append(jobs, *j)
+	}
+	return jobs, nil
+}
+
+// UnixTime reads a Unix time in seconds, or in milliseconds when too large to be seconds (later
+// than the year 5138), as dates are stored in job metadata and photos.
+func UnixTime(n float64) time.Time {
+	if n >= 1e11 {
+		return time.UnixMilli(int64(n))
+	}
+	return time.Unix(int64(n), 0)
+}
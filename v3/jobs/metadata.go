@@ -0,0 +1,140 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetadataPatch builds the Metadata of an UpdateJobRequest. Job metadata is a flat map: keys that are
+// set are added or updated, and deleted keys are sent as null, which removes them from the job.
+//
+//	patch := jobs.NewMetadataPatch().Set("description", "Phase 2").Delete("contact_number")
+//	md, err := patch.Build()
+//	job, err := client.UpdateJob(ctx, jobID, &katapultpro.UpdateJobRequest{Metadata: md})
+type MetadataPatch struct {
+	values map[string]interface{}
+	err    error
+}
+
+// NewMetadataPatch returns an empty patch.
+func NewMetadataPatch() *MetadataPatch {
+	return &MetadataPatch{values: map[string]interface{}{}}
+}
+
+// Set adds or updates key. The value must be a string, number, or boolean, since metadata is flat;
+// a nil value deletes the key.
+func (p *MetadataPatch) Set(key string, value interface{}) *MetadataPatch {
+	if key == "" {
+		p.fail(errors.New("jobs: metadata key is empty"))
+		return p
+	}
+	switch value.(type) {
+	case nil, string, bool, float64, float32, int, int64, int32, uint, uint64, uint32:
+	default:
+		p.fail(fmt.Errorf("jobs: metadata %s: %T is not a string, number, or boolean", key, value))
+		return p
+	}
+	p.values[key] = value
+	return p
+}
+
+// Delete removes keys from the job's metadata.
+func (p *MetadataPatch) Delete(keys ...string) *MetadataPatch {
+	for _, k := range keys {
+		p.Set(k, nil)
+	}
+	return p
+}
+
+func (p *MetadataPatch) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// Build returns the patch as a metadata map for UpdateJobRequest (or CreateJobRequest, when it has
+// no deletes), or the first error from Set.
+func (p *MetadataPatch) Build() (map[string]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	out := make(map[string]interface{}, len(p.values))
+	for k, v := range p.values {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// Deleted returns the keys the patch deletes, sorted.
+func (p *MetadataPatch) Deleted() []string {
+	var keys []string
+	for k, v := range p.values {
+		if v == nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MetadataCondition is one attribute:value term of a metadata filter.
+type MetadataCondition struct {
+	Attribute string
+	Value     string
+}
+
+// MetadataFilter builds ListJobsOptions.MetadataFilter. A job matches when its metadata has every
+// condition's attribute with that value.
+//
+//	f := jobs.MetadataFilter{}.Where("region", "west").Where("phase", 2)
+//	list, err := client.ListJobs(ctx, &katapultpro.ListJobsOptions{MetadataFilter: f.String()})
+type MetadataFilter []MetadataCondition
+
+// Where returns f with a condition added. Values are formatted with fmt.Sprint.
+func (f MetadataFilter) Where(attribute string, value interface{}) MetadataFilter {
+	return append(f[:len(f):len(f)], MetadataCondition{Attribute: attribute, Value: fmt.Sprint(value)})
+}
+
+// Validate reports conditions the filter syntax cannot express: empty attributes, attributes
+// containing ':' or ',', and values containing ','.
+func (f MetadataFilter) Validate() error {
+	for _, c := range f {
+		switch {
+		case c.Attribute == "":
+			return errors.New("jobs: metadata filter attribute is empty")
+		case strings.ContainsAny(c.Attribute, ":,"):
+			return fmt.Errorf("jobs: metadata filter attribute %q contains ':' or ','", c.Attribute)
+		case strings.Contains(c.Value, ","):
+			return fmt.Errorf("jobs: metadata filter value %q for %s contains ','", c.Value, c.Attribute)
+		}
+	}
+	return nil
+}
+
+// String returns the filter in the API's "attribute:value,attribute:value" form. Call Validate
+// first if the conditions come from user input.
+func (f MetadataFilter) String() string {
+	parts := make([]string, len(f))
+	for i, c := range f {
+		parts[i] = c.Attribute + ":" + c.Value
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseMetadataFilter parses a filter in the API's form. The empty string is an empty filter.
+func ParseMetadataFilter(s string) (MetadataFilter, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var f MetadataFilter
+	for _, part := range strings.Split(s, ",") {
+		attr, value, ok := strings.Cut(part, ":")
+		if !ok || attr == "" {
+			return nil, fmt.Errorf("jobs: invalid metadata filter term %q; want attribute:value", part)
+		}
+		f = append(f, MetadataCondition{Attribute: attr, Value: value})
+	}
+	return f, nil
+}
@@ -0,0 +1,102 @@
+package jobs_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+func TestMetadataPatch(t *testing.T) {
+	p := jobs.NewMetadataPatch().Set("description", "Phase 2").Set("poles", 12).Delete("contact_number", "fax")
+	md, err := p.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"description": "Phase 2", "poles": 12, "contact_number": nil, "fax": nil}
+	if !reflect.DeepEqual(md, want) {
+		t.Errorf("Build = %v", md)
+	}
+	if got := p.Deleted(); !reflect.DeepEqual(got, []string{"contact_number", "fax"}) {
+		t.Errorf("Deleted = %v", got)
+	}
+
+	if _, err := jobs.NewMetadataPatch().Set("nested", map[string]interface{}{"a": 1}).Build(); err == nil {
+		t.Error("nested value: expected error")
+	}
+	if _, err := jobs.NewMetadataPatch().Set("", "x").Build(); err == nil {
+		t.Error("empty key: expected error")
+	}
+}
+
+func TestMetadataFilter(t *testing.T) {
+	f := jobs.MetadataFilter{}.Where("region", "west").Where("phase", 2)
+	if f.String() != "region:west,phase:2" || f.Validate() != nil {
+		t.Errorf("filter = %q, %v", f.String(), f.Validate())
+	}
+	parsed, err := jobs.ParseMetadataFilter("region:west,phase:2,url:http://x")
+	if err != nil || len(parsed) != 3 || parsed[2].Value != "http://x" {
+		t.Errorf("parsed = %v, %v", parsed, err)
+	}
+	for _, bad := range []jobs.MetadataFilter{{{Attribute: "a:b", Value: "1"}}, {{Attribute: "a", Value: "1,2"}}, {{Value: "1"}}} {
+		if bad.Validate() == nil {
+			t.Errorf("Validate(%v): expected error", bad)
+		}
+	}
+	if _, err := jobs.ParseMetadataFilter("region"); err == nil {
+		t.Error("missing colon: expected error")
+	}
+}
+
+func TestSharing(t *testing.T) {
+	s, err := jobs.ParseSharing(map[string]interface{}{"owner-co": "admin", "acme": "read"})
+	if err != nil || s["acme"] != jobs.PermissionRead || !reflect.DeepEqual(s.Companies(), []string{"acme", "owner-co"}) {
+		t.Errorf("ParseSharing = %v, %v", s, err)
+	}
+	if _, err := jobs.ParseSharing(map[string]interface{}{"acme": true}); err == nil {
+		t.Error("non-string permission: expected error")
+	}
+	if _, err := jobs.NewSharingPatch().Share("acme", "owner").Build(); err == nil {
+		t.Error("invalid permission: expected error")
+	}
+}
+
+func TestPatches_FakeServer(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+	job := srv.AddJob(katapultpro.Job{Name: "Patch",
+		Metadata: map[string]interface{}{"region": "west", "contact_number": "555"},
+		Sharing:  map[string]interface{}{"owner-co": "admin", "old-contractor": "write"}})
+	srv.AddJob(katapultpro.Job{Name: "Other", Metadata: map[string]interface{}{"region": "east"}})
+
+	md, err := jobs.NewMetadataPatch().Set("phase", 2).Delete("contact_number").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharing, err := jobs.NewSharingPatch().Share("acme", jobs.PermissionRead).Unshare("old-contractor").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := client.UpdateJob(ctx, job.ID, &katapultpro.UpdateJobRequest{Metadata: md, Sharing: sharing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(updated.Metadata, map[string]interface{}{"region": "west", "phase": 2.0}) {
+		t.Errorf("metadata = %v", updated.Metadata)
+	}
+	s, err := jobs.ParseSharing(updated.Sharing)
+	if err != nil || !reflect.DeepEqual(s, jobs.Sharing{"owner-co": jobs.PermissionAdmin, "acme": jobs.PermissionRead}) {
+		t.Errorf("sharing = %v, %v", s, err)
+	}
+
+	f := jobs.MetadataFilter{}.Where("region", "west").Where("phase", 2)
+	list, err := client.ListJobs(ctx, &katapultpro.ListJobsOptions{MetadataFilter: f.String()})
+	if err != nil || len(list) != 1 || list[0].ID != job.ID {
+		t.Errorf("filtered jobs = %v, %v", list, err)
+	}
+}
@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// NestedPath joins path segments with "/" to select a value inside a job field:
+// NestedPath("metadata", "region") is meant to select only the region metadata attribute. The API
+// documentation lists only the top-level paths, so neither nested paths nor the "/" separator is
+// verified against the real API; check the response before relying on one.
+func NestedPath(segments ...string) JobPath {
+	return JobPath(strings.Join(segments, "/"))
+}
+
+// Validate reports an empty path or an empty segment in a nested path. Paths outside the
+// constants are passed through for the API to judge.
+func (p JobPath) Validate() error {
+	if p == "" {
+		return errors.New("jobs: empty job path")
+	}
+	for _, seg := range strings.Split(string(p), "/") {
+		if seg == "" {
+			return fmt.Errorf("jobs: job path %q has an empty segment", p)
+		}
+	}
+	return nil
+}
+
+// PartialGetter fetches job data into a caller-supplied value. It is implemented by *Client and
+// by the JobsAPI interface and its mocks.
+type PartialGetter interface {
+	GetInto(ctx context.Context, jobID string, opts *GetJobOptions, out any) error
+}
+
+// GetAs fetches the given paths of a job and decodes the response into a T. Without paths, they
+// are taken from T's fields with Projection:
+//
+//	type jobSummary struct {
+//		Name     string `json:"name"`
+//		Metadata struct {
+//			Region string `json:"region"`
+//		} `json:"metadata"`
+//	}
+//	s, err := jobs.GetAs[jobSummary](ctx, client.Jobs(), jobID) // paths name, metadata
+//
+// Tag a field jobpath:"metadata/region" to fetch a nested path instead (see NestedPath)./region
+func GetAs[T any](ctx context.Context, c PartialGetter, jobID string, paths ...JobPath) (*T, error) {
+	if len(paths) == 0 {
+		var err error
+		if paths, err = Projection[T](); err != nil {
+			return nil, err
+		}
+	}
+	var out T
+	if err := c.GetInto(ctx, jobID, &GetJobOptions{Paths: paths}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Projection returns the job paths that T's fields decode, in field order. T must be a struct or
+// a pointer to one. Fields are named by their json tags, as encoding/json does, and fields tagged
+// json:"-" are skipped. Each field selects its top-level path whole, struct-typed fields included.
+// A jobpath tag overrides this: jobpath:"metadata/region" selects that nested path, which is
+// unverified (see NestedPath), and jobpath:"-" skips the field (such as an ID the response does
+// not carry). Nested paths are only produced by such a tag.
+func Projection[T any]() ([]JobPath, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jobs: projection of %s: not a struct", t)
+	}
+	var paths []JobPath
+	seen := map[JobPath]bool{}
+	project(t, func(p JobPath) {
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	})
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("jobs: projection of %s: no fields", t)
+	}
+	return paths, nil
+}
+
+// project adds the paths of struct t's fields.
+func project(t reflect.Type, add func(JobPath)) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" || f.Tag.Get("jobpath") == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && (!hasTag || name == "") && ft.Kind() == reflect.Struct {
+			project(ft, add) // Embedded fields are promoted, as in encoding/json.
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if p := f.Tag.Get("jobpath"); p != "" {
+			add(JobPath(p))
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		add(JobPath(name))
+	}
+}
@@ -0,0 +1,108 @@
+package jobs_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+type audit struct {
+	Created time.Time `json:"created"`
+}
+
+type jobSummary struct {
+	ID       string `json:"id" jobpath:"-"`
+	Name     string `json:"name"`
+	Status   jobs.JobStatus
+	Metadata struct {
+		Region string  `json:"region"`
+		Poles  float64 `json:"pole_count"`
+	} `json:"metadata"`
+	Sharing map[string]string `json:"sharing"`
+	Owner   string            `json:"owner,omitempty" jobpath:"job_owner"`
+	Region  string            `json:"region" jobpath:"metadata/region"`
+	Ignored string            `json:"-"`
+	audit
+}
+
+func TestProjection(t *testing.T) {
+	paths, err := jobs.Projection[jobSummary]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []jobs.JobPath{"name", "Status", "metadata", "sharing", "job_owner", "metadata/region", "created"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	if _, err := jobs.Projection[string](); err == nil {
+		t.Error("non-struct: expected error")
+	}
+	if err := jobs.NestedPath("metadata", "").Validate(); err == nil {
+		t.Error("empty segment: expected error")
+	}
+}
+
+func TestGetAs(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Partial", JobOwner: "owner-co",
+		Metadata: map[string]interface{}{"region": "west", "pole_count": 12},
+		Sharing:  map[string]interface{}{"owner-co": "admin"}})
+	client := srv.Client()
+	ctx := context.Background()
+
+	// The documented example: a struct field fetches its top-level path.
+	type summary struct {
+		Name     string `json:"name"`
+		JobOwner string `json:"job_owner"`
+		Metadata struct {
+			Region    string  `json:"region"`
+			PoleCount float64 `json:"pole_count"`
+		} `json:"metadata"`
+	}
+	if paths, err := jobs.Projection[summary](); err != nil || !reflect.DeepEqual(paths, []jobs.JobPath{"name", "job_owner", "metadata"}) {
+		t.Errorf("paths = %v, %v", paths, err)
+	}
+	s, err := katapultpro.GetJobAs[summary](ctx, client, job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "Partial" || s.JobOwner != "owner-co" || s.Metadata.Region != "west" || s.Metadata.PoleCount != 12 {
+		t.Errorf("summary = %+v", s)
+	}
+	got := srv.Requests()
+	if q := got[len(got)-1].Query.Get("paths"); q != "name,job_owner,metadata" {
+		t.Errorf("paths query = %q", q)
+	}
+
+	if _, err := jobs.GetAs[summary](ctx, client.Jobs(), job.ID, "bogus"); err == nil {
+		t.Error("invalid path: expected error")
+	}
+
+	// Nested paths come only from a jobpath tag, and the default fake rejects them.
+	type region struct {
+		Metadata struct {
+			Region string `json:"region"`
+		} `json:"metadata" jobpath:"metadata/region"`
+	}
+	if _, err := jobs.GetAs[region](ctx, client.Jobs(), job.ID); err == nil {
+		t.Error("nested path on a default server: expected error")
+	}
+
+	nested := katapulttest.NewServer(katapulttest.WithNestedJobPaths())
+	defer nested.Close()
+	other := nested.AddJob(katapultpro.Job{Name: "Nested", Metadata: map[string]interface{}{"region": "east", "notes": "not fetched"}})
+	r, err := jobs.GetAs[region](ctx, nested.Client().Jobs(), other.ID)
+	if err != nil || r.Metadata.Region != "east" {
+		t.Errorf("region = %+v, %v", r, err)
+	}
+	full, err := nested.Client().GetJob(ctx, other.ID, &katapultpro.GetJobOptions{Paths: []katapultpro.JobPath{jobs.NestedPath("metadata", "region")}})
+	if err != nil || !reflect.DeepEqual(full.Metadata, map[string]interface{}{"region": "east"}) || full.Name != "" {
+		t.Errorf("nested path job = %+v, %v", full, err)
+	}
+}
@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Permission is a company's level of access to a shared job.
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+	PermissionAdmin Permission = "admin"
+)
+
+// String returns the API value.
+func (p Permission) String() string { return string(p) }
+
+// IsValid reports whether p is a defined permission.
+func (p Permission) IsValid() bool {
+	return p == PermissionRead || p == PermissionWrite || p == PermissionAdmin
+}
+
+// Sharing is a job's sharing settings: the companies the job is shared with, by company ID, and
+// their permission. The API stores it as a flat map on Job.Sharing; the owner company is added
+// automatically when a job is created.
+type Sharing map[string]Permission
+
+// ParseSharing reads Job.Sharing. Values must be strings; permissions this package does not
+// define are kept as they are.
+func ParseSharing(m map[string]interface{}) (Sharing, error) {
+	out := make(Sharing, len(m))
+	for company, v := range m {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("jobs: sharing %s: permission is %T, not a string", company, v)
+		}
+		out[company] = Permission(s)
+	}
+	return out, nil
+}
+
+// Companies returns the company IDs, sorted.
+func (s Sharing) Companies() []string {
+	ids := make([]string, 0, len(s))
+	for id := range s {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Map returns s in the form of CreateJobRequest.Sharing.
+func (s Sharing) Map() map[string]interface{} {
+	out := make(map[string]interface{}, len(s))
+	for company, p := range s {
+		out[company] = string(p)
+	}
+	return out
+}
+
+// SharingPatch builds the Sharing of an UpdateJobRequest. Like metadata, sharing is merged into the
+// job's settings: shared companies are added or updated, and unshared ones are sent as null.
+//
+//	sharing, err := jobs.NewSharingPatch().Share("acme-telecom", jobs.PermissionWrite).Unshare("old-contractor").Build()
+type SharingPatch struct {
+	values map[string]interface{}
+	err    error
+}
+
+// NewSharingPatch returns an empty patch.
+func NewSharingPatch() *SharingPatch {
+	return &SharingPatch{values: map[string]interface{}{}}
+}
+
+// Share gives company the permission p, which must be one of the Permission constants.
+func (p *SharingPatch) Share(company string, perm Permission) *SharingPatch {
+	switch {
+	case company == "":
+		p.fail(errors.New("jobs: sharing company ID is empty"))
+	case !perm.IsValid():
+		p.fail(fmt.Errorf("jobs: sharing %s: invalid permission %q", company, perm))
+	default:
+		p.values[company] = string(perm)
+	}
+	return p
+}
+
+// Unshare removes companies from the job's sharing settings.
+func (p *SharingPatch) Unshare(companies ...string) *SharingPatch {
+	for _, c := range companies {
+		if c == "" {
+			p.fail(errors.New("jobs: sharing company ID is empty"))
+			continue
+		}
+		p.values[c] = nil
+	}
+	return p
+}
+
+func (p *SharingPatch) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// Build returns the patch as a sharing map for UpdateJobRequest, or the first error from Share or
+// Unshare.
+func (p *SharingPatch) Build() (map[string]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	out := make(map[string]interface{}, len(p.values))
+	for k, v := range p.values {
+		out[k] = v
+	}
+	return out, nil
+}
@@ -16,7 +16,8 @@ func (s JobStatus) IsValid() bool {
 	return s == JobStatusActive || s == JobStatusArchived
 }
 
-// JobPath is a data path for GetJob partial responses. Use these constants in GetJobOptions.Paths.
+// JobPath is a data path for GetJob partial responses. Use these constants in GetJobOptions.Paths,
+// or NestedPath for paths inside them such as metadata/region (unverified; see NestedPath).
 type JobPath string
 
 const (
@@ -58,7 +59,8 @@ type CreateJobRequest struct {
 	Sharing   map[string]interface{} `json:"sharing,omitempty"`
 }
 
-// UpdateJobRequest is the body for POST /v3/jobs/:job_id.
+// UpdateJobRequest is the body for POST /v3/jobs/:job_id. Metadata and Sharing are merged into the
+// job's; a nil value is sent as null and deletes the key. Build them with MetadataPatch and SharingPatch.
 type UpdateJobRequest struct {
 	Name      string                 `json:"name,omitempty"`
 	Model     string                 `json:"model,omitempty"`
@@ -75,7 +77,7 @@ type UpdateJobStatusRequest struct {
 // ListJobsOptions are optional query parameters for ListJobs.
 type ListJobsOptions struct {
 	IncludeArchived bool
-	MetadataFilter  string
+	MetadataFilter  string // "attribute:value,..."; build with MetadataFilter
 }
 
 // GetJobOptions are optional query parameters for GetJob (partial data).
@@ -0,0 +1,495 @@
+package jobsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Action is what an Operation does.
+type Action string
+
+const (
+	ActionUpdate       Action = "update"        // Entity exists on both sides; push and/or pull field changes.
+	ActionCreateRemote Action = "create_remote" // Entity exists only locally; create it in Katapult.
+	ActionCreateLocal  Action = "create_local"  // Entity exists only in Katapult; write it to the store.
+	ActionDeleteRemote Action = "delete_remote" // Entity was deleted locally since the last sync.
+	ActionDeleteLocal  Action = "delete_local"  // Entity was deleted in Katapult since the last sync.
+	ActionSkip         Action = "skip"          // A deletion that Policy.PropagateDeletes does not allow.
+)
+
+// Operation is the planned sync of one entity.
+type Operation struct {
+	Action       Action        `json:"action"`
+	Kind         Kind          `json:"kind"`
+	KatapultID   string        `json:"katapult_id,omitempty"`
+	ConnectionID string        `json:"connection_id,omitempty"`
+	ExternalKey  string        `json:"external_key,omitempty"`
+	Push         []FieldChange `json:"push,omitempty"` // Changes sent to Katapult.
+	Pull         []FieldChange `json:"pull,omitempty"` // Changes written to the store.
+	Conflicts    []Conflict    `json:"conflicts,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+
+	merged      mergeResult
+	remoteAttrs katapultpro.EntityAttributeList
+}
+
+// Plan is the set of operations for one sync. Build it with Engine.Plan, review it (dry run), then pass it to Engine.Apply.
+type Plan struct {
+	JobID      string      `json:"job_id"`
+	Operations []Operation `json:"operations"`
+}
+
+// Conflicts returns every conflict in the plan, including those resolved automatically by the policy.
+func (p *Plan) Conflicts() []Conflict {
+	var out []Conflict
+	for _, op := range p.Operations {
+		out = append(out, op.Conflicts...)
+	}
+	return out
+}
+
+// WriteText writes a human-readable summary of the plan, one operation per line followed by its field changes.
+func (p *Plan) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "sync plan for job %s: %d operation(s)\n", p.JobID, len(p.Operations))
+	for _, op := range p.Operations {
+		id := op.KatapultID
+		if op.Kind == KindSection {
+			id = op.ConnectionID + "/" + op.KatapultID
+		}
+		if id == "" || id == "/" {
+			id = "(new)"
+		}
+		fmt.Fprintf(&b, "%-13s %-10s %s", op.Action, op.Kind, id)
+		if op.ExternalKey != "" {
+			fmt.Fprintf(&b, " [%s]", op.ExternalKey)
+		}
+		if op.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", op.Reason)
+		}
+		b.WriteString("\n")
+		for _, c := range op.Push {
+			fmt.Fprintf(&b, "    push %s: %v -> %v\n", c.Field, c.From, c.To)
+		}
+		for _, c := range op.Pull {
+			fmt.Fprintf(&b, "    pull %s: %v -> %v\n", c.Field, c.From, c.To)
+		}
+		for _, c := range op.Conflicts {
+			fmt.Fprintf(&b, "    conflict %s: base=%v local=%v remote=%v (%s)\n", c.Field, c.Base, c.Local, c.Remote, c.Resolution)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Engine syncs one job with a Store.
+type Engine struct {
+	Job    *katapultpro.JobScope
+	Store  Store
+	Policy Policy
+	// Queue receives conflicts resolved as Manual when a plan is applied. If nil and Store
+	// implements ConflictQueue, the Store is used.
+	Queue ConflictQueue
+	// Kinds limits the sync to the given kinds; empty means nodes, connections, and sections.
+	Kinds []Kind
+}
+
+func (e *Engine) kinds() []Kind {
+	if len(e.Kinds) > 0 {
+		return e.Kinds
+	}
+	return []Kind{KindNode, KindConnection, KindSection}
+}
+
+// Plan reads the job and the store and returns the operations needed to reconcile them. It makes no changes.
+func (e *Engine) Plan(ctx context.Context) (*Plan, error) {
+	remote, attrs, err := e.remote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{JobID: e.Job.JobID()}
+	for _, kind := range e.kinds() {
+		locals, err := e.Store.Records(ctx, kind)
+		if err != nil {
+			return nil, fmt.Errorf("read store: %w", err)
+		}
+		byKey := make(map[string]Record, len(locals))
+		for _, l := range locals {
+			if l.KatapultID == "" {
+				plan.Operations = append(plan.Operations, e.createRemote(l, false))
+				continue
+			}
+			byKey[l.key()] = l
+		}
+		keys := make([]string, 0, len(byKey)+len(remote[kind]))
+		for k := range byKey {
+			keys = append(keys, k)
+		}
+		for k := range remote[kind] {
+			if _, ok := byKey[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			l, hasLocal := byKey[k]
+			r, hasRemote := remote[kind][k]
+			probe := r
+			if !hasRemote {
+				probe = l
+			}
+			base, err := e.Store.Base(ctx, probe)
+			if err != nil {
+				return nil, fmt.Errorf("read base: %w", err)
+			}
+			op, ok := e.planOne(base, l, hasLocal, r, hasRemote)
+			if !ok {
+				continue
+			}
+			op.remoteAttrs = attrs[k]
+			plan.Operations = append(plan.Operations, op)
+		}
+	}
+	return plan, nil
+}
+
+func (e *Engine) planOne(base *Record, l Record, hasLocal bool, r Record, hasRemote bool) (Operation, bool) {
+	switch {
+	case hasLocal && hasRemote:
+		m := merge(base, l, r, e.Policy)
+		if len(m.push) == 0 && len(m.pull) == 0 && len(m.conflicts) == 0 {
+			return Operation{}, false
+		}
+		return Operation{
+			Action: ActionUpdate, Kind: r.Kind, KatapultID: r.KatapultID, ConnectionID: r.ConnectionID, ExternalKey: l.ExternalKey,
+			Push: m.push, Pull: m.pull, Conflicts: m.conflicts, merged: m,
+		}, true
+	case hasRemote && base == nil:
+		m := mergeResult{local: r.clone(), remote: r, base: r.clone()}
+		return Operation{Action: ActionCreateLocal, Kind: r.Kind, KatapultID: r.KatapultID, ConnectionID: r.ConnectionID, merged: m}, true
+	case hasRemote:
+		return e.deletion(ActionDeleteRemote, r, "deleted locally since last sync"), true
+	case base == nil:
+		// The store knows an ID Katapult does not: create it with that ID through the Update endpoint.
+		return e.createRemote(l, true), true
+	default:
+		return e.deletion(ActionDeleteLocal, l, "deleted in Katapult since last sync"), true
+	}
+}
+
+func (e *Engine) createRemote(l Record, withID bool) Operation {
+	m := mergeResult{local: l.clone(), remote: l.clone(), base: l.clone()}
+	op := Operation{Action: ActionCreateRemote, Kind: l.Kind, KatapultID: l.KatapultID, ConnectionID: l.ConnectionID, ExternalKey: l.ExternalKey, merged: m}
+	if withID {
+		op.Reason = "create with store ID"
+	}
+	return op
+}
+
+func (e *Engine) deletion(action Action, r Record, reason string) Operation {
+	op := Operation{Action: action, Kind: r.Kind, KatapultID: r.KatapultID, ConnectionID: r.ConnectionID, ExternalKey: r.ExternalKey, Reason: reason}
+	op.merged = mergeResult{local: r, remote: r, base: r}
+	if !e.Policy.PropagateDeletes {
+		op.Action = ActionSkip
+		op.Reason = reason + "; PropagateDeletes is off"
+	}
+	return op
+}
+
+// Apply executes the plan. Nodes are applied before connections and connections before sections so
+// that references resolve. Each operation is independent: failures are collected and returned
+// together, and the base snapshot is only advanced for operations that succeeded.
+func (e *Engine) Apply(ctx context.Context, plan *Plan) error {
+	queue := e.Queue
+	if queue == nil {
+		queue, _ = e.Store.(ConflictQueue)
+	}
+	order := map[Kind]int{KindNode: 0, KindConnection: 1, KindSection: 2}
+	ops := append([]Operation(nil), plan.Operations...)
+	sort.SliceStable(ops, func(i, j int) bool { return order[ops[i].Kind] < order[ops[j].Kind] })
+	var errs []error
+	for _, op := range ops {
+		if err := e.apply(ctx, op); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s %s: %w", op.Action, op.Kind, op.KatapultID, err))
+			continue
+		}
+		if queue == nil {
+			continue
+		}
+		for _, c := range op.Conflicts {
+			if c.Resolution != Manual {
+				continue
+			}
+			if err := queue.EnqueueConflict(ctx, c); err != nil {
+				errs = append(errs, fmt.Errorf("queue conflict: %w", err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) apply(ctx context.Context, op Operation) error {
+	m := op.merged
+	switch op.Action {
+	case ActionSkip:
+		return nil
+	case ActionDeleteLocal:
+		if err := e.Store.Delete(ctx, m.local); err != nil {
+			return err
+		}
+		return e.Store.DeleteBase(ctx, m.base)
+	case ActionDeleteRemote:
+		if err := e.deleteRemote(ctx, m.remote); err != nil {
+			return err
+		}
+		return e.Store.DeleteBase(ctx, m.base)
+	case ActionCreateRemote:
+		id, err := e.createRemoteEntity(ctx, m.remote)
+		if err != nil {
+			return err
+		}
+		m.local.KatapultID, m.base.KatapultID = id, id
+		if err := e.Store.Write(ctx, m.local); err != nil {
+			return err
+		}
+		return e.Store.SetBase(ctx, m.base)
+	case ActionCreateLocal:
+		if err := e.Store.Write(ctx, m.local); err != nil {
+			return err
+		}
+		return e.Store.SetBase(ctx, m.base)
+	case ActionUpdate:
+		if len(op.Push) > 0 {
+			if err := e.pushUpdate(ctx, m.remote, op.Push, op.remoteAttrs); err != nil {
+				return err
+			}
+		}
+		if len(op.Pull) > 0 {
+			if err := e.Store.Write(ctx, m.local); err != nil {
+				return err
+			}
+		}
+		return e.Store.SetBase(ctx, m.base)
+	}
+	return fmt.Errorf("unknown action %q", op.Action)
+}
+
+// remote lists the job's entities as Records keyed like Record.key, plus their raw attribute lists
+// (needed to address existing attribute instances when pushing).
+func (e *Engine) remote(ctx context.Context) (map[Kind]map[string]Record, map[string]katapultpro.EntityAttributeList, error) {
+	out := map[Kind]map[string]Record{KindNode: {}, KindConnection: {}, KindSection: {}}
+	attrs := map[string]katapultpro.EntityAttributeList{}
+	want := map[Kind]bool{}
+	for _, k := range e.kinds() {
+		want[k] = true
+	}
+	if want[KindNode] {
+		nodes, err := e.Job.Nodes().List(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list nodes: %w", err)
+		}
+		for _, n := range nodes {
+			r := NodeRecord(n)
+			out[KindNode][r.key()] = r
+			attrs[r.key()] = n.Attributes
+		}
+	}
+	if want[KindConnection] || want[KindSection] {
+		conns, err := e.Job.Connections().List(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("list connections: %w", err)
+		}
+		for _, c := range conns {
+			r := ConnectionRecord(c)
+			out[KindConnection][r.key()] = r
+			attrs[r.key()] = c.Attributes
+			for id, s := range c.Sections {
+				sr := SectionRecord(c.ID, id, s)
+				out[KindSection][sr.key()] = sr
+				attrs[sr.key()] = s.MultiAttributes
+			}
+		}
+	}
+	return out, attrs, nil
+}
+
+// attributeUpdate converts attribute field changes into the remove_attributes / attributes /
+// add_attributes triple, reusing existing instance IDs where the attribute has exactly one instance.
+func attributeUpdate(changes []FieldChange, existing katapultpro.EntityAttributeList) (remove []string, set katapultpro.EntityAttributeList, add map[string]interface{}) {
+	for _, c := range changes {
+		name, ok := strings.CutPrefix(c.Field, attrPrefix)
+		if !ok {
+			continue
+		}
+		if set == nil {
+			set = katapultpro.EntityAttributeList{}
+		}
+		instances := existing[name]
+		switch vals := c.To.(type) {
+		case nil:
+			set[name] = nil // Encoded as null, which removes the attribute.
+		case []any:
+			remove = append(remove, name)
+			set[name] = expandValues(vals)
+		default:
+			switch len(instances) {
+			case 0:
+				if add == nil {
+					add = map[string]interface{}{}
+				}
+				add[name] = vals
+			case 1:
+				for id := range instances {
+					set[name] = map[string]interface{}{id: vals}
+				}
+			default:
+				remove = append(remove, name)
+				set[name] = expandValues([]any{vals})
+			}
+		}
+	}
+	if len(set) == 0 {
+		set = nil
+	}
+	return remove, set, add
+}
+
+// expand converts flattened attributes into an entity attribute list with new instance IDs.
+func expand(attrs map[string]any) katapultpro.EntityAttributeList {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := katapultpro.EntityAttributeList{}
+	for name, v := range attrs {
+		switch vals := v.(type) {
+		case nil:
+		case []any:
+			out[name] = expandValues(vals)
+		default:
+			out[name] = expandValues([]any{vals})
+		}
+	}
+	return out
+}
+
+func expandValues(vals []any) map[string]interface{} {
+	m := make(map[string]interface{}, len(vals))
+	for _, v := range vals {
+		m[newInstanceID()] = v
+	}
+	return m
+}
+
+func hasField(changes []FieldChange, names ...string) bool {
+	for _, c := range changes {
+		for _, n := range names {
+			if c.Field == n {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *Engine) pushUpdate(ctx context.Context, r Record, changes []FieldChange, existing katapultpro.EntityAttributeList) error {
+	remove, set, add := attributeUpdate(changes, existing)
+	switch r.Kind {
+	case KindNode:
+		req := &katapultpro.UpdateNodeRequest{RemoveAttributes: remove, Attributes: set, AddAttributes: add}
+		if hasField(changes, "latitude", "longitude") {
+			req.Latitude, req.Longitude = r.Latitude, r.Longitude
+		}
+		_, err := e.Job.Nodes().Node(r.KatapultID).Update(ctx, req, &katapultpro.UpdateNodeOptions{OnlyIfExists: true})
+		return err
+	case KindConnection:
+		req := &katapultpro.UpdateConnectionRequest{RemoveAttributes: remove, Attributes: set, AddAttributes: add}
+		if hasField(changes, "node_id_1", "node_id_2") {
+			req.NodeID1, req.NodeID2 = r.NodeID1, r.NodeID2
+		}
+		_, err := e.Job.Connections().Connection(r.KatapultID).Update(ctx, req, &katapultpro.UpdateConnectionOptions{OnlyIfExists: true})
+		return err
+	case KindSection:
+		req := &katapultpro.UpdateSectionRequest{RemoveAttributes: remove, Attributes: set, AddAttributes: add}
+		if hasField(changes, "latitude", "longitude") {
+			req.Latitude, req.Longitude = r.Latitude, r.Longitude
+		}
+		_, err := e.Job.Connections().Connection(r.ConnectionID).Sections().Section(r.KatapultID).Update(ctx, req, &katapultpro.UpdateSectionOptions{OnlyIfExists: true})
+		return err
+	}
+	return fmt.Errorf("unknown kind %q", r.Kind)
+}
+
+// createRemoteEntity creates r in Katapult and returns its ID. Records that already carry an ID are
+// created through the Update endpoint so the store's ID is kept.
+func (e *Engine) createRemoteEntity(ctx context.Context, r Record) (string, error) {
+	attrs := expand(r.Attributes)
+	switch r.Kind {
+	case KindNode:
+		if r.KatapultID != "" {
+			n, err := e.Job.Nodes().Node(r.KatapultID).Update(ctx, &katapultpro.UpdateNodeRequest{Latitude: r.Latitude, Longitude: r.Longitude, Attributes: attrs}, nil)
+			return idOr(n, err, func() string { return n.ID }, r.KatapultID)
+		}
+		n, err := e.Job.Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Latitude: r.Latitude, Longitude: r.Longitude, Attributes: attrs})
+		return idOr(n, err, func() string { return n.ID }, "")
+	case KindConnection:
+		if r.KatapultID != "" {
+			c, err := e.Job.Connections().Connection(r.KatapultID).Update(ctx, &katapultpro.UpdateConnectionRequest{NodeID1: r.NodeID1, NodeID2: r.NodeID2, Attributes: attrs}, nil)
+			return idOr(c, err, func() string { return c.ID }, r.KatapultID)
+		}
+		c, err := e.Job.Connections().Create(ctx, &katapultpro.CreateConnectionRequest{NodeID1: r.NodeID1, NodeID2: r.NodeID2, Attributes: attrs})
+		return idOr(c, err, func() string { return c.ID }, "")
+	case KindSection:
+		if r.ConnectionID == "" {
+			return "", errors.New("section record has no connection ID")
+		}
+		secs := e.Job.Connections().Connection(r.ConnectionID).Sections()
+		if r.KatapultID != "" {
+			s, err := secs.Section(r.KatapultID).Update(ctx, &katapultpro.UpdateSectionRequest{Latitude: r.Latitude, Longitude: r.Longitude, Attributes: attrs}, nil)
+			return idOr(s, err, func() string { return sectionID(s) }, r.KatapultID)
+		}
+		s, err := secs.Create(ctx, &katapultpro.CreateSectionRequest{Latitude: r.Latitude, Longitude: r.Longitude, Attributes: attrs})
+		return idOr(s, err, func() string { return sectionID(s) }, "")
+	}
+	return "", fmt.Errorf("unknown kind %q", r.Kind)
+}
+
+func (e *Engine) deleteRemote(ctx context.Context, r Record) error {
+	switch r.Kind {
+	case KindNode:
+		return e.Job.Nodes().Node(r.KatapultID).Delete(ctx)
+	case KindConnection:
+		return e.Job.Connections().Connection(r.KatapultID).Delete(ctx)
+	case KindSection:
+		return e.Job.Connections().Connection(r.ConnectionID).Sections().Section(r.KatapultID).Delete(ctx)
+	}
+	return fmt.Errorf("unknown kind %q", r.Kind)
+}
+
+func sectionID(s *katapultpro.Section) string {
+	if s.ID != "" {
+		return s.ID
+	}
+	return s.Key
+}
+
+// idOr returns the created entity's ID, falling back to want when the response omitted it.
+func idOr[T any](v *T, err error, id func() string, want string) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	if v != nil {
+		if got := id(); got != "" {
+			return got, nil
+		}
+	}
+	if want == "" {
+		return "", errors.New("create response has no id")
+	}
+	return want, nil
+}
@@ -0,0 +1,154 @@
+package jobsync_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobsync"
+)
+
+// nodeServer serves GET /nodes from nodes and records POST bodies to /nodes/:id.
+type nodeServer struct {
+	mu      sync.Mutex
+	nodes   string
+	updates map[string]map[string]any
+}
+
+func (s *nodeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := `[]`
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v3/jobs/j1/nodes":
+		data = s.nodes
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/v3/jobs/j1/nodes/"):
+		body, _ := io.ReadAll(r.Body)
+		var m map[string]any
+		_ = json.Unmarshal(body, &m)
+		s.updates[strings.TrimPrefix(r.URL.Path, "/v3/jobs/j1/nodes/")] = m
+		data = `{}`
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"status":"success","data":` + data + `,"meta":{"token_count":9999,"last_refill_time":0}}`))
+}
+
+func newEngine(t *testing.T, srv *nodeServer, policy jobsync.Policy) (*jobsync.Engine, *jobsync.MemoryStore) {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(ts.URL))
+	store := jobsync.NewMemoryStore()
+	return &jobsync.Engine{Job: client.Job("j1"), Store: store, Policy: policy, Kinds: []jobsync.Kind{jobsync.KindNode}}, store
+}
+
+func TestEngine_ThreeWayMerge(t *testing.T) {
+	srv := &nodeServer{
+		nodes:   `[{"id":"n1","latitude":1,"longitude":2,"attributes":{"scid":{"i1":"001"},"note":{"i2":"a"}}}]`,
+		updates: map[string]map[string]any{},
+	}
+	eng, store := newEngine(t, srv, jobsync.Policy{Attributes: map[string]jobsync.Resolution{"note": jobsync.Manual}})
+	ctx := context.Background()
+
+	// First sync: the node only exists remotely, so it is written to the store.
+	plan, err := eng.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Operations) != 1 || plan.Operations[0].Action != jobsync.ActionCreateLocal {
+		t.Fatalf("got plan %+v", plan.Operations)
+	}
+	if err := eng.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	// Local edits scid, remote edits latitude, and both edit note differently.
+	recs, _ := store.Records(ctx, jobsync.KindNode)
+	local := recs[0]
+	local.ExternalKey = "POLE-1"
+	local.Attributes["scid"] = "002"
+	local.Attributes["note"] = "local"
+	_ = store.Write(ctx, local)
+	srv.nodes = `[{"id":"n1","latitude":5,"longitude":2,"attributes":{"scid":{"i1":"001"},"note":{"i2":"remote"}}}]`
+
+	plan, err = eng.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Operations) != 1 {
+		t.Fatalf("got %d operations", len(plan.Operations))
+	}
+	op := plan.Operations[0]
+	if len(op.Push) != 1 || op.Push[0].Field != "attributes.scid" {
+		t.Errorf("push: %+v", op.Push)
+	}
+	if len(op.Pull) != 1 || op.Pull[0].Field != "latitude" {
+		t.Errorf("pull: %+v", op.Pull)
+	}
+	if len(op.Conflicts) != 1 || op.Conflicts[0].Field != "attributes.note" || op.Conflicts[0].Resolution != jobsync.Manual {
+		t.Errorf("conflicts: %+v", op.Conflicts)
+	}
+	var text bytes.Buffer
+	_ = plan.WriteText(&text)
+	if !strings.Contains(text.String(), "push attributes.scid: 001 -> 002") {
+		t.Errorf("dry-run output missing push line:\n%s", text.String())
+	}
+
+	if err := eng.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	// scid is updated in place on its existing instance ID.
+	attrs, _ := srv.updates["n1"]["attributes"].(map[string]any)
+	if scid, _ := attrs["scid"].(map[string]any); scid["i1"] != "002" {
+		t.Errorf("update body: %+v", srv.updates["n1"])
+	}
+	recs, _ = store.Records(ctx, jobsync.KindNode)
+	if recs[0].Latitude != 5 || recs[0].Attributes["note"] != "local" {
+		t.Errorf("local record after apply: %+v", recs[0])
+	}
+	if q := store.Conflicts(); len(q) != 1 || q[0].ExternalKey != "POLE-1" {
+		t.Errorf("queued conflicts: %+v", q)
+	}
+
+	// The manual conflict stays open on the next run because the base keeps the old value.
+	srv.nodes = `[{"id":"n1","latitude":5,"longitude":2,"attributes":{"scid":{"i1":"002"},"note":{"i2":"remote"}}}]`
+	plan, _ = eng.Plan(ctx)
+	if c := plan.Conflicts(); len(c) != 1 || c[0].Field != "attributes.note" {
+		t.Errorf("expected note conflict to persist, got %+v", c)
+	}
+}
+
+func TestEngine_DeletesRequirePolicy(t *testing.T) {
+	srv := &nodeServer{nodes: `[{"id":"n1","latitude":1,"longitude":2}]`, updates: map[string]map[string]any{}}
+	eng, store := newEngine(t, srv, jobsync.Policy{})
+	ctx := context.Background()
+	plan, _ := eng.Plan(ctx)
+	_ = eng.Apply(ctx, plan)
+
+	srv.nodes = `[]`
+	plan, _ = eng.Plan(ctx)
+	if len(plan.Operations) != 1 || plan.Operations[0].Action != jobsync.ActionSkip {
+		t.Fatalf("got %+v", plan.Operations)
+	}
+	_ = eng.Apply(ctx, plan)
+	if recs, _ := store.Records(ctx, jobsync.KindNode); len(recs) != 1 {
+		t.Errorf("skipped deletion should keep the local record")
+	}
+
+	eng.Policy.PropagateDeletes = true
+	plan, _ = eng.Plan(ctx)
+	if plan.Operations[0].Action != jobsync.ActionDeleteLocal {
+		t.Fatalf("got %+v", plan.Operations)
+	}
+	_ = eng.Apply(ctx, plan)
+	if recs, _ := store.Records(ctx, jobsync.KindNode); len(recs) != 0 {
+		t.Errorf("expected local record to be deleted, got %+v", recs)
+	}
+}
@@ -0,0 +1,180 @@
+package jobsync
+
+import (
+	"sort"
+	"strings"
+)
+
+// Resolution decides which side wins when the local and remote values of a field both changed since the base.
+type Resolution string
+
+const (
+	RemoteWins Resolution = "remote_wins" // Take the Katapult value and write it to the store.
+	LocalWins  Resolution = "local_wins"  // Take the store value and push it to Katapult.
+	Manual     Resolution = "manual"      // Change neither side and queue the conflict.
+)
+
+// Policy configures conflict resolution. The zero value resolves every conflict with RemoteWins
+// and never propagates deletions.
+type Policy struct {
+	Default Resolution
+	// Attributes overrides Default per attribute name (e.g. "scid": LocalWins).
+	Attributes map[string]Resolution
+	// Fields overrides Default for the non-attribute fields ("latitude", "longitude", "node_id_1", "node_id_2").
+	Fields map[string]Resolution
+	// PropagateDeletes deletes an entity on one side when it was deleted on the other since the last sync.
+	// When false such entities are reported as skipped operations.
+	PropagateDeletes bool
+}
+
+func (p Policy) resolve(field string) Resolution {
+	if name, ok := strings.CutPrefix(field, attrPrefix); ok {
+		if r, ok := p.Attributes[name]; ok {
+			return r
+		}
+	} else if r, ok := p.Fields[field]; ok {
+		return r
+	}
+	if p.Default == "" {
+		return RemoteWins
+	}
+	return p.Default
+}
+
+// FieldChange is a change to one field of a record. Attribute fields are named "attributes.<name>".
+// A nil To means the attribute is removed.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// Conflict records a field that changed on both sides since the base snapshot (or differs with no base).
+type Conflict struct {
+	Kind         Kind       `json:"kind"`
+	KatapultID   string     `json:"katapult_id"`
+	ConnectionID string     `json:"connection_id,omitempty"`
+	ExternalKey  string     `json:"external_key,omitempty"`
+	Field        string     `json:"field"`
+	Base         any        `json:"base"`
+	Local        any        `json:"local"`
+	Remote       any        `json:"remote"`
+	Resolution   Resolution `json:"resolution"`
+}
+
+const attrPrefix = "attributes."
+
+// fields returns the mergeable fields of a record keyed by field name.
+func fields(r Record) map[string]any {
+	out := make(map[string]any, len(r.Attributes)+2)
+	switch r.Kind {
+	case KindConnection:
+		out["node_id_1"] = r.NodeID1
+		out["node_id_2"] = r.NodeID2
+	default:
+		out["latitude"] = r.Latitude
+		out["longitude"] = r.Longitude
+	}
+	for name, v := range r.Attributes {
+		if v != nil {
+			out[attrPrefix+name] = v
+		}
+	}
+	return out
+}
+
+func setField(r *Record, field string, v any) {
+	switch field {
+	case "latitude":
+		r.Latitude, _ = v.(float64)
+	case "longitude":
+		r.Longitude, _ = v.(float64)
+	case "node_id_1":
+		r.NodeID1, _ = v.(string)
+	case "node_id_2":
+		r.NodeID2, _ = v.(string)
+	default:
+		name := strings.TrimPrefix(field, attrPrefix)
+		if v == nil {
+			delete(r.Attributes, name)
+			return
+		}
+		if r.Attributes == nil {
+			r.Attributes = map[string]any{}
+		}
+		r.Attributes[name] = v
+	}
+}
+
+// mergeResult is the outcome of a three-way merge of one record.
+type mergeResult struct {
+	local     Record // next local state
+	remote    Record // next remote state
+	base      Record // base to save once both sides are written
+	push      []FieldChange
+	pull      []FieldChange
+	conflicts []Conflict
+}
+
+// merge performs a field-by-field three-way merge. base may be nil, in which case every
+// difference between local and remote is a conflict.
+func merge(base *Record, local, remote Record, p Policy) mergeResult {
+	res := mergeResult{local: local.clone(), remote: remote.clone(), base: remote.clone()}
+	res.local.KatapultID, res.local.ConnectionID = remote.KatapultID, remote.ConnectionID
+	res.base.ExternalKey = local.ExternalKey
+	lf, rf := fields(local), fields(remote)
+	var bf map[string]any
+	if base != nil {
+		bf = fields(*base)
+	}
+	names := make(map[string]bool, len(lf)+len(rf))
+	for k := range lf {
+		names[k] = true
+	}
+	for k := range rf {
+		names[k] = true
+	}
+	for k := range bf {
+		names[k] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for k := range names {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, f := range sorted {
+		l, r, b := lf[f], rf[f], bf[f]
+		switch {
+		case equal(l, r):
+			continue
+		case base != nil && equal(l, b):
+			res.pull = append(res.pull, FieldChange{Field: f, From: l, To: r})
+			setField(&res.local, f, r)
+			continue
+		case base != nil && equal(r, b):
+			res.push = append(res.push, FieldChange{Field: f, From: r, To: l})
+			setField(&res.remote, f, l)
+			setField(&res.base, f, l)
+			continue
+		}
+		c := Conflict{
+			Kind: remote.Kind, KatapultID: remote.KatapultID, ConnectionID: remote.ConnectionID, ExternalKey: local.ExternalKey,
+			Field: f, Base: b, Local: l, Remote: r, Resolution: p.resolve(f),
+		}
+		res.conflicts = append(res.conflicts, c)
+		switch c.Resolution {
+		case LocalWins:
+			res.push = append(res.push, FieldChange{Field: f, From: r, To: l})
+			setField(&res.remote, f, l)
+			setField(&res.base, f, l)
+		case Manual:
+			// Keep both sides and the old base so the conflict is detected again until resolved.
+			setField(&res.base, f, b)
+		default:
+			res.pull = append(res.pull, FieldChange{Field: f, From: l, To: r})
+			setField(&res.local, f, r)
+		}
+	}
+	return res
+}
@@ -0,0 +1,127 @@
+// Package jobsync keeps a job's nodes, connections, and sections in step with an external store.
+// An Engine compares the remote job, the local Store, and the base snapshot saved after the last
+// sync (a three-way merge), resolves conflicting edits according to a Policy, and produces a Plan.
+// Plans can be inspected as a dry run or applied through the domain clients' Update endpoints.
+package jobsync
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"sort"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Kind is the kind of entity a Record holds.
+type Kind string
+
+const (
+	KindNode       Kind = "node"
+	KindConnection Kind = "connection"
+	KindSection    Kind = "section"
+)
+
+// Record is the store-neutral form of a node, connection, or section.
+//
+// Attributes are flattened: an attribute with one instance maps to its value, and an attribute with
+// several instances maps to a []any of the values ordered by instance ID. A nil value means the
+// attribute is absent.
+type Record struct {
+	Kind         Kind           `json:"kind"`
+	KatapultID   string         `json:"katapult_id,omitempty"`   // Empty for local records not yet created in Katapult.
+	ConnectionID string         `json:"connection_id,omitempty"` // Sections only.
+	ExternalKey  string         `json:"external_key,omitempty"`  // The record's key in the external store.
+	Latitude     float64        `json:"latitude,omitempty"`      // Nodes and sections.
+	Longitude    float64        `json:"longitude,omitempty"`     // Nodes and sections.
+	NodeID1      string         `json:"node_id_1,omitempty"`     // Connections only.
+	NodeID2      string         `json:"node_id_2,omitempty"`     // Connections only.
+	Attributes   map[string]any `json:"attributes,omitempty"`
+}
+
+// NodeRecord converts a node into a Record.
+func NodeRecord(n katapultpro.Node) Record {
+	return Record{Kind: KindNode, KatapultID: n.ID, Latitude: n.Latitude, Longitude: n.Longitude, Attributes: Flatten(n.Attributes)}
+}
+
+// ConnectionRecord converts a connection (without its sections) into a Record.
+func ConnectionRecord(c katapultpro.Connection) Record {
+	return Record{Kind: KindConnection, KatapultID: c.ID, NodeID1: c.NodeID1, NodeID2: c.NodeID2, Attributes: Flatten(c.Attributes)}
+}
+
+// SectionRecord converts a section embedded in a connection response into a Record.
+func SectionRecord(connectionID, sectionID string, s katapultpro.EmbeddedSection) Record {
+	return Record{Kind: KindSection, KatapultID: sectionID, ConnectionID: connectionID, Latitude: s.Latitude, Longitude: s.Longitude, Attributes: Flatten(s.MultiAttributes)}
+}
+
+// Flatten converts an entity attribute list into the flattened form used by Record.
+func Flatten(l katapultpro.EntityAttributeList) map[string]any {
+	if len(l) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(l))
+	for name, instances := range l {
+		switch len(instances) {
+		case 0:
+			continue
+		case 1:
+			for _, v := range instances {
+				out[name] = v
+			}
+		default:
+			ids := make([]string, 0, len(instances))
+			for id := range instances {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			vals := make([]any, len(ids))
+			for i, id := range ids {
+				vals[i] = instances[id]
+			}
+			out[name] = vals
+		}
+	}
+	return out
+}
+
+// key identifies a record across the remote job, the store, and the base snapshots.
+func (r Record) key() string {
+	if r.Kind == KindSection {
+		return string(r.Kind) + ":" + r.ConnectionID + "/" + r.KatapultID
+	}
+	return string(r.Kind) + ":" + r.KatapultID
+}
+
+func (r Record) clone() Record {
+	out := r
+	if r.Attributes != nil {
+		out.Attributes = make(map[string]any, len(r.Attributes))
+		for k, v := range r.Attributes {
+			out.Attributes[k] = v
+		}
+	}
+	return out
+}
+
+// equal compares two values by their JSON encoding so that, for example, int 5 from a store
+// equals float64 5 decoded from the API.
+func equal(a, b any) bool {
+	ab, err1 := json.Marshal(a)
+	bb, err2 := json.Marshal(b)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return bytes.Equal(ab, bb)
+}
+
+const idAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// newInstanceID returns a random attribute instance ID that satisfies the API's ID requirements.
+func newInstanceID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = idAlphabet[int(b[i])%len(idAlphabet)]
+	}
+	return string(b)
+}
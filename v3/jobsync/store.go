@@ -0,0 +1,129 @@
+package jobsync
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Store is the external side of a sync. Implementations map Records to their own schema.
+//
+// Records are identified by Kind and KatapultID (plus ConnectionID for sections). A local record
+// with an empty KatapultID is created in Katapult on Apply and then written back with its new ID;
+// implementations should match it by ExternalKey in that case.
+type Store interface {
+	// Records returns every local record of the kind.
+	Records(ctx context.Context, kind Kind) ([]Record, error)
+	// Write inserts or replaces a local record.
+	Write(ctx context.Context, rec Record) error
+	// Delete removes a local record.
+	Delete(ctx context.Context, rec Record) error
+	// Base returns the snapshot saved after the last successful sync of the record, or nil if there is none.
+	Base(ctx context.Context, rec Record) (*Record, error)
+	// SetBase saves the merged record as the base for the next sync.
+	SetBase(ctx context.Context, rec Record) error
+	// DeleteBase forgets the base snapshot of a record that no longer exists on either side.
+	DeleteBase(ctx context.Context, rec Record) error
+}
+
+// ConflictQueue receives conflicts that a Policy routes to manual resolution.
+// A Store that also implements ConflictQueue is used automatically when Engine.Queue is nil.
+type ConflictQueue interface {
+	EnqueueConflict(ctx context.Context, c Conflict) error
+}
+
+// MemoryStore is an in-memory Store and ConflictQueue, useful for tests and as a reference implementation.
+type MemoryStore struct {
+	mu        sync.Mutex
+	records   map[string]Record
+	bases     map[string]Record
+	conflicts []Conflict
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string]Record{}, bases: map[string]Record{}}
+}
+
+func (s *MemoryStore) localKey(r Record) string {
+	if r.KatapultID == "" {
+		return string(r.Kind) + ":ext:" + r.ExternalKey
+	}
+	return r.key()
+}
+
+// Records implements Store.
+func (s *MemoryStore) Records(_ context.Context, kind Kind) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Record
+	for _, r := range s.records {
+		if r.Kind == kind {
+			out = append(out, r.clone())
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return s.localKey(out[i]) < s.localKey(out[j]) })
+	return out, nil
+}
+
+// Write implements Store. A record written with a KatapultID replaces a pending record with the same ExternalKey.
+func (s *MemoryStore) Write(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rec.KatapultID != "" && rec.ExternalKey != "" {
+		delete(s.records, string(rec.Kind)+":ext:"+rec.ExternalKey)
+	}
+	s.records[s.localKey(rec)] = rec.clone()
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, s.localKey(rec))
+	return nil
+}
+
+// Base implements Store.
+func (s *MemoryStore) Base(_ context.Context, rec Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.bases[rec.key()]
+	if !ok {
+		return nil, nil
+	}
+	b = b.clone()
+	return &b, nil
+}
+
+// SetBase implements Store.
+func (s *MemoryStore) SetBase(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bases[rec.key()] = rec.clone()
+	return nil
+}
+
+// DeleteBase implements Store.
+func (s *MemoryStore) DeleteBase(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bases, rec.key())
+	return nil
+}
+
+// EnqueueConflict implements ConflictQueue.
+func (s *MemoryStore) EnqueueConflict(_ context.Context, c Conflict) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conflicts = append(s.conflicts, c)
+	return nil
+}
+
+// Conflicts returns the conflicts queued for manual resolution.
+func (s *MemoryStore) Conflicts() []Conflict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Conflict(nil), s.conflicts...)
+}
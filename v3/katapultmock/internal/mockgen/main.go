@@ -0,0 +1,204 @@
+// Command mockgen generates katapultmock's mocks from the *API interfaces in the root package.
+//
+//	go run ./internal/mockgen -src ../api.go -out mocks.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+func main() {
+	src := flag.String("src", "../api.go", "file declaring the interfaces")
+	out := flag.String("out", "mocks.go", "output file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, *src, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var ifaces []*ast.TypeSpec
+	names := map[string]bool{}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if _, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.IsExported() && strings.HasSuffix(ts.Name.Name, "API") {
+				ifaces = append(ifaces, ts)
+				names[ts.Name.Name] = true
+			}
+		}
+	}
+
+	g := &generator{fset: fset, names: names}
+	g.printf("// Code generated by mockgen from api.go. DO NOT EDIT.\n\n")
+	g.printf("package katapultmock\n\n")
+	g.printf("import (\n\t\"context\"\n\t\"io\"\n\n\t\"github.com/romer-pro/katapultpro-go-sdk/v3\"\n)\n")
+	for _, ts := range ifaces {
+		g.mock(ts.Name.Name, ts.Type.(*ast.InterfaceType))
+	}
+	code, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		log.Fatalf("format: %v\n%s", err, g.buf.Bytes())
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	fset  *token.FileSet
+	names map[string]bool
+	buf   bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...any) { fmt.Fprintf(&g.buf, format, args...) }
+
+// typ renders a type expression, qualifying the root package's exported identifiers.
+func (g *generator) typ(e ast.Expr) string {
+	e = qualify(e)
+	var b bytes.Buffer
+	_ = printer.Fprint(&b, g.fset, e)
+	return b.String()
+}
+
+func qualify(e ast.Expr) ast.Expr {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if t.IsExported() {
+			return &ast.SelectorExpr{X: ast.NewIdent("katapultpro"), Sel: ast.NewIdent(t.Name)}
+		}
+		return t
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualify(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(t.Key), Value: qualify(t.Value)}
+	}
+	return e
+}
+
+type param struct {
+	name string
+	typ  string
+	ctx  bool
+}
+
+func (g *generator) params(fl *ast.FieldList) []param {
+	var out []param
+	if fl == nil {
+		return nil
+	}
+	for _, f := range fl.List {
+		t := g.typ(f.Type)
+		if len(f.Names) == 0 {
+			out = append(out, param{typ: t})
+			continue
+		}
+		for _, n := range f.Names {
+			out = append(out, param{name: n.Name, typ: t, ctx: t == "context.Context"})
+		}
+	}
+	return out
+}
+
+func (g *generator) mock(name string, it *ast.InterfaceType) {
+	type method struct {
+		name    string
+		params  []param
+		results []param
+		child   string // interface name when the method drills down to another API
+	}
+	var methods []method
+	for _, f := range it.Methods.List {
+		ft := f.Type.(*ast.FuncType)
+		m := method{name: f.Names[0].Name, params: g.params(ft.Params), results: g.params(ft.Results)}
+		if len(m.results) == 1 {
+			if id, ok := ft.Results.List[0].Type.(*ast.Ident); ok && g.names[id.Name] {
+				m.child = id.Name
+			}
+		}
+		methods = append(methods, m)
+	}
+
+	g.printf("\n// %s is a mock katapultpro.%s. Set a <Method>Func field to stub a method; unstubbed\n", name, name)
+	g.printf("// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).\n")
+	g.printf("type %s struct {\n\tRecorder\n\n", name)
+	for _, m := range methods {
+		g.printf("\t%sFunc func(%s) %s\n", m.name, joinParams(m.params, true), joinResults(m.results))
+	}
+	for _, m := range methods {
+		if m.child != "" {
+			g.printf("\t%sMock *%s\n", m.name, m.child)
+		}
+	}
+	g.printf("}\n\nvar _ katapultpro.%s = (*%s)(nil)\n", name, name)
+
+	for _, m := range methods {
+		g.printf("\n// %s implements katapultpro.%s.\n", m.name, name)
+		g.printf("func (m *%s) %s(%s) %s {\n", name, m.name, joinParams(m.params, true), joinResults(m.results))
+		var recorded, call []string
+		recorded = append(recorded, fmt.Sprintf("%q", m.name))
+		for _, p := range m.params {
+			call = append(call, p.name)
+			if !p.ctx {
+				recorded = append(recorded, p.name)
+			}
+		}
+		g.printf("\tm.record(%s)\n", strings.Join(recorded, ", "))
+		g.printf("\tif m.%sFunc != nil {\n\t\treturn m.%sFunc(%s)\n\t}\n", m.name, m.name, strings.Join(call, ", "))
+		switch {
+		case m.child != "":
+			g.printf("\treturn lazy(&m.Recorder, &m.%sMock)\n", m.name)
+		case len(m.results) == 0:
+		default:
+			var zeros []string
+			for i, r := range m.results {
+				if r.typ == "error" {
+					zeros = append(zeros, fmt.Sprintf("notStubbed(%q, %q)", name, m.name))
+					continue
+				}
+				g.printf("\tvar r%d %s\n", i, r.typ)
+				zeros = append(zeros, fmt.Sprintf("r%d", i))
+			}
+			g.printf("\treturn %s\n", strings.Join(zeros, ", "))
+		}
+		g.printf("}\n")
+	}
+}
+
+func joinParams(ps []param, named bool) string {
+	parts := make([]string, len(ps))
+	for i, p := range ps {
+		if named && p.name != "" {
+			parts[i] = p.name + " " + p.typ
+		} else {
+			parts[i] = p.typ
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinResults(rs []param) string {
+	switch len(rs) {
+	case 0:
+		return ""
+	case 1:
+		return rs[0].typ
+	}
+	return "(" + joinParams(rs, false) + ")"
+}
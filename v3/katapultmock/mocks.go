@@ -0,0 +1,1303 @@
+// Code generated by mockgen from api.go. DO NOT EDIT.
+
+package katapultmock
+
+import (
+	"context"
+	"io"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// ClientAPI is a mock katapultpro.ClientAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type ClientAPI struct {
+	Recorder
+
+	JobsFunc            func() katapultpro.JobsAPI
+	JobFunc             func(jobID string) katapultpro.JobAPI
+	ListJobsFunc        func(ctx context.Context, opts *katapultpro.ListJobsOptions) ([]katapultpro.Job, error)
+	GetJobFunc          func(ctx context.Context, jobID string, opts *katapultpro.GetJobOptions) (*katapultpro.Job, error)
+	CreateJobFunc       func(ctx context.Context, req *katapultpro.CreateJobRequest) (*katapultpro.Job, error)
+	UpdateJobFunc       func(ctx context.Context, jobID string, req *katapultpro.UpdateJobRequest) (*katapultpro.Job, error)
+	GetJobStatusFunc    func(ctx context.Context, jobID string) (katapultpro.JobStatus, error)
+	UpdateJobStatusFunc func(ctx context.Context, jobID string, status katapultpro.JobStatus) error
+	JobsMock            *JobsAPI
+	JobMock             *JobAPI
+}
+
+var _ katapultpro.ClientAPI = (*ClientAPI)(nil)
+
+// Jobs implements katapultpro.ClientAPI.
+func (m *ClientAPI) Jobs() katapultpro.JobsAPI {
+	m.record("Jobs")
+	if m.JobsFunc != nil {
+		return m.JobsFunc()
+	}
+	return lazy(&m.Recorder, &m.JobsMock)
+}
+
+// Job implements katapultpro.ClientAPI.
+func (m *ClientAPI) Job(jobID string) katapultpro.JobAPI {
+	m.record("Job", jobID)
+	if m.JobFunc != nil {
+		return m.JobFunc(jobID)
+	}
+	return lazy(&m.Recorder, &m.JobMock)
+}
+
+// ListJobs implements katapultpro.ClientAPI.
+func (m *ClientAPI) ListJobs(ctx context.Context, opts *katapultpro.ListJobsOptions) ([]katapultpro.Job, error) {
+	m.record("ListJobs", opts)
+	if m.ListJobsFunc != nil {
+		return m.ListJobsFunc(ctx, opts)
+	}
+	var r0 []katapultpro.Job
+	return r0, notStubbed("ClientAPI", "ListJobs")
+}
+
+// GetJob implements katapultpro.ClientAPI.
+func (m *ClientAPI) GetJob(ctx context.Context, jobID string, opts *katapultpro.GetJobOptions) (*katapultpro.Job, error) {
+	m.record("GetJob", jobID, opts)
+	if m.GetJobFunc != nil {
+		return m.GetJobFunc(ctx, jobID, opts)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("ClientAPI", "GetJob")
+}
+
+// CreateJob implements katapultpro.ClientAPI.
+func (m *ClientAPI) CreateJob(ctx context.Context, req *katapultpro.CreateJobRequest) (*katapultpro.Job, error) {
+	m.record("CreateJob", req)
+	if m.CreateJobFunc != nil {
+		return m.CreateJobFunc(ctx, req)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("ClientAPI", "CreateJob")
+}
+
+// UpdateJob implements katapultpro.ClientAPI.
+func (m *ClientAPI) UpdateJob(ctx context.Context, jobID string, req *katapultpro.UpdateJobRequest) (*katapultpro.Job, error) {
+	m.record("UpdateJob", jobID, req)
+	if m.UpdateJobFunc != nil {
+		return m.UpdateJobFunc(ctx, jobID, req)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("ClientAPI", "UpdateJob")
+}
+
+// GetJobStatus implements katapultpro.ClientAPI.
+func (m *ClientAPI) GetJobStatus(ctx context.Context, jobID string) (katapultpro.JobStatus, error) {
+	m.record("GetJobStatus", jobID)
+	if m.GetJobStatusFunc != nil {
+		return m.GetJobStatusFunc(ctx, jobID)
+	}
+	var r0 katapultpro.JobStatus
+	return r0, notStubbed("ClientAPI", "GetJobStatus")
+}
+
+// UpdateJobStatus implements katapultpro.ClientAPI.
+func (m *ClientAPI) UpdateJobStatus(ctx context.Context, jobID string, status katapultpro.JobStatus) error {
+	m.record("UpdateJobStatus", jobID, status)
+	if m.UpdateJobStatusFunc != nil {
+		return m.UpdateJobStatusFunc(ctx, jobID, status)
+	}
+	return notStubbed("ClientAPI", "UpdateJobStatus")
+}
+
+// JobsAPI is a mock katapultpro.JobsAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type JobsAPI struct {
+	Recorder
+
+	ListFunc         func(ctx context.Context, opts *katapultpro.ListJobsOptions) ([]katapultpro.Job, error)
+	GetFunc          func(ctx context.Context, jobID string, opts *katapultpro.GetJobOptions) (*katapultpro.Job, error)
+	GetIntoFunc      func(ctx context.Context, jobID string, opts *katapultpro.GetJobOptions, out any) error
+	CreateFunc       func(ctx context.Context, req *katapultpro.CreateJobRequest) (*katapultpro.Job, error)
+	UpdateFunc       func(ctx context.Context, jobID string, req *katapultpro.UpdateJobRequest) (*katapultpro.Job, error)
+	GetStatusFunc    func(ctx context.Context, jobID string) (katapultpro.JobStatus, error)
+	UpdateStatusFunc func(ctx context.Context, jobID string, status katapultpro.JobStatus) error
+}
+
+var _ katapultpro.JobsAPI = (*JobsAPI)(nil)
+
+// List implements katapultpro.JobsAPI.
+func (m *JobsAPI) List(ctx context.Context, opts *katapultpro.ListJobsOptions) ([]katapultpro.Job, error) {
+	m.record("List", opts)
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, opts)
+	}
+	var r0 []katapultpro.Job
+	return r0, notStubbed("JobsAPI", "List")
+}
+
+// Get implements katapultpro.JobsAPI.
+func (m *JobsAPI) Get(ctx context.Context, jobID string, opts *katapultpro.GetJobOptions) (*katapultpro.Job, error) {
+	m.record("Get", jobID, opts)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, jobID, opts)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("JobsAPI", "Get")
+}
+
+// GetInto implements katapultpro.JobsAPI.
+func (m *JobsAPI) GetInto(ctx context.Context, jobID string, opts *katapultpro.GetJobOptions, out any) error {
+	m.record("GetInto", jobID, opts, out)
+	if m.GetIntoFunc != nil {
+		return m.GetIntoFunc(ctx, jobID, opts, out)
+	}
+	return notStubbed("JobsAPI", "GetInto")
+}
+
+// Create implements katapultpro.JobsAPI.
+func (m *JobsAPI) Create(ctx context.Context, req *katapultpro.CreateJobRequest) (*katapultpro.Job, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("JobsAPI", "Create")
+}
+
+// Update implements katapultpro.JobsAPI.
+func (m *JobsAPI) Update(ctx context.Context, jobID string, req *katapultpro.UpdateJobRequest) (*katapultpro.Job, error) {
+	m.record("Update", jobID, req)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, jobID, req)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("JobsAPI", "Update")
+}
+
+// GetStatus implements katapultpro.JobsAPI.
+func (m *JobsAPI) GetStatus(ctx context.Context, jobID string) (katapultpro.JobStatus, error) {
+	m.record("GetStatus", jobID)
+	if m.GetStatusFunc != nil {
+		return m.GetStatusFunc(ctx, jobID)
+	}
+	var r0 katapultpro.JobStatus
+	return r0, notStubbed("JobsAPI", "GetStatus")
+}
+
+// UpdateStatus implements katapultpro.JobsAPI.
+func (m *JobsAPI) UpdateStatus(ctx context.Context, jobID string, status katapultpro.JobStatus) error {
+	m.record("UpdateStatus", jobID, status)
+	if m.UpdateStatusFunc != nil {
+		return m.UpdateStatusFunc(ctx, jobID, status)
+	}
+	return notStubbed("JobsAPI", "UpdateStatus")
+}
+
+// JobAPI is a mock katapultpro.JobAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type JobAPI struct {
+	Recorder
+
+	JobIDFunc       func() string
+	GetFunc         func(ctx context.Context, opts *katapultpro.GetJobOptions) (*katapultpro.Job, error)
+	UpdateFunc      func(ctx context.Context, req *katapultpro.UpdateJobRequest) (*katapultpro.Job, error)
+	StatusFunc      func(ctx context.Context) (katapultpro.JobStatus, error)
+	SetStatusFunc   func(ctx context.Context, status katapultpro.JobStatus) error
+	NodesFunc       func() katapultpro.NodesAPI
+	ConnectionsFunc func() katapultpro.ConnectionsAPI
+	PhotosFunc      func() katapultpro.PhotosAPI
+	TracesFunc      func() katapultpro.TracesAPI
+	SectionsFunc    func() katapultpro.JobSectionsAPI
+	NodesMock       *NodesAPI
+	ConnectionsMock *ConnectionsAPI
+	PhotosMock      *PhotosAPI
+	TracesMock      *TracesAPI
+	SectionsMock    *JobSectionsAPI
+}
+
+var _ katapultpro.JobAPI = (*JobAPI)(nil)
+
+// JobID implements katapultpro.JobAPI.
+func (m *JobAPI) JobID() string {
+	m.record("JobID")
+	if m.JobIDFunc != nil {
+		return m.JobIDFunc()
+	}
+	var r0 string
+	return r0
+}
+
+// Get implements katapultpro.JobAPI.
+func (m *JobAPI) Get(ctx context.Context, opts *katapultpro.GetJobOptions) (*katapultpro.Job, error) {
+	m.record("Get", opts)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, opts)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("JobAPI", "Get")
+}
+
+// Update implements katapultpro.JobAPI.
+func (m *JobAPI) Update(ctx context.Context, req *katapultpro.UpdateJobRequest) (*katapultpro.Job, error) {
+	m.record("Update", req)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, req)
+	}
+	var r0 *katapultpro.Job
+	return r0, notStubbed("JobAPI", "Update")
+}
+
+// Status implements katapultpro.JobAPI.
+func (m *JobAPI) Status(ctx context.Context) (katapultpro.JobStatus, error) {
+	m.record("Status")
+	if m.StatusFunc != nil {
+		return m.StatusFunc(ctx)
+	}
+	var r0 katapultpro.JobStatus
+	return r0, notStubbed("JobAPI", "Status")
+}
+
+// SetStatus implements katapultpro.JobAPI.
+func (m *JobAPI) SetStatus(ctx context.Context, status katapultpro.JobStatus) error {
+	m.record("SetStatus", status)
+	if m.SetStatusFunc != nil {
+		return m.SetStatusFunc(ctx, status)
+	}
+	return notStubbed("JobAPI", "SetStatus")
+}
+
+// Nodes implements katapultpro.JobAPI.
+func (m *JobAPI) Nodes() katapultpro.NodesAPI {
+	m.record("Nodes")
+	if m.NodesFunc != nil {
+		return m.NodesFunc()
+	}
+	return lazy(&m.Recorder, &m.NodesMock)
+}
+
+// Connections implements katapultpro.JobAPI.
+func (m *JobAPI) Connections() katapultpro.ConnectionsAPI {
+	m.record("Connections")
+	if m.ConnectionsFunc != nil {
+		return m.ConnectionsFunc()
+	}
+	return lazy(&m.Recorder, &m.ConnectionsMock)
+}
+
+// Photos implements katapultpro.JobAPI.
+func (m *JobAPI) Photos() katapultpro.PhotosAPI {
+	m.record("Photos")
+	if m.PhotosFunc != nil {
+		return m.PhotosFunc()
+	}
+	return lazy(&m.Recorder, &m.PhotosMock)
+}
+
+// Traces implements katapultpro.JobAPI.
+func (m *JobAPI) Traces() katapultpro.TracesAPI {
+	m.record("Traces")
+	if m.TracesFunc != nil {
+		return m.TracesFunc()
+	}
+	return lazy(&m.Recorder, &m.TracesMock)
+}
+
+// Sections implements katapultpro.JobAPI.
+func (m *JobAPI) Sections() katapultpro.JobSectionsAPI {
+	m.record("Sections")
+	if m.SectionsFunc != nil {
+		return m.SectionsFunc()
+	}
+	return lazy(&m.Recorder, &m.SectionsMock)
+}
+
+// NodesAPI is a mock katapultpro.NodesAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type NodesAPI struct {
+	Recorder
+
+	NodeFunc        func(nodeID string) katapultpro.NodeAPI
+	ListFunc        func(ctx context.Context) ([]katapultpro.Node, error)
+	GetFunc         func(ctx context.Context, nodeID string) (*katapultpro.Node, error)
+	CreateFunc      func(ctx context.Context, req *katapultpro.CreateNodeRequest) (*katapultpro.Node, error)
+	UpdateFunc      func(ctx context.Context, nodeID string, req *katapultpro.UpdateNodeRequest, opts *katapultpro.UpdateNodeOptions) (*katapultpro.Node, error)
+	UploadPhotoFunc func(ctx context.Context, nodeID string, imageData io.Reader, opts *katapultpro.UploadNodePhotoOptions) (*katapultpro.Photo, error)
+	DeleteFunc      func(ctx context.Context, nodeID string) error
+	NodeMock        *NodeAPI
+}
+
+var _ katapultpro.NodesAPI = (*NodesAPI)(nil)
+
+// Node implements katapultpro.NodesAPI.
+func (m *NodesAPI) Node(nodeID string) katapultpro.NodeAPI {
+	m.record("Node", nodeID)
+	if m.NodeFunc != nil {
+		return m.NodeFunc(nodeID)
+	}
+	return lazy(&m.Recorder, &m.NodeMock)
+}
+
+// List implements katapultpro.NodesAPI.
+func (m *NodesAPI) List(ctx context.Context) ([]katapultpro.Node, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.Node
+	return r0, notStubbed("NodesAPI", "List")
+}
+
+// Get implements katapultpro.NodesAPI.
+func (m *NodesAPI) Get(ctx context.Context, nodeID string) (*katapultpro.Node, error) {
+	m.record("Get", nodeID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, nodeID)
+	}
+	var r0 *katapultpro.Node
+	return r0, notStubbed("NodesAPI", "Get")
+}
+
+// Create implements katapultpro.NodesAPI.
+func (m *NodesAPI) Create(ctx context.Context, req *katapultpro.CreateNodeRequest) (*katapultpro.Node, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.Node
+	return r0, notStubbed("NodesAPI", "Create")
+}
+
+// Update implements katapultpro.NodesAPI.
+func (m *NodesAPI) Update(ctx context.Context, nodeID string, req *katapultpro.UpdateNodeRequest, opts *katapultpro.UpdateNodeOptions) (*katapultpro.Node, error) {
+	m.record("Update", nodeID, req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, nodeID, req, opts)
+	}
+	var r0 *katapultpro.Node
+	return r0, notStubbed("NodesAPI", "Update")
+}
+
+// UploadPhoto implements katapultpro.NodesAPI.
+func (m *NodesAPI) UploadPhoto(ctx context.Context, nodeID string, imageData io.Reader, opts *katapultpro.UploadNodePhotoOptions) (*katapultpro.Photo, error) {
+	m.record("UploadPhoto", nodeID, imageData, opts)
+	if m.UploadPhotoFunc != nil {
+		return m.UploadPhotoFunc(ctx, nodeID, imageData, opts)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("NodesAPI", "UploadPhoto")
+}
+
+// Delete implements katapultpro.NodesAPI.
+func (m *NodesAPI) Delete(ctx context.Context, nodeID string) error {
+	m.record("Delete", nodeID)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, nodeID)
+	}
+	return notStubbed("NodesAPI", "Delete")
+}
+
+// NodeAPI is a mock katapultpro.NodeAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type NodeAPI struct {
+	Recorder
+
+	NodeIDFunc      func() string
+	GetFunc         func(ctx context.Context) (*katapultpro.Node, error)
+	UpdateFunc      func(ctx context.Context, req *katapultpro.UpdateNodeRequest, opts *katapultpro.UpdateNodeOptions) (*katapultpro.Node, error)
+	UploadPhotoFunc func(ctx context.Context, imageData io.Reader, opts *katapultpro.UploadNodePhotoOptions) (*katapultpro.Photo, error)
+	DeleteFunc      func(ctx context.Context) error
+}
+
+var _ katapultpro.NodeAPI = (*NodeAPI)(nil)
+
+// NodeID implements katapultpro.NodeAPI.
+func (m *NodeAPI) NodeID() string {
+	m.record("NodeID")
+	if m.NodeIDFunc != nil {
+		return m.NodeIDFunc()
+	}
+	var r0 string
+	return r0
+}
+
+// Get implements katapultpro.NodeAPI.
+func (m *NodeAPI) Get(ctx context.Context) (*katapultpro.Node, error) {
+	m.record("Get")
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	var r0 *katapultpro.Node
+	return r0, notStubbed("NodeAPI", "Get")
+}
+
+// Update implements katapultpro.NodeAPI.
+func (m *NodeAPI) Update(ctx context.Context, req *katapultpro.UpdateNodeRequest, opts *katapultpro.UpdateNodeOptions) (*katapultpro.Node, error) {
+	m.record("Update", req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, req, opts)
+	}
+	var r0 *katapultpro.Node
+	return r0, notStubbed("NodeAPI", "Update")
+}
+
+// UploadPhoto implements katapultpro.NodeAPI.
+func (m *NodeAPI) UploadPhoto(ctx context.Context, imageData io.Reader, opts *katapultpro.UploadNodePhotoOptions) (*katapultpro.Photo, error) {
+	m.record("UploadPhoto", imageData, opts)
+	if m.UploadPhotoFunc != nil {
+		return m.UploadPhotoFunc(ctx, imageData, opts)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("NodeAPI", "UploadPhoto")
+}
+
+// Delete implements katapultpro.NodeAPI.
+func (m *NodeAPI) Delete(ctx context.Context) error {
+	m.record("Delete")
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx)
+	}
+	return notStubbed("NodeAPI", "Delete")
+}
+
+// ConnectionsAPI is a mock katapultpro.ConnectionsAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type ConnectionsAPI struct {
+	Recorder
+
+	ConnectionFunc func(connectionID string) katapultpro.ConnectionAPI
+	SectionsFunc   func(connectionID string) katapultpro.SectionsAPI
+	ListFunc       func(ctx context.Context) ([]katapultpro.Connection, error)
+	GetFunc        func(ctx context.Context, connectionID string) (*katapultpro.Connection, error)
+	CreateFunc     func(ctx context.Context, req *katapultpro.CreateConnectionRequest) (*katapultpro.Connection, error)
+	UpdateFunc     func(ctx context.Context, connectionID string, req *katapultpro.UpdateConnectionRequest, opts *katapultpro.UpdateConnectionOptions) (*katapultpro.Connection, error)
+	DeleteFunc     func(ctx context.Context, connectionID string) error
+	ConnectionMock *ConnectionAPI
+	SectionsMock   *SectionsAPI
+}
+
+var _ katapultpro.ConnectionsAPI = (*ConnectionsAPI)(nil)
+
+// Connection implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) Connection(connectionID string) katapultpro.ConnectionAPI {
+	m.record("Connection", connectionID)
+	if m.ConnectionFunc != nil {
+		return m.ConnectionFunc(connectionID)
+	}
+	return lazy(&m.Recorder, &m.ConnectionMock)
+}
+
+// Sections implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) Sections(connectionID string) katapultpro.SectionsAPI {
+	m.record("Sections", connectionID)
+	if m.SectionsFunc != nil {
+		return m.SectionsFunc(connectionID)
+	}
+	return lazy(&m.Recorder, &m.SectionsMock)
+}
+
+// List implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) List(ctx context.Context) ([]katapultpro.Connection, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.Connection
+	return r0, notStubbed("ConnectionsAPI", "List")
+}
+
+// Get implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) Get(ctx context.Context, connectionID string) (*katapultpro.Connection, error) {
+	m.record("Get", connectionID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, connectionID)
+	}
+	var r0 *katapultpro.Connection
+	return r0, notStubbed("ConnectionsAPI", "Get")
+}
+
+// Create implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) Create(ctx context.Context, req *katapultpro.CreateConnectionRequest) (*katapultpro.Connection, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.Connection
+	return r0, notStubbed("ConnectionsAPI", "Create")
+}
+
+// Update implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) Update(ctx context.Context, connectionID string, req *katapultpro.UpdateConnectionRequest, opts *katapultpro.UpdateConnectionOptions) (*katapultpro.Connection, error) {
+	m.record("Update", connectionID, req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, connectionID, req, opts)
+	}
+	var r0 *katapultpro.Connection
+	return r0, notStubbed("ConnectionsAPI", "Update")
+}
+
+// Delete implements katapultpro.ConnectionsAPI.
+func (m *ConnectionsAPI) Delete(ctx context.Context, connectionID string) error {
+	m.record("Delete", connectionID)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, connectionID)
+	}
+	return notStubbed("ConnectionsAPI", "Delete")
+}
+
+// ConnectionAPI is a mock katapultpro.ConnectionAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type ConnectionAPI struct {
+	Recorder
+
+	ConnectionIDFunc func() string
+	GetFunc          func(ctx context.Context) (*katapultpro.Connection, error)
+	UpdateFunc       func(ctx context.Context, req *katapultpro.UpdateConnectionRequest, opts *katapultpro.UpdateConnectionOptions) (*katapultpro.Connection, error)
+	DeleteFunc       func(ctx context.Context) error
+	SectionsFunc     func() katapultpro.SectionsAPI
+	SectionsMock     *SectionsAPI
+}
+
+var _ katapultpro.ConnectionAPI = (*ConnectionAPI)(nil)
+
+// ConnectionID implements katapultpro.ConnectionAPI.
+func (m *ConnectionAPI) ConnectionID() string {
+	m.record("ConnectionID")
+	if m.ConnectionIDFunc != nil {
+		return m.ConnectionIDFunc()
+	}
+	var r0 string
+	return r0
+}
+
+// Get implements katapultpro.ConnectionAPI.
+func (m *ConnectionAPI) Get(ctx context.Context) (*katapultpro.Connection, error) {
+	m.record("Get")
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	var r0 *katapultpro.Connection
+	return r0, notStubbed("ConnectionAPI", "Get")
+}
+
+// Update implements katapultpro.ConnectionAPI.
+func (m *ConnectionAPI) Update(ctx context.Context, req *katapultpro.UpdateConnectionRequest, opts *katapultpro.UpdateConnectionOptions) (*katapultpro.Connection, error) {
+	m.record("Update", req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, req, opts)
+	}
+	var r0 *katapultpro.Connection
+	return r0, notStubbed("ConnectionAPI", "Update")
+}
+
+// Delete implements katapultpro.ConnectionAPI.
+func (m *ConnectionAPI) Delete(ctx context.Context) error {
+	m.record("Delete")
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx)
+	}
+	return notStubbed("ConnectionAPI", "Delete")
+}
+
+// Sections implements katapultpro.ConnectionAPI.
+func (m *ConnectionAPI) Sections() katapultpro.SectionsAPI {
+	m.record("Sections")
+	if m.SectionsFunc != nil {
+		return m.SectionsFunc()
+	}
+	return lazy(&m.Recorder, &m.SectionsMock)
+}
+
+// SectionsAPI is a mock katapultpro.SectionsAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type SectionsAPI struct {
+	Recorder
+
+	SectionFunc     func(sectionKey string) katapultpro.SectionAPI
+	ListFunc        func(ctx context.Context) ([]katapultpro.Section, error)
+	GetFunc         func(ctx context.Context, sectionKey string) (*katapultpro.Section, error)
+	CreateFunc      func(ctx context.Context, req *katapultpro.CreateSectionRequest) (*katapultpro.Section, error)
+	UpdateFunc      func(ctx context.Context, sectionKey string, req *katapultpro.UpdateSectionRequest, opts *katapultpro.UpdateSectionOptions) (*katapultpro.Section, error)
+	UploadPhotoFunc func(ctx context.Context, sectionID string, imageData io.Reader, opts *katapultpro.UploadSectionPhotoOptions) (*katapultpro.Photo, error)
+	DeleteFunc      func(ctx context.Context, sectionKey string) error
+	SectionMock     *SectionAPI
+}
+
+var _ katapultpro.SectionsAPI = (*SectionsAPI)(nil)
+
+// Section implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) Section(sectionKey string) katapultpro.SectionAPI {
+	m.record("Section", sectionKey)
+	if m.SectionFunc != nil {
+		return m.SectionFunc(sectionKey)
+	}
+	return lazy(&m.Recorder, &m.SectionMock)
+}
+
+// List implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) List(ctx context.Context) ([]katapultpro.Section, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.Section
+	return r0, notStubbed("SectionsAPI", "List")
+}
+
+// Get implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) Get(ctx context.Context, sectionKey string) (*katapultpro.Section, error) {
+	m.record("Get", sectionKey)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, sectionKey)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("SectionsAPI", "Get")
+}
+
+// Create implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) Create(ctx context.Context, req *katapultpro.CreateSectionRequest) (*katapultpro.Section, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("SectionsAPI", "Create")
+}
+
+// Update implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) Update(ctx context.Context, sectionKey string, req *katapultpro.UpdateSectionRequest, opts *katapultpro.UpdateSectionOptions) (*katapultpro.Section, error) {
+	m.record("Update", sectionKey, req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, sectionKey, req, opts)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("SectionsAPI", "Update")
+}
+
+// UploadPhoto implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) UploadPhoto(ctx context.Context, sectionID string, imageData io.Reader, opts *katapultpro.UploadSectionPhotoOptions) (*katapultpro.Photo, error) {
+	m.record("UploadPhoto", sectionID, imageData, opts)
+	if m.UploadPhotoFunc != nil {
+		return m.UploadPhotoFunc(ctx, sectionID, imageData, opts)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("SectionsAPI", "UploadPhoto")
+}
+
+// Delete implements katapultpro.SectionsAPI.
+func (m *SectionsAPI) Delete(ctx context.Context, sectionKey string) error {
+	m.record("Delete", sectionKey)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, sectionKey)
+	}
+	return notStubbed("SectionsAPI", "Delete")
+}
+
+// SectionAPI is a mock katapultpro.SectionAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type SectionAPI struct {
+	Recorder
+
+	SectionKeyFunc  func() string
+	GetFunc         func(ctx context.Context) (*katapultpro.Section, error)
+	UpdateFunc      func(ctx context.Context, req *katapultpro.UpdateSectionRequest, opts *katapultpro.UpdateSectionOptions) (*katapultpro.Section, error)
+	UploadPhotoFunc func(ctx context.Context, imageData io.Reader, opts *katapultpro.UploadSectionPhotoOptions) (*katapultpro.Photo, error)
+	DeleteFunc      func(ctx context.Context) error
+}
+
+var _ katapultpro.SectionAPI = (*SectionAPI)(nil)
+
+// SectionKey implements katapultpro.SectionAPI.
+func (m *SectionAPI) SectionKey() string {
+	m.record("SectionKey")
+	if m.SectionKeyFunc != nil {
+		return m.SectionKeyFunc()
+	}
+	var r0 string
+	return r0
+}
+
+// Get implements katapultpro.SectionAPI.
+func (m *SectionAPI) Get(ctx context.Context) (*katapultpro.Section, error) {
+	m.record("Get")
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("SectionAPI", "Get")
+}
+
+// Update implements katapultpro.SectionAPI.
+func (m *SectionAPI) Update(ctx context.Context, req *katapultpro.UpdateSectionRequest, opts *katapultpro.UpdateSectionOptions) (*katapultpro.Section, error) {
+	m.record("Update", req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, req, opts)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("SectionAPI", "Update")
+}
+
+// UploadPhoto implements katapultpro.SectionAPI.
+func (m *SectionAPI) UploadPhoto(ctx context.Context, imageData io.Reader, opts *katapultpro.UploadSectionPhotoOptions) (*katapultpro.Photo, error) {
+	m.record("UploadPhoto", imageData, opts)
+	if m.UploadPhotoFunc != nil {
+		return m.UploadPhotoFunc(ctx, imageData, opts)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("SectionAPI", "UploadPhoto")
+}
+
+// Delete implements katapultpro.SectionAPI.
+func (m *SectionAPI) Delete(ctx context.Context) error {
+	m.record("Delete")
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx)
+	}
+	return notStubbed("SectionAPI", "Delete")
+}
+
+// JobSectionsAPI is a mock katapultpro.JobSectionsAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type JobSectionsAPI struct {
+	Recorder
+
+	SectionFunc func(sectionID string) katapultpro.JobSectionAPI
+	ListFunc    func(ctx context.Context) ([]katapultpro.Section, error)
+	GetFunc     func(ctx context.Context, sectionID string) (*katapultpro.Section, error)
+	SectionMock *JobSectionAPI
+}
+
+var _ katapultpro.JobSectionsAPI = (*JobSectionsAPI)(nil)
+
+// Section implements katapultpro.JobSectionsAPI.
+func (m *JobSectionsAPI) Section(sectionID string) katapultpro.JobSectionAPI {
+	m.record("Section", sectionID)
+	if m.SectionFunc != nil {
+		return m.SectionFunc(sectionID)
+	}
+	return lazy(&m.Recorder, &m.SectionMock)
+}
+
+// List implements katapultpro.JobSectionsAPI.
+func (m *JobSectionsAPI) List(ctx context.Context) ([]katapultpro.Section, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.Section
+	return r0, notStubbed("JobSectionsAPI", "List")
+}
+
+// Get implements katapultpro.JobSectionsAPI.
+func (m *JobSectionsAPI) Get(ctx context.Context, sectionID string) (*katapultpro.Section, error) {
+	m.record("Get", sectionID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, sectionID)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("JobSectionsAPI", "Get")
+}
+
+// JobSectionAPI is a mock katapultpro.JobSectionAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type JobSectionAPI struct {
+	Recorder
+
+	SectionIDFunc func() string
+	GetFunc       func(ctx context.Context) (*katapultpro.Section, error)
+}
+
+var _ katapultpro.JobSectionAPI = (*JobSectionAPI)(nil)
+
+// SectionID implements katapultpro.JobSectionAPI.
+func (m *JobSectionAPI) SectionID() string {
+	m.record("SectionID")
+	if m.SectionIDFunc != nil {
+		return m.SectionIDFunc()
+	}
+	var r0 string
+	return r0
+}
+
+// Get implements katapultpro.JobSectionAPI.
+func (m *JobSectionAPI) Get(ctx context.Context) (*katapultpro.Section, error) {
+	m.record("Get")
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	var r0 *katapultpro.Section
+	return r0, notStubbed("JobSectionAPI", "Get")
+}
+
+// PhotosAPI is a mock katapultpro.PhotosAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type PhotosAPI struct {
+	Recorder
+
+	PhotoFunc                   func(photoID string) katapultpro.PhotoAPI
+	ListFunc                    func(ctx context.Context) ([]katapultpro.Photo, error)
+	GetFunc                     func(ctx context.Context, photoID string) (*katapultpro.Photo, error)
+	UploadFunc                  func(ctx context.Context, imageData io.Reader) (*katapultpro.Photo, error)
+	UploadWithOptionsFunc       func(ctx context.Context, imageData io.Reader, opts *katapultpro.PhotoUploadOptions) (*katapultpro.Photo, error)
+	AssociateFunc               func(ctx context.Context, photoID string, req *katapultpro.AssociatePhotoRequest) error
+	ListElementsFunc            func(ctx context.Context, photoID string) ([]katapultpro.PhotoElement, error)
+	ListAllElementsFunc         func(ctx context.Context, photoIDs []string, concurrency int) (map[string][]katapultpro.PhotoElement, error)
+	GetElementFunc              func(ctx context.Context, photoID string, elementID string) (*katapultpro.PhotoElement, error)
+	CreateElementFunc           func(ctx context.Context, photoID string, req *katapultpro.CreatePhotoElementRequest) (*katapultpro.PhotoElement, error)
+	UpdateElementFunc           func(ctx context.Context, photoID string, elementID string, req *katapultpro.UpdatePhotoElementRequest, opts *katapultpro.UpdatePhotoElementOptions) (*katapultpro.PhotoElement, error)
+	DeleteElementFunc           func(ctx context.Context, photoID string, elementID string) error
+	ListCalibrationAnchorsFunc  func(ctx context.Context, photoID string) ([]katapultpro.PhotoCalibrationAnchor, error)
+	GetCalibrationAnchorFunc    func(ctx context.Context, photoID string, anchorID string) (*katapultpro.PhotoCalibrationAnchor, error)
+	CreateCalibrationAnchorFunc func(ctx context.Context, photoID string, req *katapultpro.CreatePhotoCalibrationAnchorRequest) (*katapultpro.PhotoCalibrationAnchor, error)
+	UpdateCalibrationAnchorFunc func(ctx context.Context, photoID string, anchorID string, req *katapultpro.UpdatePhotoCalibrationAnchorRequest, opts *katapultpro.UpdatePhotoCalibrationAnchorOptions) (*katapultpro.PhotoCalibrationAnchor, error)
+	DeleteCalibrationAnchorFunc func(ctx context.Context, photoID string, anchorID string) error
+	PhotoMock                   *PhotoAPI
+}
+
+var _ katapultpro.PhotosAPI = (*PhotosAPI)(nil)
+
+// Photo implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) Photo(photoID string) katapultpro.PhotoAPI {
+	m.record("Photo", photoID)
+	if m.PhotoFunc != nil {
+		return m.PhotoFunc(photoID)
+	}
+	return lazy(&m.Recorder, &m.PhotoMock)
+}
+
+// List implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) List(ctx context.Context) ([]katapultpro.Photo, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.Photo
+	return r0, notStubbed("PhotosAPI", "List")
+}
+
+// Get implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) Get(ctx context.Context, photoID string) (*katapultpro.Photo, error) {
+	m.record("Get", photoID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, photoID)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("PhotosAPI", "Get")
+}
+
+// Upload implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) Upload(ctx context.Context, imageData io.Reader) (*katapultpro.Photo, error) {
+	m.record("Upload", imageData)
+	if m.UploadFunc != nil {
+		return m.UploadFunc(ctx, imageData)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("PhotosAPI", "Upload")
+}
+
+// UploadWithOptions implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) UploadWithOptions(ctx context.Context, imageData io.Reader, opts *katapultpro.PhotoUploadOptions) (*katapultpro.Photo, error) {
+	m.record("UploadWithOptions", imageData, opts)
+	if m.UploadWithOptionsFunc != nil {
+		return m.UploadWithOptionsFunc(ctx, imageData, opts)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("PhotosAPI", "UploadWithOptions")
+}
+
+// Associate implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) Associate(ctx context.Context, photoID string, req *katapultpro.AssociatePhotoRequest) error {
+	m.record("Associate", photoID, req)
+	if m.AssociateFunc != nil {
+		return m.AssociateFunc(ctx, photoID, req)
+	}
+	return notStubbed("PhotosAPI", "Associate")
+}
+
+// ListElements implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) ListElements(ctx context.Context, photoID string) ([]katapultpro.PhotoElement, error) {
+	m.record("ListElements", photoID)
+	if m.ListElementsFunc != nil {
+		return m.ListElementsFunc(ctx, photoID)
+	}
+	var r0 []katapultpro.PhotoElement
+	return r0, notStubbed("PhotosAPI", "ListElements")
+}
+
+// ListAllElements implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) ListAllElements(ctx context.Context, photoIDs []string, concurrency int) (map[string][]katapultpro.PhotoElement, error) {
+	m.record("ListAllElements", photoIDs, concurrency)
+	if m.ListAllElementsFunc != nil {
+		return m.ListAllElementsFunc(ctx, photoIDs, concurrency)
+	}
+	var r0 map[string][]katapultpro.PhotoElement
+	return r0, notStubbed("PhotosAPI", "ListAllElements")
+}
+
+// GetElement implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) GetElement(ctx context.Context, photoID string, elementID string) (*katapultpro.PhotoElement, error) {
+	m.record("GetElement", photoID, elementID)
+	if m.GetElementFunc != nil {
+		return m.GetElementFunc(ctx, photoID, elementID)
+	}
+	var r0 *katapultpro.PhotoElement
+	return r0, notStubbed("PhotosAPI", "GetElement")
+}
+
+// CreateElement implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) CreateElement(ctx context.Context, photoID string, req *katapultpro.CreatePhotoElementRequest) (*katapultpro.PhotoElement, error) {
+	m.record("CreateElement", photoID, req)
+	if m.CreateElementFunc != nil {
+		return m.CreateElementFunc(ctx, photoID, req)
+	}
+	var r0 *katapultpro.PhotoElement
+	return r0, notStubbed("PhotosAPI", "CreateElement")
+}
+
+// UpdateElement implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) UpdateElement(ctx context.Context, photoID string, elementID string, req *katapultpro.UpdatePhotoElementRequest, opts *katapultpro.UpdatePhotoElementOptions) (*katapultpro.PhotoElement, error) {
+	m.record("UpdateElement", photoID, elementID, req, opts)
+	if m.UpdateElementFunc != nil {
+		return m.UpdateElementFunc(ctx, photoID, elementID, req, opts)
+	}
+	var r0 *katapultpro.PhotoElement
+	return r0, notStubbed("PhotosAPI", "UpdateElement")
+}
+
+// DeleteElement implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) DeleteElement(ctx context.Context, photoID string, elementID string) error {
+	m.record("DeleteElement", photoID, elementID)
+	if m.DeleteElementFunc != nil {
+		return m.DeleteElementFunc(ctx, photoID, elementID)
+	}
+	return notStubbed("PhotosAPI", "DeleteElement")
+}
+
+// ListCalibrationAnchors implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) ListCalibrationAnchors(ctx context.Context, photoID string) ([]katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("ListCalibrationAnchors", photoID)
+	if m.ListCalibrationAnchorsFunc != nil {
+		return m.ListCalibrationAnchorsFunc(ctx, photoID)
+	}
+	var r0 []katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("PhotosAPI", "ListCalibrationAnchors")
+}
+
+// GetCalibrationAnchor implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) GetCalibrationAnchor(ctx context.Context, photoID string, anchorID string) (*katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("GetCalibrationAnchor", photoID, anchorID)
+	if m.GetCalibrationAnchorFunc != nil {
+		return m.GetCalibrationAnchorFunc(ctx, photoID, anchorID)
+	}
+	var r0 *katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("PhotosAPI", "GetCalibrationAnchor")
+}
+
+// CreateCalibrationAnchor implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) CreateCalibrationAnchor(ctx context.Context, photoID string, req *katapultpro.CreatePhotoCalibrationAnchorRequest) (*katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("CreateCalibrationAnchor", photoID, req)
+	if m.CreateCalibrationAnchorFunc != nil {
+		return m.CreateCalibrationAnchorFunc(ctx, photoID, req)
+	}
+	var r0 *katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("PhotosAPI", "CreateCalibrationAnchor")
+}
+
+// UpdateCalibrationAnchor implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) UpdateCalibrationAnchor(ctx context.Context, photoID string, anchorID string, req *katapultpro.UpdatePhotoCalibrationAnchorRequest, opts *katapultpro.UpdatePhotoCalibrationAnchorOptions) (*katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("UpdateCalibrationAnchor", photoID, anchorID, req, opts)
+	if m.UpdateCalibrationAnchorFunc != nil {
+		return m.UpdateCalibrationAnchorFunc(ctx, photoID, anchorID, req, opts)
+	}
+	var r0 *katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("PhotosAPI", "UpdateCalibrationAnchor")
+}
+
+// DeleteCalibrationAnchor implements katapultpro.PhotosAPI.
+func (m *PhotosAPI) DeleteCalibrationAnchor(ctx context.Context, photoID string, anchorID string) error {
+	m.record("DeleteCalibrationAnchor", photoID, anchorID)
+	if m.DeleteCalibrationAnchorFunc != nil {
+		return m.DeleteCalibrationAnchorFunc(ctx, photoID, anchorID)
+	}
+	return notStubbed("PhotosAPI", "DeleteCalibrationAnchor")
+}
+
+// PhotoAPI is a mock katapultpro.PhotoAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type PhotoAPI struct {
+	Recorder
+
+	PhotoIDFunc   func() string
+	GetFunc       func(ctx context.Context) (*katapultpro.Photo, error)
+	AssociateFunc func(ctx context.Context, req *katapultpro.AssociatePhotoRequest) error
+	URLFunc       func(ctx context.Context, size katapultpro.PhotoSize) (string, error)
+	DownloadFunc  func(ctx context.Context, size katapultpro.PhotoSize, w io.Writer) (int64, error)
+	OpenImageFunc func(ctx context.Context, size katapultpro.PhotoSize, offset int64) (*katapultpro.PhotoImage, error)
+	ElementsFunc  func() katapultpro.ElementsAPI
+	AnchorsFunc   func() katapultpro.AnchorsAPI
+	ElementsMock  *ElementsAPI
+	AnchorsMock   *AnchorsAPI
+}
+
+var _ katapultpro.PhotoAPI = (*PhotoAPI)(nil)
+
+// PhotoID implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) PhotoID() string {
+	m.record("PhotoID")
+	if m.PhotoIDFunc != nil {
+		return m.PhotoIDFunc()
+	}
+	var r0 string
+	return r0
+}
+
+// Get implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) Get(ctx context.Context) (*katapultpro.Photo, error) {
+	m.record("Get")
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx)
+	}
+	var r0 *katapultpro.Photo
+	return r0, notStubbed("PhotoAPI", "Get")
+}
+
+// Associate implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) Associate(ctx context.Context, req *katapultpro.AssociatePhotoRequest) error {
+	m.record("Associate", req)
+	if m.AssociateFunc != nil {
+		return m.AssociateFunc(ctx, req)
+	}
+	return notStubbed("PhotoAPI", "Associate")
+}
+
+// URL implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) URL(ctx context.Context, size katapultpro.PhotoSize) (string, error) {
+	m.record("URL", size)
+	if m.URLFunc != nil {
+		return m.URLFunc(ctx, size)
+	}
+	var r0 string
+	return r0, notStubbed("PhotoAPI", "URL")
+}
+
+// Download implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) Download(ctx context.Context, size katapultpro.PhotoSize, w io.Writer) (int64, error) {
+	m.record("Download", size, w)
+	if m.DownloadFunc != nil {
+		return m.DownloadFunc(ctx, size, w)
+	}
+	var r0 int64
+	return r0, notStubbed("PhotoAPI", "Download")
+}
+
+// OpenImage implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) OpenImage(ctx context.Context, size katapultpro.PhotoSize, offset int64) (*katapultpro.PhotoImage, error) {
+	m.record("OpenImage", size, offset)
+	if m.OpenImageFunc != nil {
+		return m.OpenImageFunc(ctx, size, offset)
+	}
+	var r0 *katapultpro.PhotoImage
+	return r0, notStubbed("PhotoAPI", "OpenImage")
+}
+
+// Elements implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) Elements() katapultpro.ElementsAPI {
+	m.record("Elements")
+	if m.ElementsFunc != nil {
+		return m.ElementsFunc()
+	}
+	return lazy(&m.Recorder, &m.ElementsMock)
+}
+
+// Anchors implements katapultpro.PhotoAPI.
+func (m *PhotoAPI) Anchors() katapultpro.AnchorsAPI {
+	m.record("Anchors")
+	if m.AnchorsFunc != nil {
+		return m.AnchorsFunc()
+	}
+	return lazy(&m.Recorder, &m.AnchorsMock)
+}
+
+// ElementsAPI is a mock katapultpro.ElementsAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type ElementsAPI struct {
+	Recorder
+
+	ListFunc   func(ctx context.Context) ([]katapultpro.PhotoElement, error)
+	GetFunc    func(ctx context.Context, elementID string) (*katapultpro.PhotoElement, error)
+	CreateFunc func(ctx context.Context, req *katapultpro.CreatePhotoElementRequest) (*katapultpro.PhotoElement, error)
+	UpdateFunc func(ctx context.Context, elementID string, req *katapultpro.UpdatePhotoElementRequest, opts *katapultpro.UpdatePhotoElementOptions) (*katapultpro.PhotoElement, error)
+	DeleteFunc func(ctx context.Context, elementID string) error
+}
+
+var _ katapultpro.ElementsAPI = (*ElementsAPI)(nil)
+
+// List implements katapultpro.ElementsAPI.
+func (m *ElementsAPI) List(ctx context.Context) ([]katapultpro.PhotoElement, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.PhotoElement
+	return r0, notStubbed("ElementsAPI", "List")
+}
+
+// Get implements katapultpro.ElementsAPI.
+func (m *ElementsAPI) Get(ctx context.Context, elementID string) (*katapultpro.PhotoElement, error) {
+	m.record("Get", elementID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, elementID)
+	}
+	var r0 *katapultpro.PhotoElement
+	return r0, notStubbed("ElementsAPI", "Get")
+}
+
+// Create implements katapultpro.ElementsAPI.
+func (m *ElementsAPI) Create(ctx context.Context, req *katapultpro.CreatePhotoElementRequest) (*katapultpro.PhotoElement, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.PhotoElement
+	return r0, notStubbed("ElementsAPI", "Create")
+}
+
+// Update implements katapultpro.ElementsAPI.
+func (m *ElementsAPI) Update(ctx context.Context, elementID string, req *katapultpro.UpdatePhotoElementRequest, opts *katapultpro.UpdatePhotoElementOptions) (*katapultpro.PhotoElement, error) {
+	m.record("Update", elementID, req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, elementID, req, opts)
+	}
+	var r0 *katapultpro.PhotoElement
+	return r0, notStubbed("ElementsAPI", "Update")
+}
+
+// Delete implements katapultpro.ElementsAPI.
+func (m *ElementsAPI) Delete(ctx context.Context, elementID string) error {
+	m.record("Delete", elementID)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, elementID)
+	}
+	return notStubbed("ElementsAPI", "Delete")
+}
+
+// AnchorsAPI is a mock katapultpro.AnchorsAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type AnchorsAPI struct {
+	Recorder
+
+	ListFunc   func(ctx context.Context) ([]katapultpro.PhotoCalibrationAnchor, error)
+	GetFunc    func(ctx context.Context, anchorID string) (*katapultpro.PhotoCalibrationAnchor, error)
+	CreateFunc func(ctx context.Context, req *katapultpro.CreatePhotoCalibrationAnchorRequest) (*katapultpro.PhotoCalibrationAnchor, error)
+	UpdateFunc func(ctx context.Context, anchorID string, req *katapultpro.UpdatePhotoCalibrationAnchorRequest, opts *katapultpro.UpdatePhotoCalibrationAnchorOptions) (*katapultpro.PhotoCalibrationAnchor, error)
+	DeleteFunc func(ctx context.Context, anchorID string) error
+}
+
+var _ katapultpro.AnchorsAPI = (*AnchorsAPI)(nil)
+
+// List implements katapultpro.AnchorsAPI.
+func (m *AnchorsAPI) List(ctx context.Context) ([]katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("AnchorsAPI", "List")
+}
+
+// Get implements katapultpro.AnchorsAPI.
+func (m *AnchorsAPI) Get(ctx context.Context, anchorID string) (*katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("Get", anchorID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, anchorID)
+	}
+	var r0 *katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("AnchorsAPI", "Get")
+}
+
+// Create implements katapultpro.AnchorsAPI.
+func (m *AnchorsAPI) Create(ctx context.Context, req *katapultpro.CreatePhotoCalibrationAnchorRequest) (*katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("AnchorsAPI", "Create")
+}
+
+// Update implements katapultpro.AnchorsAPI.
+func (m *AnchorsAPI) Update(ctx context.Context, anchorID string, req *katapultpro.UpdatePhotoCalibrationAnchorRequest, opts *katapultpro.UpdatePhotoCalibrationAnchorOptions) (*katapultpro.PhotoCalibrationAnchor, error) {
+	m.record("Update", anchorID, req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, anchorID, req, opts)
+	}
+	var r0 *katapultpro.PhotoCalibrationAnchor
+	return r0, notStubbed("AnchorsAPI", "Update")
+}
+
+// Delete implements katapultpro.AnchorsAPI.
+func (m *AnchorsAPI) Delete(ctx context.Context, anchorID string) error {
+	m.record("Delete", anchorID)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, anchorID)
+	}
+	return notStubbed("AnchorsAPI", "Delete")
+}
+
+// TracesAPI is a mock katapultpro.TracesAPI. Set a <Method>Func field to stub a method; unstubbed
+// methods return ErrNotStubbed (or, for drill-down methods, the <Method>Mock child, created on first use).
+type TracesAPI struct {
+	Recorder
+
+	ListFunc   func(ctx context.Context) ([]katapultpro.Trace, error)
+	GetFunc    func(ctx context.Context, traceID string) (*katapultpro.Trace, error)
+	CreateFunc func(ctx context.Context, req *katapultpro.CreateTraceRequest) (*katapultpro.Trace, error)
+	UpdateFunc func(ctx context.Context, traceID string, req *katapultpro.UpdateTraceRequest, opts *katapultpro.UpdateTraceOptions) (*katapultpro.Trace, error)
+	DeleteFunc func(ctx context.Context, traceID string) error
+}
+
+var _ katapultpro.TracesAPI = (*TracesAPI)(nil)
+
+// List implements katapultpro.TracesAPI.
+func (m *TracesAPI) List(ctx context.Context) ([]katapultpro.Trace, error) {
+	m.record("List")
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	var r0 []katapultpro.Trace
+	return r0, notStubbed("TracesAPI", "List")
+}
+
+// Get implements katapultpro.TracesAPI.
+func (m *TracesAPI) Get(ctx context.Context, traceID string) (*katapultpro.Trace, error) {
+	m.record("Get", traceID)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, traceID)
+	}
+	var r0 *katapultpro.Trace
+	return r0, notStubbed("TracesAPI", "Get")
+}
+
+// Create implements katapultpro.TracesAPI.
+func (m *TracesAPI) Create(ctx context.Context, req *katapultpro.CreateTraceRequest) (*katapultpro.Trace, error) {
+	m.record("Create", req)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	var r0 *katapultpro.Trace
+	return r0, notStubbed("TracesAPI", "Create")
+}
+
+// Update implements katapultpro.TracesAPI.
+func (m *TracesAPI) Update(ctx context.Context, traceID string, req *katapultpro.UpdateTraceRequest, opts *katapultpro.UpdateTraceOptions) (*katapultpro.Trace, error) {
+	m.record("Update", traceID, req, opts)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, traceID, req, opts)
+	}
+	var r0 *katapultpro.Trace
+	return r0, notStubbed("TracesAPI", "Update")
+}
+
+// Delete implements katapultpro.TracesAPI.
+func (m *TracesAPI) Delete(ctx context.Context, traceID string) error {
+	m.record("Delete", traceID)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, traceID)
+	}
+	return notStubbed("TracesAPI", "Delete")
+}
@@ -0,0 +1,63 @@
+package katapultmock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapultmock"
+)
+
+// retag is code under test that depends only on the interfaces.
+func retag(ctx context.Context, job katapultpro.JobAPI, nodeID, tag string) error {
+	_, err := job.Nodes().Node(nodeID).Update(ctx, &katapultpro.UpdateNodeRequest{
+		AddAttributes: map[string]interface{}{"pole_tag": tag},
+	}, &katapultpro.UpdateNodeOptions{OnlyIfExists: true})
+	return err
+}
+
+func TestMocks_DrillDownAndAssertions(t *testing.T) {
+	node := &katapultmock.NodeAPI{
+		UpdateFunc: func(ctx context.Context, req *katapultpro.UpdateNodeRequest, opts *katapultpro.UpdateNodeOptions) (*katapultpro.Node, error) {
+			return &katapultpro.Node{ID: "n1"}, nil
+		},
+	}
+	nodes := &katapultmock.NodesAPI{NodeMock: node}
+	job := &katapultmock.JobAPI{NodesMock: nodes}
+
+	if err := retag(context.Background(), job, "n1", "T-1"); err != nil {
+		t.Fatal(err)
+	}
+	nodes.AssertCalled(t, "Node", "n1")
+	node.AssertCallCount(t, "Update", 1)
+	node.AssertCalled(t, "Update", &katapultpro.UpdateNodeRequest{AddAttributes: map[string]interface{}{"pole_tag": "T-1"}}, katapultmock.Any)
+	node.AssertNotCalled(t, "Delete")
+
+	// Failing assertions report through TB.
+	var rec recordingTB
+	node.AssertCalled(&rec, "Update", katapultmock.Any, &katapultpro.UpdateNodeOptions{})
+	if len(rec.errors) != 1 {
+		t.Errorf("expected mismatched assertion to fail, got %v", rec.errors)
+	}
+}
+
+func TestMocks_UnstubbedAndLazyChildren(t *testing.T) {
+	client := &katapultmock.ClientAPI{}
+	_, err := client.Job("j1").Photos().Photo("p1").Anchors().List(context.Background())
+	if !errors.Is(err, katapultmock.ErrNotStubbed) {
+		t.Errorf("expected ErrNotStubbed, got %v", err)
+	}
+	if client.JobMock == nil || client.JobMock.PhotosMock == nil || client.JobMock.PhotosMock.PhotoMock == nil {
+		t.Fatal("expected drill-down children to be created")
+	}
+	client.JobMock.PhotosMock.AssertCalled(t, "Photo", "p1")
+}
+
+type recordingTB struct{ errors []string }
+
+func (r *recordingTB) Helper() {}
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
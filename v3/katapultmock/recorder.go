@@ -0,0 +1,152 @@
+// Package katapultmock provides mock implementations of the katapultpro *API interfaces for unit tests.
+//
+// Each mock has a <Method>Func field per method. Stub the methods your code calls; every call is
+// recorded (without the context) for assertions. Drill-down methods such as Node or Photo return
+// the <Method>Mock child, created on first use, so chains work without setup:
+//
+//	job := &katapultmock.JobAPI{}
+//	node := &katapultmock.NodeAPI{UpdateFunc: func(ctx context.Context, req *katapultpro.UpdateNodeRequest, opts *katapultpro.UpdateNodeOptions) (*katapultpro.Node, error) {
+//	    return &katapultpro.Node{ID: "n1"}, nil
+//	}}
+//	job.NodesMock = &katapultmock.NodesAPI{NodeMock: node}
+//
+//	_ = code.Under.Test(ctx, job) // calls job.Nodes().Node("n1").Update(...)
+//
+//	job.NodesMock.AssertCalled(t, "Node", "n1")
+//	node.AssertCallCount(t, "Update", 1)
+//
+// The mocks are generated from the interfaces in api.go; run go generate after changing them.
+package katapultmock
+
+//go:generate go run ./internal/mockgen -src ../api.go -out mocks.go
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrNotStubbed is returned (wrapped) by mock methods whose Func field is nil.
+var ErrNotStubbed = errors.New("katapultmock: method not stubbed")
+
+func notStubbed(mock, method string) error {
+	return fmt.Errorf("%w: %s.%s", ErrNotStubbed, mock, method)
+}
+
+// Call is one recorded method call. Args excludes the context.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Any matches any argument in AssertCalled.
+var Any any = anyArg{}
+
+type anyArg struct{}
+
+// TB is the subset of testing.TB used by the assertion helpers.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Recorder records calls made on a mock. It is embedded in every mock and safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every recorded call in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the recorded calls to method.
+func (r *Recorder) CallsTo(method string) []Call {
+	var out []Call
+	for _, c := range r.Calls() {
+		if c.Method == method {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Reset clears the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled reports an error unless method was called with args. Args are compared with
+// reflect.DeepEqual; pass Any to skip a position, and fewer args than the method takes to match a prefix.
+func (r *Recorder) AssertCalled(t TB, method string, args ...any) bool {
+	t.Helper()
+	calls := r.CallsTo(method)
+	for _, c := range calls {
+		if matchArgs(c.Args, args) {
+			return true
+		}
+	}
+	if len(calls) == 0 {
+		t.Errorf("katapultmock: expected call to %s, got none", method)
+	} else {
+		t.Errorf("katapultmock: no call to %s matched %v; calls: %v", method, args, calls)
+	}
+	return false
+}
+
+// AssertNotCalled reports an error if method was called.
+func (r *Recorder) AssertNotCalled(t TB, method string) bool {
+	t.Helper()
+	if calls := r.CallsTo(method); len(calls) > 0 {
+		t.Errorf("katapultmock: expected no call to %s, got %v", method, calls)
+		return false
+	}
+	return true
+}
+
+// AssertCallCount reports an error unless method was called exactly n times.
+func (r *Recorder) AssertCallCount(t TB, method string, n int) bool {
+	t.Helper()
+	if got := len(r.CallsTo(method)); got != n {
+		t.Errorf("katapultmock: expected %d calls to %s, got %d", n, method, got)
+		return false
+	}
+	return true
+}
+
+func matchArgs(got, want []any) bool {
+	if len(want) > len(got) {
+		return false
+	}
+	for i, w := range want {
+		if w == Any {
+			continue
+		}
+		if !reflect.DeepEqual(got[i], w) {
+			return false
+		}
+	}
+	return true
+}
+
+// lazy returns *child, creating it under r's lock on first use.
+func lazy[T any](r *Recorder, child **T) *T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if *child == nil {
+		*child = new(T)
+	}
+	return *child
+}
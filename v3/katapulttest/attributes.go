@@ -0,0 +1,130 @@
+package katapulttest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"regexp"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+var (
+	idPattern         = regexp.MustCompile(`^[A-Za-z0-9_-]{20,256}$`)
+	instanceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// validID reports whether id meets the API's resource ID requirements
+// (alphanumeric, dashes and underscores, 20-256 characters).
+func validID(id string) bool { return idPattern.MatchString(id) }
+
+const pushAlphabet = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+// newID returns a random 20-character ID shaped like the Firebase push IDs the API generates.
+func newID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	b[0] = '-'
+	for i := 1; i < len(b); i++ {
+		b[i] = pushAlphabet[int(b[i])%len(pushAlphabet)]
+	}
+	return string(b)
+}
+
+// attributeBody holds the attribute fields of a create or update request as raw JSON so that
+// explicit nulls can be told apart from missing fields.
+type attributeBody struct {
+	RemoveAttributes []string                              `json:"remove_attributes"`
+	Attributes       map[string]map[string]json.RawMessage `json:"attributes"`
+	AddAttributes    map[string]json.RawMessage            `json:"add_attributes"`
+}
+
+// validate checks instance IDs in the partial attribute list.
+func (b *attributeBody) validate() error {
+	for _, instances := range b.Attributes {
+		for id := range instances {
+			if !instanceIDPattern.MatchString(id) {
+				return errBadRequest("invalid attribute instance id " + id)
+			}
+		}
+	}
+	return nil
+}
+
+// apply updates list following the documented order: remove_attributes, then attributes
+// (a null attribute or instance removes it), then add_attributes (with generated instance IDs).
+func (b *attributeBody) apply(list katapultpro.EntityAttributeList) katapultpro.EntityAttributeList {
+	if list == nil {
+		list = katapultpro.EntityAttributeList{}
+	}
+	for _, name := range b.RemoveAttributes {
+		delete(list, name)
+	}
+	for name, instances := range b.Attributes {
+		if instances == nil {
+			delete(list, name)
+			continue
+		}
+		cur := list[name]
+		if cur == nil {
+			cur = map[string]interface{}{}
+		}
+		for id, raw := range instances {
+			if isNull(raw) {
+				delete(cur, id)
+				continue
+			}
+			cur[id] = decode(raw)
+		}
+		if len(cur) == 0 {
+			delete(list, name)
+		} else {
+			list[name] = cur
+		}
+	}
+	for name, raw := range b.AddAttributes {
+		if isNull(raw) {
+			continue
+		}
+		if list[name] == nil {
+			list[name] = map[string]interface{}{}
+		}
+		list[name][newID()] = decode(raw)
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// mergeFlat applies a flat attribute patch (jobs metadata, photo elements, traces): null removes a key.
+func mergeFlat(dst map[string]interface{}, patch map[string]json.RawMessage) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for k, raw := range patch {
+		if isNull(raw) {
+			delete(dst, k)
+			continue
+		}
+		dst[k] = decode(raw)
+	}
+	return dst
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+func decode(raw json.RawMessage) interface{} {
+	var v interface{}
+	_ = json.Unmarshal(raw, &v)
+	return v
+}
+
+// clone deep-copies a JSON-compatible value by round-tripping it, so callers never share state with the server.
+func clone[T any](v T) T {
+	var out T
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &out)
+	return out
+}
@@ -0,0 +1,77 @@
+package katapulttest
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SignedURLTTL is how long the signed image URLs returned by the v2 photoURL route stay valid.
+const SignedURLTTL = 15 * time.Minute
+
+// imagePathPrefix is where signed image URLs point. Requests under it are not API calls.
+const imagePathPrefix = "/_images/"
+
+// AddPhotoImage sets the image bytes served for a photo (at every size). Uploads set them automatically.
+// It panics if the job does not exist.
+func (s *Server) AddPhotoImage(jobID, photoID string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mustJob(jobID).images[photoID] = append([]byte(nil), data...)
+}
+
+// photoURL serves GET /v2/jobs/:job_id/photoURL/:photo_id, which answers without the v3 envelope.
+// The caller holds s.mu.
+func (s *Server) photoURL(w http.ResponseWriter, r *http.Request) {
+	seg := splitPath(r.URL.Path)
+	if r.Method != http.MethodGet || len(seg) != 5 || seg[1] != "jobs" || seg[3] != "photoURL" {
+		s.writeError(w, errNotFound("route "+r.URL.Path))
+		return
+	}
+	job, ok := s.jobs[seg[2]]
+	if !ok {
+		s.writeError(w, errNotFound("job "+seg[2]))
+		return
+	}
+	if _, ok := job.images[seg[4]]; !ok {
+		s.writeError(w, errNotFound("photo image "+seg[4]))
+		return
+	}
+	size := r.URL.Query().Get("file_size")
+	if size == "" {
+		size = "extra_large"
+	}
+	s.tokens -= GetTokenCost
+	q := url.Values{"Expires": {strconv.FormatInt(s.now().Add(SignedURLTTL).Unix(), 10)}, "token": {newID()}}
+	u := "http://" + r.Host + imagePathPrefix + job.id + "/" + seg[4] + "/" + size + "?" + q.Encode()
+	s.write(w, http.StatusOK, map[string]string{"url": u})
+}
+
+// serveImage serves a signed image URL, honoring Range requests. Expired URLs get 403.
+// The caller holds s.mu.
+func (s *Server) serveImage(w http.ResponseWriter, r *http.Request) {
+	seg := splitPath(r.URL.Path)
+	expires, err := strconv.ParseInt(r.URL.Query().Get("Expires"), 10, 64)
+	if err != nil || !s.now().Before(time.Unix(expires, 0)) {
+		http.Error(w, "signed URL expired", http.StatusForbidden)
+		return
+	}
+	if len(seg) != 4 {
+		http.NotFound(w, r)
+		return
+	}
+	job, ok := s.jobs[seg[1]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	data, ok := job.images[seg[2]]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/jpeg")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
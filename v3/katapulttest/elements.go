@@ -0,0 +1,266 @@
+package katapulttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+var manualHeightPattern = regexp.MustCompile(`^\d+-\d+(\.\d+)?$`)
+
+// elementBody is a create or update photo element request. parent_id and attributes are kept raw so
+// nulls can remove them.
+type elementBody struct {
+	ElementType      *string                     `json:"element_type"`
+	PixelSelection   *katapultpro.PixelSelection `json:"pixel_selection"`
+	ManualHeight     *string                     `json:"manual_height"`
+	Attributes       map[string]json.RawMessage  `json:"attributes"`
+	ParentID         json.RawMessage             `json:"parent_id"`
+	TraceID          *string                     `json:"trace_id"`
+	PoleTopExtension *bool                       `json:"pole_top_extension"`
+}
+
+// findElement returns the type and stored object of the element with the given ID.
+func findElement(pfd object, id string) (string, object, bool) {
+	for _, typ := range sortedKeys(pfd) {
+		if typ == anchorKey || typ == "_editors" {
+			continue
+		}
+		if el, ok := child(pfd, typ, false)[id].(map[string]any); ok {
+			return typ, el, true
+		}
+	}
+	return "", nil, false
+}
+
+// elementView returns a stored element as the photo element endpoints report it.
+func elementView(typ, id string, el object) object {
+	out := object{
+		"id":           id,
+		"element_type": typ,
+		"_context":     object{"id": id, "path": typ + "/" + id, "type": typ},
+	}
+	attrs := object{}
+	for k, v := range el {
+		if elementFields[k] {
+			out[k] = clone(v)
+		} else {
+			attrs[k] = clone(v)
+		}
+	}
+	if len(attrs) > 0 {
+		out["attributes"] = attrs
+	}
+	return out
+}
+
+func (s *Server) routeElements(c *call, job *jobState, photoID string, photo object, seg []string) (any, error) {
+	pfd := child(photo, "photofirst_data", c.method == http.MethodPost)
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		out := []object{}
+		for _, typ := range sortedKeys(pfd) {
+			if typ == anchorKey || typ == "_editors" {
+				continue
+			}
+			els := child(pfd, typ, false)
+			for _, id := range sortedKeys(els) {
+				if el, ok := els[id].(map[string]any); ok {
+					out = append(out, elementView(typ, id, el))
+				}
+			}
+		}
+		return out, nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		return s.writeElement(c, job, photoID, pfd, newID(), "", nil)
+	case len(seg) == 1 && c.method == http.MethodGet:
+		typ, el, ok := findElement(pfd, seg[0])
+		if !ok {
+			return nil, errNotFound("photo element " + seg[0])
+		}
+		return elementView(typ, seg[0], el), nil
+	case len(seg) == 1 && c.method == http.MethodPost:
+		typ, el, ok := findElement(pfd, seg[0])
+		id := seg[0]
+		if !ok {
+			if c.onlyIfExists() {
+				return nil, errNotFound("photo element " + id)
+			}
+			// The API creates an element with a random ID when the given one does not exist.
+			id = newID()
+		}
+		return s.writeElement(c, job, photoID, pfd, id, typ, el)
+	case len(seg) == 1 && c.method == http.MethodDelete:
+		typ, el, ok := findElement(pfd, seg[0])
+		if !ok {
+			return nil, errNotFound("photo element " + seg[0])
+		}
+		setTraceItem(job, str(el["_trace"]), photoID, typ, seg[0], false)
+		delete(child(pfd, typ, false), seg[0])
+		return nil, nil
+	}
+	return nil, errMethod(c)
+}
+
+// writeElement creates (el == nil) or updates an element.
+func (s *Server) writeElement(c *call, job *jobState, photoID string, pfd object, id, typ string, el object) (any, error) {
+	var b elementBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	if el == nil {
+		if b.ElementType == nil || *b.ElementType == "" {
+			return nil, errBadRequest("element_type is required")
+		}
+		if *b.ElementType == anchorKey || *b.ElementType == "_editors" {
+			return nil, errBadRequest("invalid element_type " + *b.ElementType)
+		}
+		typ, el = *b.ElementType, object{}
+	} else if b.ElementType != nil && *b.ElementType != typ {
+		return nil, errBadRequest("element_type can only be set when creating an element")
+	}
+	if b.ManualHeight != nil && !manualHeightPattern.MatchString(*b.ManualHeight) {
+		return nil, errBadRequest("manual_height must be in feet-inches notation")
+	}
+	if b.TraceID != nil {
+		if _, ok := job.traces.get(*b.TraceID); !ok {
+			return nil, errBadRequest("trace " + *b.TraceID + " does not exist")
+		}
+	}
+	for k := range b.Attributes {
+		if elementFields[k] {
+			return nil, errBadRequest("attribute " + k + " is reserved")
+		}
+	}
+	if b.PixelSelection != nil {
+		el["pixel_selection"] = toAny([]katapultpro.PixelSelection{*b.PixelSelection})
+	}
+	if b.ManualHeight != nil {
+		el["manual_height"] = *b.ManualHeight
+	}
+	switch {
+	case b.PoleTopExtension != nil && *b.PoleTopExtension:
+		el["pole_top_extension"] = true
+	case b.PoleTopExtension != nil:
+		delete(el, "pole_top_extension")
+	}
+	switch {
+	case isNull(b.ParentID) && b.ParentID != nil:
+		delete(el, "parent_id")
+	case b.ParentID != nil:
+		el["parent_id"] = decode(b.ParentID)
+	}
+	mergeFlat(el, b.Attributes)
+	if b.TraceID != nil {
+		setTraceItem(job, str(el["_trace"]), photoID, typ, id, false)
+		el["_trace"] = *b.TraceID
+		setTraceItem(job, *b.TraceID, photoID, typ, id, true)
+	}
+	child(pfd, typ, true)[id] = el
+	return elementView(typ, id, el), nil
+}
+
+// setTraceItem adds or removes an element from a trace's items.
+func setTraceItem(job *jobState, traceID, photoID, typ, elementID string, add bool) {
+	trace, ok := job.traces.get(traceID)
+	if !ok {
+		return
+	}
+	items := child(trace, "items", add)
+	if items == nil {
+		return
+	}
+	photo := child(items, photoID, add)
+	if photo == nil {
+		return
+	}
+	els := child(photo, typ, add)
+	if add {
+		els[elementID] = true
+		return
+	}
+	delete(els, elementID)
+	if len(els) == 0 {
+		delete(photo, typ)
+	}
+	if len(photo) == 0 {
+		delete(items, photoID)
+	}
+}
+
+// anchorBody is a create or update calibration anchor request.
+type anchorBody struct {
+	PixelSelection *katapultpro.PixelSelection `json:"pixel_selection"`
+	Height         *float64                    `json:"height"`
+}
+
+func anchorView(id string, a object) object { return withID(id, a) }
+
+func (s *Server) routeAnchors(c *call, photo object, seg []string) (any, error) {
+	pfd := child(photo, "photofirst_data", c.method == http.MethodPost)
+	anchors := child(pfd, anchorKey, false)
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		out := []object{}
+		for _, id := range sortedKeys(anchors) {
+			if a, ok := anchors[id].(map[string]any); ok {
+				out = append(out, anchorView(id, a))
+			}
+		}
+		return out, nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		var b anchorBody
+		if err := c.decode(&b); err != nil {
+			return nil, err
+		}
+		if b.PixelSelection == nil || b.Height == nil {
+			return nil, errBadRequest("pixel_selection and height are required")
+		}
+		return writeAnchor(photo, pfd, newID(), object{}, b), nil
+	case len(seg) == 1 && c.method == http.MethodGet:
+		a, ok := anchors[seg[0]].(map[string]any)
+		if !ok {
+			return nil, errNotFound("calibration anchor " + seg[0])
+		}
+		return anchorView(seg[0], a), nil
+	case len(seg) == 1 && c.method == http.MethodPost:
+		var b anchorBody
+		if err := c.decode(&b); err != nil {
+			return nil, err
+		}
+		a, ok := anchors[seg[0]].(map[string]any)
+		if !ok {
+			if c.onlyIfExists() {
+				return nil, errNotFound("calibration anchor " + seg[0])
+			}
+			if !validID(seg[0]) {
+				return nil, errBadRequest("invalid calibration anchor id " + seg[0])
+			}
+			a = object{}
+		}
+		return writeAnchor(photo, pfd, seg[0], a, b), nil
+	case len(seg) == 1 && c.method == http.MethodDelete:
+		if _, ok := anchors[seg[0]]; !ok {
+			return nil, errNotFound("calibration anchor " + seg[0])
+		}
+		delete(anchors, seg[0])
+		delete(photo, "stick_align")
+		return nil, nil
+	}
+	return nil, errMethod(c)
+}
+
+// writeAnchor stores an anchor. Changing anchors un-calibrates the photo, so stick_align is removed.
+func writeAnchor(photo, pfd object, id string, a object, b anchorBody) object {
+	if b.PixelSelection != nil {
+		a["pixel_selection"] = toAny([]katapultpro.PixelSelection{*b.PixelSelection})
+	}
+	if b.Height != nil {
+		a["height"] = *b.Height
+	}
+	child(pfd, anchorKey, true)[id] = a
+	delete(photo, "stick_align")
+	return anchorView(id, a)
+}
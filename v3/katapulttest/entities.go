@@ -0,0 +1,341 @@
+package katapulttest
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// AddNode seeds a node in a job and returns it as stored. A random ID is generated when node.ID is empty.
+// It panics if the job does not exist.
+func (s *Server) AddNode(jobID string, node katapultpro.Node) katapultpro.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := node.ID
+	if id == "" {
+		id = newID()
+	}
+	o := toObject(node)
+	s.mustJob(jobID).nodes.put(id, o)
+	return fromObject[katapultpro.Node](id, o)
+}
+
+// AddConnection seeds a connection in a job and returns it as stored. Embedded sections are kept.
+// It panics if the job does not exist.
+func (s *Server) AddConnection(jobID string, conn katapultpro.Connection) katapultpro.Connection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := conn.ID
+	if id == "" {
+		id = newID()
+	}
+	o := toObject(conn)
+	s.mustJob(jobID).connections.put(id, o)
+	return fromObject[katapultpro.Connection](id, o)
+}
+
+// AddSection seeds a section on a connection and returns its ID. A random ID is generated when id is empty.
+// It panics if the job or connection does not exist.
+func (s *Server) AddSection(jobID, connectionID, id string, sec katapultpro.EmbeddedSection) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn, ok := s.mustJob(jobID).connections.get(connectionID)
+	if !ok {
+		panic("katapulttest: unknown connection " + connectionID)
+	}
+	if id == "" {
+		id = newID()
+	}
+	child(conn, "sections", true)[id] = toObject(sec)
+	return id
+}
+
+func (s *Server) mustJob(id string) *jobState {
+	j, ok := s.jobs[id]
+	if !ok {
+		panic("katapulttest: unknown job " + id)
+	}
+	return j
+}
+
+// position holds optional latitude and longitude request fields.
+type position struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+func (p *position) apply(o object) {
+	if p.Latitude != nil {
+		o["latitude"] = *p.Latitude
+	}
+	if p.Longitude != nil {
+		o["longitude"] = *p.Longitude
+	}
+}
+
+type nodeBody struct {
+	position
+	attributeBody
+}
+
+func (s *Server) created() object {
+	return object{"method": "api", "timestamp": s.now().UnixMilli()}
+}
+
+func (s *Server) createNode(c *call, job *jobState) (any, error) {
+	var b nodeBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	if b.Latitude == nil || b.Longitude == nil {
+		return nil, errBadRequest("latitude and longitude are required")
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	id := newID()
+	o := object{"_created": s.created()}
+	b.position.apply(o)
+	setAttributes(o, "attributes", b.attributeBody)
+	job.nodes.put(id, o)
+	return withID(id, o), nil
+}
+
+func (s *Server) updateNode(c *call, job *jobState, id string) (any, error) {
+	var b nodeBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	o, err := s.upsert(c, job.nodes, "node", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	b.position.apply(o)
+	setAttributes(o, "attributes", b.attributeBody)
+	job.nodes.put(id, o)
+	return withID(id, o), nil
+}
+
+// upsert returns the object to update for an update-or-create endpoint: the existing object,
+// a 404 when onlyIfExists is set, or a new object when id is a valid resource ID.
+func (s *Server) upsert(c *call, t *table, what, id string) (object, error) {
+	if o, ok := t.get(id); ok {
+		return o, nil
+	}
+	if c.onlyIfExists() {
+		return nil, errNotFound(what + " " + id)
+	}
+	if !validID(id) {
+		return nil, errBadRequest("invalid " + what + " id " + id)
+	}
+	return object{"_created": s.created()}, nil
+}
+
+func (s *Server) deleteNode(job *jobState, id string) (any, error) {
+	if !job.nodes.remove(id) {
+		return nil, errNotFound("node " + id)
+	}
+	for _, p := range job.photos.rows {
+		delete(child(p, "associated_locations", false), id)
+	}
+	return nil, nil
+}
+
+// setAttributes applies b to the attribute list stored under key.
+func setAttributes(o object, key string, b attributeBody) {
+	list := fromAny[katapultpro.EntityAttributeList](o[key])
+	if list = b.apply(list); list == nil {
+		delete(o, key)
+		return
+	}
+	o[key] = toAny(list)
+}
+
+// fromAny and toAny convert between stored JSON values and typed values.
+func fromAny[T any](v any) T {
+	var out T
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+func toAny(v any) any {
+	var out any
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type connectionBody struct {
+	NodeID1 *string `json:"node_id_1"`
+	NodeID2 *string `json:"node_id_2"`
+	attributeBody
+}
+
+func (b *connectionBody) apply(job *jobState, o object) error {
+	for _, p := range []struct {
+		key string
+		id  *string
+	}{{"node_id_1", b.NodeID1}, {"node_id_2", b.NodeID2}} {
+		if p.id == nil {
+			continue
+		}
+		if _, ok := job.nodes.get(*p.id); !ok {
+			return errBadRequest("node " + *p.id + " does not exist")
+		}
+		o[p.key] = *p.id
+	}
+	return nil
+}
+
+func (s *Server) createConnection(c *call, job *jobState) (any, error) {
+	var b connectionBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	if b.NodeID1 == nil || b.NodeID2 == nil {
+		return nil, errBadRequest("node_id_1 and node_id_2 are required")
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	o := object{"_created": s.created()}
+	if err := b.apply(job, o); err != nil {
+		return nil, err
+	}
+	setAttributes(o, "attributes", b.attributeBody)
+	id := newID()
+	job.connections.put(id, o)
+	return withID(id, o), nil
+}
+
+func (s *Server) updateConnection(c *call, job *jobState, id string) (any, error) {
+	var b connectionBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	o, err := s.upsert(c, job.connections, "connection", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	if err := b.apply(job, o); err != nil {
+		return nil, err
+	}
+	setAttributes(o, "attributes", b.attributeBody)
+	job.connections.put(id, o)
+	return withID(id, o), nil
+}
+
+type sectionBody struct {
+	MakeMidpoint bool `json:"make_midpoint"`
+	position
+	attributeBody
+}
+
+// sectionView returns a section as the section endpoints report it. Sections store their attributes
+// as multi_attributes (as embedded in connections); the view also exposes them as attributes.
+func sectionView(connID, id string, sec object) object {
+	out := withID(id, sec)
+	out["connection_id"] = connID
+	if attrs, ok := out["multi_attributes"]; ok {
+		out["attributes"] = clone(attrs)
+	}
+	return out
+}
+
+func listSections(connID string, conn object) []object {
+	secs := child(conn, "sections", false)
+	out := make([]object, 0, len(secs))
+	for _, id := range sortedKeys(secs) {
+		if sec, ok := secs[id].(map[string]any); ok {
+			out = append(out, sectionView(connID, id, sec))
+		}
+	}
+	return out
+}
+
+func getSection(connID string, conn object, id string) (object, error) {
+	sec, ok := child(conn, "sections", false)[id].(map[string]any)
+	if !ok {
+		return nil, errNotFound("section " + id)
+	}
+	return sectionView(connID, id, sec), nil
+}
+
+func (s *Server) createSection(c *call, job *jobState, connID string, conn object) (any, error) {
+	var b sectionBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	sec := object{"_created": s.created()}
+	if b.MakeMidpoint {
+		n1, ok1 := job.nodes.get(str(conn["node_id_1"]))
+		n2, ok2 := job.nodes.get(str(conn["node_id_2"]))
+		if !ok1 || !ok2 {
+			return nil, errBadRequest("connection nodes not found for midpoint")
+		}
+		sec["latitude"] = (num(n1["latitude"]) + num(n2["latitude"])) / 2
+		sec["longitude"] = (num(n1["longitude"]) + num(n2["longitude"])) / 2
+	} else if b.Latitude == nil || b.Longitude == nil {
+		return nil, errBadRequest("latitude and longitude are required unless make_midpoint is set")
+	}
+	b.position.apply(sec)
+	setAttributes(sec, "multi_attributes", b.attributeBody)
+	id := newID()
+	child(conn, "sections", true)[id] = sec
+	return sectionView(connID, id, sec), nil
+}
+
+func (s *Server) updateSection(c *call, connID string, conn object, id string) (any, error) {
+	var b sectionBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	secs := child(conn, "sections", true)
+	sec, ok := secs[id].(map[string]any)
+	if !ok {
+		if c.onlyIfExists() {
+			return nil, errNotFound("section " + id)
+		}
+		if !validID(id) {
+			return nil, errBadRequest("invalid section id " + id)
+		}
+		sec = object{"_created": s.created()}
+	}
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	b.position.apply(sec)
+	setAttributes(sec, "multi_attributes", b.attributeBody)
+	secs[id] = sec
+	return sectionView(connID, id, sec), nil
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func num(v any) float64 {
+	f, _ := v.(float64)
+	return f
+}
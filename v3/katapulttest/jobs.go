@@ -0,0 +1,197 @@
+package katapulttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// jobPaths are the values accepted by the paths query parameter of GET /v3/jobs/:job_id.
+var jobPaths = map[string]bool{
+	"name": true, "job_creator": true, "job_owner": true, "project_folder": true, "project_id": true,
+	"status": true, "done": true, "map_styles": true, "metadata": true, "sharing": true,
+}
+
+// AddJob seeds a job and returns it as stored. A random ID is generated when job.ID is empty and
+// the status defaults to active.
+func (s *Server) AddJob(job katapultpro.Job) katapultpro.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := job.ID
+	if id == "" {
+		id = newID()
+	}
+	o := toObject(job)
+	if o["status"] == nil {
+		o["status"] = string(katapultpro.JobStatusActive)
+	}
+	s.putJob(id, o)
+	return fromObject[katapultpro.Job](id, o)
+}
+
+func (s *Server) putJob(id string, o object) {
+	if j, ok := s.jobs[id]; ok {
+		j.job = o
+		return
+	}
+	s.jobs[id] = newJobState(id, o)
+	s.jobOrder = append(s.jobOrder, id)
+}
+
+// jobFields holds the job fields of a create or update request. Metadata and sharing are kept raw
+// so nulls can delete keys.
+type jobFields struct {
+	Name      *string                    `json:"name"`
+	Model     *string                    `json:"model"`
+	MapStyles *string                    `json:"map_styles"`
+	Metadata  map[string]json.RawMessage `json:"metadata"`
+	Sharing   map[string]json.RawMessage `json:"sharing"`
+}
+
+func (f *jobFields) apply(o object) {
+	if f.Name != nil {
+		o["name"] = *f.Name
+	}
+	if f.Model != nil {
+		o["model"] = *f.Model
+	}
+	if f.MapStyles != nil {
+		o["map_styles"] = *f.MapStyles
+	}
+	if f.Metadata != nil {
+		o["metadata"] = mergeFlat(child(o, "metadata", false), f.Metadata)
+	}
+	if f.Sharing != nil {
+		o["sharing"] = mergeFlat(child(o, "sharing", false), f.Sharing)
+	}
+}
+
+// listJobs returns job summaries. The API's list entries "do not contain the full job data" and
+// which fields they keep is not documented, so the fake returns only the ID and name; clients must
+// fetch anything else with GetJob.
+func (s *Server) listJobs(c *call) (any, error) {
+	archived := c.query.Get("includeArchived") == "true"
+	filter, err := parseMetadataFilter(c.query.Get("metadataFilter"))
+	if err != nil {
+		return nil, err
+	}
+	out := []object{}
+	for _, id := range s.jobOrder {
+		o := s.jobs[id].job
+		if !archived && o["status"] == string(katapultpro.JobStatusArchived) {
+			continue
+		}
+		if !matchMetadata(child(o, "metadata", false), filter) {
+			continue
+		}
+		entry := object{"id": id}
+		if name, ok := o["name"]; ok {
+			entry["name"] = name
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
+// parseMetadataFilter parses "attr:value,attr2:value2".
+func parseMetadataFilter(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	out := map[string]string{}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, ":")
+		if !ok || k == "" {
+			return nil, errBadRequest("invalid metadataFilter " + s)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+func matchMetadata(md object, filter map[string]string) bool {
+	for k, want := range filter {
+		v, ok := md[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) getJob(c *call, job *jobState) (any, error) {
+	paths := c.query.Get("paths")
+	if paths == "" {
+		return withID(job.id, job.job), nil
+	}
+	out := object{}
+	for _, p := range strings.Split(paths, ",") {
+		segs := strings.Split(p, "/")
+		if !jobPaths[segs[0]] || (len(segs) > 1 && !s.nestedJobPaths) {
+			return nil, errBadRequest("invalid path " + p)
+		}
+		// Nested paths (WithNestedJobPaths) select one value inside a field, returned at the same
+		// nesting.
+		var v any = job.job
+		for _, seg := range segs {
+			o, _ := v.(object)
+			if v = o[seg]; v == nil {
+				break
+			}
+		}
+		if v == nil {
+			continue
+		}
+		dst := out
+		for _, seg := range segs[:len(segs)-1] {
+			dst = child(dst, seg, true)
+		}
+		dst[segs[len(segs)-1]] = clone(v)
+	}
+	return out, nil
+}
+
+func (s *Server) createJob(c *call) (any, error) {
+	var f jobFields
+	if err := c.decode(&f); err != nil {
+		return nil, err
+	}
+	if f.Name == nil || *f.Name == "" {
+		return nil, errBadRequest("name is required")
+	}
+	o := object{"status": string(katapultpro.JobStatusActive)}
+	f.apply(o)
+	id := newID()
+	s.putJob(id, o)
+	return withID(id, o), nil
+}
+
+func (s *Server) updateJob(c *call, job *jobState) (any, error) {
+	var f jobFields
+	if err := c.decode(&f); err != nil {
+		return nil, err
+	}
+	f.apply(job.job)
+	return withID(job.id, job.job), nil
+}
+
+func (s *Server) jobStatus(c *call, job *jobState) (any, error) {
+	switch c.method {
+	case http.MethodGet:
+		return object{"status": job.job["status"]}, nil
+	case http.MethodPost:
+		var req katapultpro.UpdateJobStatusRequest
+		if err := c.decode(&req); err != nil {
+			return nil, err
+		}
+		if !req.Status.IsValid() {
+			return nil, errBadRequest("invalid status " + string(req.Status))
+		}
+		job.job["status"] = string(req.Status)
+		return object{"status": job.job["status"]}, nil
+	}
+	return nil, errMethod(c)
+}
@@ -0,0 +1,152 @@
+package katapulttest
+
+import (
+	"encoding/json"
+	"mime"
+	"strconv"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// elementFields are the photo element fields stored on the element object itself; every other
+// key on a stored element is an attribute.
+var elementFields = map[string]bool{
+	"pixel_selection": true, "manual_height": true, "_measured_height": true, "_routine_instance_id": true,
+	"_trace": true, "pole_top_extension": true, "parent_id": true,
+}
+
+// anchorKey is the photofirst_data key holding calibration anchors.
+const anchorKey = "anchor_calibration"
+
+// AddPhoto seeds a photo in a job and returns it as stored. A random ID is generated when photo.ID is empty.
+// Elements and calibration anchors can be seeded through photo.PhotofirstData.
+// It panics if the job does not exist.
+func (s *Server) AddPhoto(jobID string, photo katapultpro.Photo) katapultpro.Photo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := photo.ID
+	if id == "" {
+		id = newID()
+	}
+	o := toObject(photo)
+	s.mustJob(jobID).photos.put(id, o)
+	return fromObject[katapultpro.Photo](id, o)
+}
+
+// target is the item an upload is associated to; the zero value means no association.
+type target struct {
+	nodeID       string
+	connectionID string
+	sectionID    string
+}
+
+func (s *Server) uploadAndAssociate(c *call, job *jobState, to target) (any, error) {
+	if mt, _, _ := mime.ParseMediaType(c.contentType); mt != "image/jpeg" {
+		return nil, errBadRequest("photo must be an image/jpeg file")
+	}
+	if len(c.body) == 0 {
+		return nil, errBadRequest("photo body is empty")
+	}
+	now := s.now().UnixMilli()
+	id := newID()
+	o := object{
+		"filename":      id + ".jpg",
+		"name_date":     id + ".jpg|" + strconv.FormatInt(now, 10),
+		"original_size": len(c.body),
+		"upload_date":   now,
+		"status":        "upload_complete",
+	}
+	job.photos.put(id, o)
+	job.images[id] = c.body
+	if to != (target{}) {
+		value := any(true)
+		if c.query.Get("association_value") == "main" {
+			value = "main"
+		}
+		if err := associate(job, id, to, value); err != nil {
+			return nil, err
+		}
+	}
+	return withID(id, o), nil
+}
+
+func (s *Server) associate(c *call, job *jobState, photoID string) (any, error) {
+	var req struct {
+		NodeID           string          `json:"node_id"`
+		SectionID        string          `json:"section_id"`
+		ConnectionID     string          `json:"connection_id"`
+		AssociationValue json.RawMessage `json:"association_value"`
+	}
+	if err := c.decode(&req); err != nil {
+		return nil, err
+	}
+	var value any
+	switch string(req.AssociationValue) {
+	case `"main"`:
+		value = "main"
+	case `true`:
+		value = true
+	case ``, `null`:
+	default:
+		return nil, errBadRequest("association_value must be \"main\", true or null")
+	}
+	to := target{nodeID: req.NodeID, connectionID: req.ConnectionID, sectionID: req.SectionID}
+	switch {
+	case to.nodeID != "" && to.sectionID == "":
+	case to.nodeID == "" && to.sectionID != "" && to.connectionID != "":
+	default:
+		return nil, errBadRequest("provide node_id, or section_id and connection_id")
+	}
+	return nil, associate(job, photoID, to, value)
+}
+
+// associate sets (or, with a nil value, removes) the association between a photo and an item.
+// A "main" association demotes the item's previous main photo.
+func associate(job *jobState, photoID string, to target, value any) error {
+	var item object
+	kind, itemID := "node", to.nodeID
+	if to.nodeID != "" {
+		n, ok := job.nodes.get(to.nodeID)
+		if !ok {
+			// Removing an association left behind by a deleted node only clears the photo's side.
+			p, _ := job.photos.get(photoID)
+			locs := child(p, "associated_locations", false)
+			if _, stale := locs[to.nodeID]; value == nil && stale {
+				delete(locs, to.nodeID)
+				return nil
+			}
+			return errNotFound("node " + to.nodeID)
+		}
+		item = n
+	} else {
+		conn, ok := job.connections.get(to.connectionID)
+		if !ok {
+			return errNotFound("connection " + to.connectionID)
+		}
+		sec, ok := child(conn, "sections", false)[to.sectionID].(map[string]any)
+		if !ok {
+			return errNotFound("section " + to.sectionID)
+		}
+		item, kind, itemID = sec, "section", to.sectionID
+	}
+	photo, _ := job.photos.get(photoID)
+	photos := child(item, "photos", true)
+	if value == nil {
+		delete(photos, photoID)
+		if len(photos) == 0 {
+			delete(item, "photos")
+		}
+		delete(child(photo, "associated_locations", false), itemID)
+		return nil
+	}
+	if value == "main" {
+		for id, p := range photos {
+			if m, ok := p.(map[string]any); ok && m["association"] == "main" {
+				photos[id] = object{"association": true}
+			}
+		}
+	}
+	photos[photoID] = object{"association": value}
+	child(photo, "associated_locations", true)[itemID] = kind
+	return nil
+}
@@ -0,0 +1,211 @@
+package katapulttest
+
+import (
+	"net/http"
+)
+
+// route dispatches a request to its handler. The caller holds s.mu.
+func (s *Server) route(c *call) (any, error) {
+	seg := splitPath(c.path)
+	if len(seg) < 2 || seg[0] != "v3" || seg[1] != "jobs" {
+		return nil, errNotFound("route " + c.path)
+	}
+	seg = seg[2:]
+	if len(seg) == 0 {
+		switch c.method {
+		case http.MethodGet:
+			return s.listJobs(c)
+		case http.MethodPost:
+			return s.createJob(c)
+		}
+		return nil, errMethod(c)
+	}
+	job, ok := s.jobs[seg[0]]
+	if !ok {
+		return nil, errNotFound("job " + seg[0])
+	}
+	seg = seg[1:]
+	if len(seg) == 0 {
+		switch c.method {
+		case http.MethodGet:
+			return s.getJob(c, job)
+		case http.MethodPost:
+			return s.updateJob(c, job)
+		}
+		return nil, errMethod(c)
+	}
+	switch seg[0] {
+	case "status":
+		if len(seg) == 1 {
+			return s.jobStatus(c, job)
+		}
+	case "nodes":
+		return s.routeNodes(c, job, seg[1:])
+	case "connections":
+		return s.routeConnections(c, job, seg[1:])
+	case "sections":
+		return s.routeJobSections(c, job, seg[1:])
+	case "photos":
+		return s.routePhotos(c, job, seg[1:])
+	case "traces":
+		return s.routeTraces(c, job, seg[1:])
+	}
+	return nil, errNotFound("route " + c.path)
+}
+
+func (s *Server) routeNodes(c *call, job *jobState, seg []string) (any, error) {
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		return job.nodes.list(), nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		return s.createNode(c, job)
+	case len(seg) == 1 && c.method == http.MethodGet:
+		return getRow(job.nodes, "node", seg[0])
+	case len(seg) == 1 && c.method == http.MethodPost:
+		return s.updateNode(c, job, seg[0])
+	case len(seg) == 1 && c.method == http.MethodDelete:
+		return s.deleteNode(job, seg[0])
+	case len(seg) == 2 && seg[1] == "photos" && c.method == http.MethodPost:
+		if _, ok := job.nodes.get(seg[0]); !ok {
+			return nil, errNotFound("node " + seg[0])
+		}
+		return s.uploadAndAssociate(c, job, target{nodeID: seg[0]})
+	}
+	return nil, errMethod(c)
+}
+
+func (s *Server) routeConnections(c *call, job *jobState, seg []string) (any, error) {
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		return job.connections.list(), nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		return s.createConnection(c, job)
+	case len(seg) == 1 && c.method == http.MethodGet:
+		return getRow(job.connections, "connection", seg[0])
+	case len(seg) == 1 && c.method == http.MethodPost:
+		return s.updateConnection(c, job, seg[0])
+	case len(seg) == 1 && c.method == http.MethodDelete:
+		if !job.connections.remove(seg[0]) {
+			return nil, errNotFound("connection " + seg[0])
+		}
+		return nil, nil
+	case len(seg) >= 2 && seg[1] == "sections":
+		conn, ok := job.connections.get(seg[0])
+		if !ok {
+			return nil, errNotFound("connection " + seg[0])
+		}
+		return s.routeSections(c, job, seg[0], conn, seg[2:])
+	}
+	return nil, errMethod(c)
+}
+
+func (s *Server) routeSections(c *call, job *jobState, connID string, conn object, seg []string) (any, error) {
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		return listSections(connID, conn), nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		return s.createSection(c, job, connID, conn)
+	case len(seg) == 1 && c.method == http.MethodGet:
+		return getSection(connID, conn, seg[0])
+	case len(seg) == 1 && c.method == http.MethodPost:
+		return s.updateSection(c, connID, conn, seg[0])
+	case len(seg) == 1 && c.method == http.MethodDelete:
+		secs := child(conn, "sections", false)
+		if _, ok := secs[seg[0]]; !ok {
+			return nil, errNotFound("section " + seg[0])
+		}
+		delete(secs, seg[0])
+		return nil, nil
+	case len(seg) == 2 && seg[1] == "photos" && c.method == http.MethodPost:
+		if _, err := getSection(connID, conn, seg[0]); err != nil {
+			return nil, err
+		}
+		return s.uploadAndAssociate(c, job, target{connectionID: connID, sectionID: seg[0]})
+	}
+	return nil, errMethod(c)
+}
+
+func (s *Server) routeJobSections(c *call, job *jobState, seg []string) (any, error) {
+	if c.method != http.MethodGet {
+		return nil, errMethod(c)
+	}
+	switch len(seg) {
+	case 0:
+		var out []object
+		for _, connID := range job.connections.order {
+			out = append(out, listSections(connID, job.connections.rows[connID])...)
+		}
+		if out == nil {
+			out = []object{}
+		}
+		return out, nil
+	case 1:
+		for _, connID := range job.connections.order {
+			if sec, err := getSection(connID, job.connections.rows[connID], seg[0]); err == nil {
+				return sec, nil
+			}
+		}
+		return nil, errNotFound("section " + seg[0])
+	}
+	return nil, errMethod(c)
+}
+
+func (s *Server) routePhotos(c *call, job *jobState, seg []string) (any, error) {
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		return job.photos.list(), nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		return s.uploadAndAssociate(c, job, target{})
+	case len(seg) == 1 && c.method == http.MethodGet:
+		return getRow(job.photos, "photo", seg[0])
+	}
+	if len(seg) < 2 {
+		return nil, errMethod(c)
+	}
+	photo, ok := job.photos.get(seg[0])
+	if !ok {
+		return nil, errNotFound("photo " + seg[0])
+	}
+	switch seg[1] {
+	case "associate":
+		if len(seg) == 2 && c.method == http.MethodPost {
+			return s.associate(c, job, seg[0])
+		}
+	case "photo_elements":
+		return s.routeElements(c, job, seg[0], photo, seg[2:])
+	case "calibration_anchors":
+		return s.routeAnchors(c, photo, seg[2:])
+	}
+	return nil, errMethod(c)
+}
+
+func (s *Server) routeTraces(c *call, job *jobState, seg []string) (any, error) {
+	switch {
+	case len(seg) == 0 && c.method == http.MethodGet:
+		return job.traces.list(), nil
+	case len(seg) == 0 && c.method == http.MethodPost:
+		return s.createTrace(c, job)
+	case len(seg) == 1 && c.method == http.MethodGet:
+		return getRow(job.traces, "trace", seg[0])
+	case len(seg) == 1 && c.method == http.MethodPost:
+		return s.updateTrace(c, job, seg[0])
+	case len(seg) == 1 && c.method == http.MethodDelete:
+		if !job.traces.remove(seg[0]) {
+			return nil, errNotFound("trace " + seg[0])
+		}
+		return nil, nil
+	}
+	return nil, errMethod(c)
+}
+
+func getRow(t *table, what, id string) (any, error) {
+	o, ok := t.get(id)
+	if !ok {
+		return nil, errNotFound(what + " " + id)
+	}
+	return withID(id, o), nil
+}
+
+func errMethod(c *call) error {
+	return &apiError{status: http.StatusNotFound, typ: "not_found", message: "no route for " + c.method + " " + c.path}
+}
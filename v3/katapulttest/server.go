@@ -0,0 +1,329 @@
+// Package katapulttest provides an in-memory, stateful fake of the Katapult Pro v3 API for tests.
+//
+// The fake implements every route the SDK covers (jobs, nodes, connections, sections, photos,
+// photo elements, calibration anchors, and traces) with the real response envelope, token
+// bucket accounting in meta, onlyIfExists semantics, the add/remove/attributes merge rules,
+// and resource ID validation. Faults (error statuses such as 429 or 5xx, and latency) can be
+// injected per route.
+//
+//	srv := katapulttest.NewServer()
+//	defer srv.Close()
+//	job := srv.AddJob(katapultpro.Job{Name: "Test job"})
+//	client := srv.Client()
+//	node, err := client.Job(job.ID).Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Latitude: 1, Longitude: 2})
+package katapulttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Token bucket defaults, matching the API documentation.
+const (
+	DefaultTokenCapacity = 10000
+	TokenRefillInterval  = time.Minute
+	GetTokenCost         = 1
+	WriteTokenCost       = 10 // POST and DELETE
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAPIKey requires every request to carry ?api_key=key; others get 401. By default any key is accepted.
+func WithAPIKey(key string) Option {
+	return func(s *Server) { s.apiKey = key }
+}
+
+// WithTokenCapacity sets the size of the token bucket. Requests that cost more than the remaining
+// tokens fail with 429 until the bucket refills.
+func WithTokenCapacity(n int64) Option {
+	return func(s *Server) {
+		s.capacity = n
+		s.tokens = n
+	}
+}
+
+// WithClock sets the time source used for token refills and timestamps.
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		if now != nil {
+			s.now = now
+		}
+	}
+}
+
+// WithNestedJobPaths makes GetJob accept paths inside a job field, such as metadata/region, and
+// return only that value at the same nesting. Whether the real API supports nested paths is
+// unverified, so by default the fake rejects them with 400 like any other unknown path.
+func WithNestedJobPaths() Option {
+	return func(s *Server) { s.nestedJobPaths = true }
+}
+
+// Fault makes matching requests fail or slow down. Method and PathPrefix narrow the match
+// (empty matches everything). Status, if non-zero, is returned instead of handling the request;
+// Latency is slept before responding. Count limits how many requests the fault affects (0 = unlimited).
+type Fault struct {
+	Method     string
+	PathPrefix string
+	Status     int
+	Latency    time.Duration
+	Count      int
+}
+
+// Request is a request received by the Server, recorded for assertions.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+}
+
+// Server is a fake Katapult Pro v3 API. It implements http.Handler; NewServer also starts it on a
+// local httptest server. A Server is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the running server (e.g. "http://127.0.0.1:1234"); pass it to katapultpro.WithBaseURL.
+	URL string
+
+	srv            *httptest.Server
+	now            func() time.Time
+	nestedJobPaths bool
+
+	mu         sync.Mutex
+	apiKey     string
+	capacity   int64
+	tokens     int64
+	lastRefill time.Time
+	faults     []*Fault
+	requests   []Request
+	jobs       map[string]*jobState
+	jobOrder   []string
+}
+
+// NewServer starts a fake API server. Call Close when done.
+func NewServer(opts ...Option) *Server {
+	s := New(opts...)
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// New returns a fake API handler without starting a server, for mounting in your own mux or httptest.Server.
+func New(opts ...Option) *Server {
+	s := &Server{
+		now:      time.Now,
+		capacity: DefaultTokenCapacity,
+		tokens:   DefaultTokenCapacity,
+		jobs:     map[string]*jobState{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.lastRefill = s.now()
+	return s
+}
+
+// Close shuts down the server started by NewServer.
+func (s *Server) Close() {
+	if s.srv != nil {
+		s.srv.Close()
+	}
+}
+
+// Client returns a katapultpro.Client pointed at the server. Extra options are applied after the base URL.
+func (s *Server) Client(opts ...katapultpro.ClientOption) *katapultpro.Client {
+	key := s.apiKey
+	if key == "" {
+		key = "katapulttest"
+	}
+	c, _ := katapultpro.NewClient(key, append([]katapultpro.ClientOption{katapultpro.WithBaseURL(s.URL)}, opts...)...)
+	return c
+}
+
+// Inject adds a fault. Faults are checked in the order they were added; the first match applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Tokens returns the number of tokens left in the bucket.
+func (s *Server) Tokens() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refill()
+	return s.tokens
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Body: body})
+	fault := s.matchFault(r)
+	s.mu.Unlock()
+
+	if fault != nil && fault.Latency > 0 {
+		select {
+		case <-time.After(fault.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refill()
+	if fault != nil && fault.Status != 0 {
+		s.writeError(w, &apiError{status: fault.Status, typ: errorType(fault.Status), message: "injected fault"})
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, imagePathPrefix) {
+		s.serveImage(w, r)
+		return
+	}
+	if s.apiKey != "" && r.URL.Query().Get("api_key") != s.apiKey {
+		s.writeError(w, &apiError{status: http.StatusUnauthorized, typ: "unauthorized", message: "invalid api key"})
+		return
+	}
+	cost := int64(GetTokenCost)
+	if r.Method != http.MethodGet {
+		cost = WriteTokenCost
+	}
+	if s.tokens < cost {
+		s.writeError(w, &apiError{status: http.StatusTooManyRequests, typ: "rate_limited", message: "token bucket depleted"})
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, "/v2/") {
+		s.photoURL(w, r)
+		return
+	}
+	data, err := s.route(&call{method: r.Method, path: r.URL.Path, query: r.URL.Query(), body: body, contentType: r.Header.Get("Content-Type")})
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.tokens -= cost
+	s.write(w, http.StatusOK, map[string]any{"status": "success", "data": data, "meta": s.meta()})
+}
+
+func (s *Server) matchFault(r *http.Request) *Fault {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.PathPrefix != "" && !strings.HasPrefix(r.URL.Path, f.PathPrefix) {
+			continue
+		}
+		out := *f
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return &out
+	}
+	return nil
+}
+
+func (s *Server) refill() {
+	if s.now().Sub(s.lastRefill) >= TokenRefillInterval {
+		s.tokens = s.capacity
+		s.lastRefill = s.now()
+	}
+}
+
+func (s *Server) meta() katapultpro.Meta {
+	return katapultpro.Meta{TokenCount: s.tokens, LastRefillTime: s.lastRefill.UnixMilli()}
+}
+
+func (s *Server) write(w http.ResponseWriter, status int, v any) {
+	var buf bytes.Buffer
+	_ = json.NewEncoder(&buf).Encode(v)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	e, ok := err.(*apiError)
+	if !ok {
+		e = &apiError{status: http.StatusInternalServerError, typ: "internal_error", message: err.Error()}
+	}
+	s.write(w, e.status, map[string]any{"status": "error", "message": e.message, "type": e.typ, "meta": s.meta()})
+}
+
+// apiError is an error response with an HTTP status and API error type.
+type apiError struct {
+	status  int
+	typ     string
+	message string
+}
+
+func (e *apiError) Error() string { return e.message }
+
+func errNotFound(what string) error {
+	return &apiError{status: http.StatusNotFound, typ: "not_found", message: what + " not found"}
+}
+
+func errBadRequest(msg string) error {
+	return &apiError{status: http.StatusBadRequest, typ: "invalid_request", message: msg}
+}
+
+func errorType(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return "rate_limited"
+	case status == http.StatusNotFound:
+		return "not_found"
+	case status >= 500:
+		return "internal_error"
+	default:
+		return "invalid_request"
+	}
+}
+
+// call is one API request after routing-independent checks.
+type call struct {
+	method      string
+	path        string
+	query       url.Values
+	body        []byte
+	contentType string
+}
+
+func (c *call) onlyIfExists() bool { return c.query.Get("onlyIfExists") == "true" }
+
+// decode unmarshals the JSON body into v, treating an empty body as {}.
+func (c *call) decode(v any) error {
+	if len(bytes.TrimSpace(c.body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(c.body, v); err != nil {
+		return errBadRequest("invalid request body: " + err.Error())
+	}
+	return nil
+}
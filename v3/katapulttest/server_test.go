@@ -0,0 +1,184 @@
+package katapulttest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+)
+
+func TestServer_NodesAttributesAndMeta(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Test job"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	node, err := client.Job(job.ID).Nodes().Create(ctx, &katapultpro.CreateNodeRequest{
+		Latitude: 40, Longitude: -111,
+		Attributes: katapultpro.EntityAttributeList{"scid": {"i1": "001"}, "note": {"i2": "a"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.LastMeta == nil || client.LastMeta.TokenCount != katapulttest.DefaultTokenCapacity-katapulttest.WriteTokenCost {
+		t.Errorf("meta after create: %+v", client.LastMeta)
+	}
+
+	updated, err := client.Job(job.ID).Nodes().Update(ctx, node.ID, &katapultpro.UpdateNodeRequest{
+		RemoveAttributes: []string{"note"},
+		Attributes:       katapultpro.EntityAttributeList{"scid": {"i1": "002"}},
+		AddAttributes:    map[string]interface{}{"pole_tag": "T1"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Attributes["scid"]["i1"] != "002" || updated.Attributes["note"] != nil || len(updated.Attributes["pole_tag"]) != 1 {
+		t.Errorf("attributes after update: %+v", updated.Attributes)
+	}
+
+	_, err = client.Job(job.ID).Nodes().Update(ctx, "-missingnode00000000", &katapultpro.UpdateNodeRequest{Latitude: 1}, &katapultpro.UpdateNodeOptions{OnlyIfExists: true})
+	var apiErr *katapultpro.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("onlyIfExists on missing node: %v", err)
+	}
+	_, err = client.Job(job.ID).Nodes().Update(ctx, "bad id", &katapultpro.UpdateNodeRequest{Latitude: 1}, nil)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid node id: %v", err)
+	}
+	if nodes, _ := client.Job(job.ID).Nodes().List(ctx); len(nodes) != 1 {
+		t.Errorf("got %d nodes", len(nodes))
+	}
+}
+
+func TestServer_PhotosElementsAnchors(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Photos"})
+	node := srv.AddNode(job.ID, katapultpro.Node{Latitude: 1, Longitude: 2})
+	client := srv.Client()
+	ctx := context.Background()
+	scope := client.Job(job.ID)
+
+	photo, err := scope.Nodes().Node(node.ID).UploadPhoto(ctx, bytes.NewReader([]byte("jpeg")), &katapultpro.UploadNodePhotoOptions{AssociationValue: katapultpro.PhotoAssociationQueryMain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := scope.Nodes().Get(ctx, node.ID)
+	if got.Photos[photo.ID].Association != "main" {
+		t.Errorf("node photos: %+v", got.Photos)
+	}
+
+	trace, _ := scope.Traces().Create(ctx, &katapultpro.CreateTraceRequest{TraceType: "cable", Attributes: map[string]interface{}{"company": "Acme"}})
+	el, err := scope.Photos().Photo(photo.ID).Elements().Create(ctx, &katapultpro.CreatePhotoElementRequest{
+		ElementType: "wire", ManualHeight: "16-6", TraceID: trace.ID, Attributes: map[string]interface{}{"note": "x"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if el.Context == nil || el.Context.Path != "wire/"+el.ID || el.Attributes["note"] != "x" {
+		t.Errorf("element: %+v", el)
+	}
+	trace, _ = scope.Traces().Get(ctx, trace.ID)
+	if !trace.Items[photo.ID]["wire"][el.ID] || trace.Company != "Acme" {
+		t.Errorf("trace: %+v", trace)
+	}
+
+	// Updating an unknown element creates one with a random ID.
+	other, err := scope.Photos().Photo(photo.ID).Elements().Update(ctx, "-unknownelement00000", &katapultpro.UpdatePhotoElementRequest{ElementType: "pole_top"}, nil)
+	if err != nil || other.ID == "-unknownelement00000" {
+		t.Errorf("upsert element: %+v, %v", other, err)
+	}
+
+	req, err := katapultpro.NewPoleTopElement(true, katapultpro.ElementOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	top, err := scope.Photos().Photo(photo.ID).Elements().Create(ctx, req)
+	if err != nil || !top.PoleTopExtension || top.Type() != katapultpro.ElementTypePoleTop {
+		t.Errorf("pole top: %+v, %v", top, err)
+	}
+
+	srv.AddPhoto(job.ID, katapultpro.Photo{ID: "calibratedphoto00000", StickAlign: &katapultpro.StickAlign{A: 1}})
+	if _, err := scope.Photos().Photo("calibratedphoto00000").Anchors().Create(ctx, &katapultpro.CreatePhotoCalibrationAnchorRequest{PixelSelection: katapultpro.PixelSelection{PercentX: 10, PercentY: 20}, Height: 20}); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := scope.Photos().Get(ctx, "calibratedphoto00000")
+	if p.StickAlign != nil || len(p.PhotofirstData.AnchorCalibration) != 1 {
+		t.Errorf("photo after anchor: %+v", p)
+	}
+
+	if _, err := scope.Photos().Upload(ctx, bytes.NewReader(nil)); err == nil {
+		t.Error("expected error for empty upload")
+	}
+}
+
+func TestServer_JobsMetadataAndPaths(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	job, err := client.CreateJob(ctx, &katapultpro.CreateJobRequest{Name: "A", Model: "m", Metadata: map[string]interface{}{"region": "west", "tmp": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.AddJob(katapultpro.Job{Name: "B", Metadata: map[string]interface{}{"region": "east"}})
+	if _, err := client.UpdateJob(ctx, job.ID, &katapultpro.UpdateJobRequest{Metadata: map[string]interface{}{"tmp": nil}}); err != nil {
+		t.Fatal(err)
+	}
+	jobs, _ := client.ListJobs(ctx, &katapultpro.ListJobsOptions{MetadataFilter: "region:west"})
+	if len(jobs) != 1 || jobs[0].ID != job.ID || jobs[0].Name == "" || jobs[0].Metadata != nil || jobs[0].Status != "" {
+		t.Errorf("filtered jobs, want summaries: %+v", jobs)
+	}
+
+	if err := client.Job(job.ID).SetStatus(ctx, katapultpro.JobStatusArchived); err != nil {
+		t.Fatal(err)
+	}
+	if jobs, _ := client.ListJobs(ctx, nil); len(jobs) != 1 {
+		t.Errorf("archived job listed: %+v", jobs)
+	}
+	partial, _ := client.GetJob(ctx, job.ID, &katapultpro.GetJobOptions{Paths: []katapultpro.JobPath{katapultpro.JobPathName}})
+	if partial.Name != "A" || partial.Status != "" {
+		t.Errorf("partial job: %+v", partial)
+	}
+}
+
+func TestServer_FaultsAndRateLimit(t *testing.T) {
+	now := time.Unix(0, 0)
+	srv := katapulttest.NewServer(katapulttest.WithTokenCapacity(12), katapulttest.WithClock(func() time.Time { return now }))
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Faults"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	srv.Inject(katapulttest.Fault{Method: http.MethodGet, PathPrefix: "/v3/jobs/" + job.ID + "/nodes", Status: http.StatusServiceUnavailable, Count: 1})
+	var apiErr *katapultpro.APIError
+	if _, err := client.Job(job.ID).Nodes().List(ctx); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("injected fault: %v", err)
+	}
+	if _, err := client.Job(job.ID).Nodes().List(ctx); err != nil {
+		t.Errorf("fault should be spent: %v", err)
+	}
+
+	// 12 tokens: one GET above, one POST (10) leaves 1; a second POST is rate limited.
+	if _, err := client.Job(job.ID).Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Latitude: 1, Longitude: 1}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Job(job.ID).Nodes().Create(ctx, &katapultpro.CreateNodeRequest{Latitude: 1, Longitude: 1})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("expected 429, got %v", err)
+	}
+	now = now.Add(katapulttest.TokenRefillInterval)
+	if srv.Tokens() != 12 {
+		t.Errorf("tokens after refill: %d", srv.Tokens())
+	}
+	if n := len(srv.Requests()); n != 4 {
+		t.Errorf("recorded %d requests", n)
+	}
+}
@@ -0,0 +1,120 @@
+package katapulttest
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// object is a stored API object. Entities are kept as generic JSON objects so that fields the
+// SDK types do not model survive round trips, as they do against the real API.
+type object = map[string]any
+
+// table holds objects keyed by ID in insertion order.
+type table struct {
+	rows  map[string]object
+	order []string
+}
+
+func newTable() *table { return &table{rows: map[string]object{}} }
+
+func (t *table) get(id string) (object, bool) {
+	o, ok := t.rows[id]
+	return o, ok
+}
+
+func (t *table) put(id string, o object) {
+	if _, ok := t.rows[id]; !ok {
+		t.order = append(t.order, id)
+	}
+	t.rows[id] = o
+}
+
+func (t *table) remove(id string) bool {
+	if _, ok := t.rows[id]; !ok {
+		return false
+	}
+	delete(t.rows, id)
+	for i, v := range t.order {
+		if v == id {
+			t.order = append(t.order[:i:i], t.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns copies of every object with its ID set.
+func (t *table) list() []object {
+	out := make([]object, 0, len(t.order))
+	for _, id := range t.order {
+		out = append(out, withID(id, t.rows[id]))
+	}
+	return out
+}
+
+// jobState is everything stored for one job.
+type jobState struct {
+	id          string
+	job         object
+	nodes       *table
+	connections *table
+	photos      *table
+	traces      *table
+	images      map[string][]byte // Photo ID to image bytes, served for every size
+}
+
+func newJobState(id string, job object) *jobState {
+	return &jobState{id: id, job: job, nodes: newTable(), connections: newTable(), photos: newTable(), traces: newTable(), images: map[string][]byte{}}
+}
+
+// withID returns a deep copy of o with "id" set.
+func withID(id string, o object) object {
+	out := clone(o)
+	if out == nil {
+		out = object{}
+	}
+	out["id"] = id
+	return out
+}
+
+// toObject converts an SDK value to a stored object, dropping its "id".
+func toObject(v any) object {
+	b, _ := json.Marshal(v)
+	var o object
+	_ = json.Unmarshal(b, &o)
+	if o == nil {
+		o = object{}
+	}
+	delete(o, "id")
+	return o
+}
+
+// fromObject converts a stored object to an SDK value.
+func fromObject[T any](id string, o object) T {
+	var out T
+	b, _ := json.Marshal(withID(id, o))
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+// child returns o[key] as an object, creating it when create is set.
+func child(o object, key string, create bool) object {
+	if m, ok := o[key].(map[string]any); ok {
+		return m
+	}
+	if !create {
+		return nil
+	}
+	m := object{}
+	o[key] = m
+	return m
+}
+
+// splitPath splits a URL path into segments, ignoring leading and trailing slashes.
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
@@ -0,0 +1,88 @@
+package katapulttest
+
+import (
+	"encoding/json"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// traceFields are reserved keys on a stored trace; trace attributes live at the object root beside them.
+var traceFields = map[string]bool{"id": true, "_trace_type": true, "items": true}
+
+// AddTrace seeds a trace in a job and returns it as stored. A random ID is generated when trace.ID is empty.
+// trace.Attributes are stored at the trace root, as the API does. It panics if the job does not exist.
+func (s *Server) AddTrace(jobID string, trace katapultpro.Trace) katapultpro.Trace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := trace.ID
+	if id == "" {
+		id = newID()
+	}
+	attrs := trace.Attributes
+	trace.Attributes = nil
+	o := toObject(trace)
+	for k, v := range attrs {
+		if !traceFields[k] {
+			o[k] = toAny(v)
+		}
+	}
+	s.mustJob(jobID).traces.put(id, o)
+	return fromObject[katapultpro.Trace](id, o)
+}
+
+type traceBody struct {
+	TraceType  *string                    `json:"trace_type"`
+	Attributes map[string]json.RawMessage `json:"attributes"`
+}
+
+func (b *traceBody) apply(o object) error {
+	for k := range b.Attributes {
+		if traceFields[k] {
+			return errBadRequest("attribute " + k + " is reserved")
+		}
+	}
+	if b.TraceType != nil {
+		o["_trace_type"] = *b.TraceType
+	}
+	mergeFlat(o, b.Attributes)
+	return nil
+}
+
+func (s *Server) createTrace(c *call, job *jobState) (any, error) {
+	var b traceBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	if b.TraceType == nil || *b.TraceType == "" {
+		return nil, errBadRequest("trace_type is required")
+	}
+	o := object{}
+	if err := b.apply(o); err != nil {
+		return nil, err
+	}
+	id := newID()
+	job.traces.put(id, o)
+	return withID(id, o), nil
+}
+
+func (s *Server) updateTrace(c *call, job *jobState, id string) (any, error) {
+	var b traceBody
+	if err := c.decode(&b); err != nil {
+		return nil, err
+	}
+	o, ok := job.traces.get(id)
+	if !ok {
+		if c.onlyIfExists() {
+			return nil, errNotFound("trace " + id)
+		}
+		if !validID(id) {
+			return nil, errBadRequest("invalid trace id " + id)
+		}
+		o = object{}
+	}
+	if err := b.apply(o); err != nil {
+		return nil, err
+	}
+	job.traces.put(id, o)
+	return withID(id, o), nil
+}
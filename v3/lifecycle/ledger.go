@@ -0,0 +1,116 @@
+package lifecycle
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Transition is one recorded status change.
+type Transition struct {
+	ID       string                `json:"id"`
+	Time     time.Time             `json:"time"`
+	JobID    string                `json:"job_id"`
+	JobName  string                `json:"job_name,omitempty"`
+	From     katapultpro.JobStatus `json:"from"`
+	To       katapultpro.JobStatus `json:"to"`
+	Rule     string                `json:"rule"`               // The rule that chose it, or RuleUndo
+	Snapshot string                `json:"snapshot,omitempty"` // File the job was exported to before archiving
+	Undoes   string                `json:"undoes,omitempty"`   // For undos, the ID of the transition reverted
+}
+
+// RuleUndo is the Rule of transitions made by Manager.Undo.
+const RuleUndo = "undo"
+
+// Ledger records transitions for audit and undo.
+type Ledger interface {
+	// Append records a transition.
+	Append(ctx context.Context, t Transition) error
+	// Transitions returns every recorded transition, oldest first.
+	Transitions(ctx context.Context) ([]Transition, error)
+}
+
+// FileLedger is a Ledger kept as a JSON-lines file, one transition per line. The file is only
+// ever appended to.
+type FileLedger struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Append implements Ledger.
+func (l *FileLedger) Append(_ context.Context, t Transition) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("lifecycle: ledger: %w", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("lifecycle: ledger: %w", err)
+	}
+	return f.Close()
+}
+
+// Transitions implements Ledger. A missing file is an empty ledger.
+func (l *FileLedger) Transitions(_ context.Context) ([]Transition, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.Open(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle: ledger: %w", err)
+	}
+	defer f.Close()
+	var out []Transition
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var t Transition
+		if err := json.Unmarshal(sc.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("lifecycle: ledger %s line %d: %w", l.Path, line, err)
+		}
+		out = append(out, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("lifecycle: ledger: %w", err)
+	}
+	return out, nil
+}
+
+// MemoryLedger is an in-memory Ledger, useful for tests.
+type MemoryLedger struct {
+	mu          sync.Mutex
+	transitions []Transition
+}
+
+// Append implements Ledger.
+func (l *MemoryLedger) Append(_ context.Context, t Transition) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.transitions = append(l.transitions, t)
+	return nil
+}
+
+// Transitions implements Ledger.
+func (l *MemoryLedger) Transitions(_ context.Context) ([]Transition, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Transition(nil), l.transitions...), nil
+}
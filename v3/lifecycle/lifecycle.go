@@ -0,0 +1,458 @@
+// Package lifecycle archives and reactivates jobs by policy. Rules match jobs on their done flag,
+// metadata, and last activity, and say which status the job should have; a Manager evaluates them
+// over every job, exports a snapshot of each job before archiving it, and records each transition
+// in a Ledger so it can be audited and undone.
+//
+//	api := katapultpro.NewAPI(client)
+//	m := &lifecycle.Manager{
+//		Client:   api,
+//		Activity: lifecycle.PhotoUploads(api),
+//		Rules: []lifecycle.Rule{
+//			{Name: "reopened", To: katapultpro.JobStatusActive, When: lifecycle.MetadataEquals("reopen", true)},
+//			{Name: "stale", To: katapultpro.JobStatusArchived, When: lifecycle.All(lifecycle.Done(), lifecycle.InactiveFor(180*24*time.Hour))},
+//		},
+//		Ledger:      &lifecycle.FileLedger{Path: "lifecycle.jsonl"},
+//		SnapshotDir: "snapshots",
+//		Commit:      true,
+//	}
+//	report, err := m.Run(ctx)
+//	_, err = m.Undo(ctx, report.Decisions[0].TransitionID)
+//
+// The API does not expose tracked actions, so activity comes from an ActivitySource: photo upload
+// dates with PhotoUploads, or any other record of activity with ActivityFunc. Job list entries do not
+// contain the full job data, so Run fetches each job's status, done flag, and metadata before the
+// rules see it.
+package lifecycle
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/snapshot"
+)
+
+// Facts is what conditions see about a job. Last activity is looked up on first use.
+type Facts struct {
+	Job katapultpro.Job
+	Now time.Time
+
+	activity     ActivitySource
+	lastActivity time.Time
+	activityErr  error
+	looked       bool
+}
+
+// LastActivity returns the job's last activity from the Manager's ActivitySource, or the zero time
+// when it is not known.
+func (f *Facts) LastActivity(ctx context.Context) (time.Time, error) {
+	if !f.looked && f.activity != nil {
+		f.lastActivity, f.activityErr = f.activity.LastActivity(ctx, f.Job)
+	}
+	f.looked = true
+	return f.lastActivity, f.activityErr
+}
+
+// Condition reports whether a job matches.
+type Condition func(ctx context.Context, f *Facts) (bool, error)
+
+// Done matches jobs marked done.
+func Done() Condition {
+	return func(_ context.Context, f *Facts) (bool, error) { return f.Job.Done, nil }
+}
+
+// MetadataEquals matches jobs whose metadata attribute key has the value, compared as text.
+func MetadataEquals(key string, value interface{}) Condition {
+	want := fmt.Sprint(value)
+	return func(_ context.Context, f *Facts) (bool, error) {
+		v, ok := f.Job.Metadata[key]
+		return ok && v != nil && fmt.Sprint(v) == want, nil
+	}
+}
+
+// MetadataSet matches jobs that have the metadata attribute key.
+func MetadataSet(key string) Condition {
+	return func(_ context.Context, f *Facts) (bool, error) { return f.Job.Metadata[key] != nil, nil }
+}
+
+// InactiveFor matches jobs whose last activity is at least d ago. Jobs with no known activity do
+// not match.
+func InactiveFor(d time.Duration) Condition {
+	return func(ctx context.Context, f *Facts) (bool, error) {
+		t, err := f.LastActivity(ctx)
+		return err == nil && !t.IsZero() && f.Now.Sub(t) >= d, err
+	}
+}
+
+// ActiveWithin matches jobs with activity in the last d.
+func ActiveWithin(d time.Duration) Condition {
+	return func(ctx context.Context, f *Facts) (bool, error) {
+		t, err := f.LastActivity(ctx)
+		return err == nil && !t.IsZero() && f.Now.Sub(t) < d, err
+	}
+}
+
+// All matches jobs every condition matches.
+func All(cs ...Condition) Condition {
+	return func(ctx context.Context, f *Facts) (bool, error) {
+		for _, c := range cs {
+			if ok, err := c(ctx, f); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// Any matches jobs at least one condition matches.
+func Any(cs ...Condition) Condition {
+	return func(ctx context.Context, f *Facts) (bool, error) {
+		for _, c := range cs {
+			if ok, err := c(ctx, f); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+}
+
+// Not matches jobs c does not.
+func Not(c Condition) Condition {
+	return func(ctx context.Context, f *Facts) (bool, error) {
+		ok, err := c(ctx, f)
+		return !ok && err == nil, err
+	}
+}
+
+// Rule gives the status jobs matching When should have. Rules are tried in order and the first
+// that matches a job decides it, so an earlier rule can protect jobs from a later one.
+type Rule struct {
+	Name string
+	To   katapultpro.JobStatus
+	When Condition
+}
+
+// ActivitySource finds when a job last saw activity. The zero time means unknown.
+type ActivitySource interface {
+	LastActivity(ctx context.Context, job katapultpro.Job) (time.Time, error)
+}
+
+// ActivityFunc adapts a function to an ActivitySource.
+type ActivityFunc func(ctx context.Context, job katapultpro.Job) (time.Time, error)
+
+// LastActivity calls f.
+func (f ActivityFunc) LastActivity(ctx context.Context, job katapultpro.Job) (time.Time, error) {
+	return f(ctx, job)
+}
+
+// PhotoUploads returns the time of the job's latest photo upload (or, for photos without an upload
+// date, the latest date taken). It lists the job's photos, one request per job evaluated.
+func PhotoUploads(c katapultpro.ClientAPI) ActivitySource {
+	return ActivityFunc(func(ctx context.Context, job katapultpro.Job) (time.Time, error) {
+		photos, err := c.Job(job.ID).Photos().List(ctx)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("lifecycle: list photos of job %s: %w", job.ID, err)
+		}
+		var latest time.Time
+		for _, p := range photos {
+			n := p.UploadDate
+			if n == 0 {
+				n = p.DateTaken
+			}
+			if t := jobs.UnixTime(float64(n)); n != 0 && t.After(latest) {
+				latest = t
+			}
+		}
+		return latest, nil
+	})
+}
+
+// Latest returns the latest activity reported by any of the sources.
+func Latest(sources ...ActivitySource) ActivitySource {
+	return ActivityFunc(func(ctx context.Context, job katapultpro.Job) (time.Time, error) {
+		var latest time.Time
+		for _, s := range sources {
+			t, err := s.LastActivity(ctx, job)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if t.After(latest) {
+				latest = t
+			}
+		}
+		return latest, nil
+	})
+}
+
+// Decision statuses.
+const (
+	StatusDryRun  = "dry_run" // Manager.Commit is off
+	StatusApplied = "applied"
+	StatusFailed  = "failed"
+)
+
+// Decision is a status change a rule chose for a job.
+type Decision struct {
+	JobID        string                `json:"job_id"`
+	JobName      string                `json:"job_name,omitempty"`
+	From         katapultpro.JobStatus `json:"from"`
+	To           katapultpro.JobStatus `json:"to"`
+	Rule         string                `json:"rule"`
+	LastActivity time.Time             `json:"last_activity,omitzero"` // If a condition looked it up
+	Status       string                `json:"status"`
+	Error        string                `json:"error,omitempty"`
+	TransitionID string                `json:"transition_id,omitempty"` // Ledger entry, once applied
+	Snapshot     string                `json:"snapshot,omitempty"`
+}
+
+// Report lists the decisions of a run.
+type Report struct {
+	Time      time.Time  `json:"time"`
+	Evaluated int        `json:"evaluated"`
+	Skipped   []string   `json:"skipped,omitempty"` // IDs of jobs without a status, which were not evaluated
+	Decisions []Decision `json:"decisions"`
+}
+
+// Manager evaluates Rules over jobs and applies the transitions they choose. It is a dry run
+// unless Commit is set.
+type Manager struct {
+	Client   katapultpro.ClientAPI // katapultpro.NewAPI(client), or a katapultmock.ClientAPI in tests
+	Rules    []Rule
+	Activity ActivitySource // Needed by InactiveFor and ActiveWithin; nil means unknown
+	// Ledger records every transition. Required to commit and to undo.
+	Ledger Ledger
+	// SnapshotDir receives a JSON export of each job (the job and all of its nodes, connections,
+	// sections, photos, and traces) before it is archived. Required to commit an archive.
+	SnapshotDir string
+	Commit      bool
+	Now         func() time.Time // Defaults to time.Now
+}
+
+func (m *Manager) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// jobPaths are the job fields rules read.
+var jobPaths = []katapultpro.JobPath{
+	katapultpro.JobPathName, katapultpro.JobPathStatus, katapultpro.JobPathDone, katapultpro.JobPathMetadata,
+}
+
+// Run lists every job, archived ones included, fetches each job's name, status, done flag, and
+// metadata with jobs.FetchAll, evaluates the rules, and applies the decisions. Failures to apply a decision do not stop the run; they are recorded
+// in the report and returned joined.
+func (m *Manager) Run(ctx context.Context) (*Report, error) {
+	all, err := jobs.FetchAll(ctx, m.Client, &katapultpro.ListJobsOptions{IncludeArchived: true}, jobPaths)
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle: %w", err)
+	}
+	report, err := m.Evaluate(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	return report, m.Apply(ctx, report)
+}
+
+// Evaluate decides each job with the first rule that matches it. Jobs already in that rule's
+// status, and jobs no rule matches, get no decision. The jobs need their Status, and the Done and
+// Metadata the rules read; ListJobs entries lack them. Jobs without a status are not evaluated and
+// are listed in Report.Skipped.
+func (m *Manager) Evaluate(ctx context.Context, jobs []katapultpro.Job) (*Report, error) {
+	now := m.now()
+	report := &Report{Time: now, Decisions: []Decision{}}
+	for _, job := range jobs {
+		from := job.Status
+		if from == "" {
+			report.Skipped = append(report.Skipped, job.ID)
+			continue
+		}
+		report.Evaluated++
+		f := &Facts{Job: job, Now: now, activity: m.Activity}
+		for _, r := range m.Rules {
+			ok, err := r.When(ctx, f)
+			if err != nil {
+				return nil, fmt.Errorf("lifecycle: rule %s on job %s: %w", r.Name, job.ID, err)
+			}
+			if !ok {
+				continue
+			}
+			if r.To != from {
+				report.Decisions = append(report.Decisions, Decision{
+					JobID: job.ID, JobName: job.Name, From: from, To: r.To, Rule: r.Name,
+					LastActivity: f.lastActivity, Status: StatusDryRun,
+				})
+			}
+			break
+		}
+	}
+	return report, nil
+}
+
+// Apply carries out the report's dry-run decisions when Commit is set, snapshotting jobs before
+// archiving them and recording each transition in the Ledger.
+func (m *Manager) Apply(ctx context.Context, report *Report) error {
+	if !m.Commit {
+		return nil
+	}
+	if m.Ledger == nil {
+		return errors.New("lifecycle: a Ledger is required to commit")
+	}
+	var errs []error
+	for i := range report.Decisions {
+		d := &report.Decisions[i]
+		if d.Status != StatusDryRun {
+			continue
+		}
+		if err := m.apply(ctx, d); err != nil {
+			d.Status, d.Error = StatusFailed, err.Error()
+			errs = append(errs, fmt.Errorf("lifecycle: job %s: %w", d.JobID, err))
+			continue
+		}
+		d.Status = StatusApplied
+	}
+	return errors.Join(errs...)
+}
+
+func (m *Manager) apply(ctx context.Context, d *Decision) error {
+	now := m.now()
+	if d.To == katapultpro.JobStatusArchived {
+		path, err := m.snapshot(ctx, d.JobID, now)
+		if err != nil {
+			return err
+		}
+		d.Snapshot = path
+	}
+	if err := m.Client.UpdateJobStatus(ctx, d.JobID, d.To); err != nil {
+		return err
+	}
+	t := Transition{ID: newTransitionID(now, d.JobID), Time: now, JobID: d.JobID, JobName: d.JobName,
+		From: d.From, To: d.To, Rule: d.Rule, Snapshot: d.Snapshot}
+	if err := m.Ledger.Append(ctx, t); err != nil {
+		return fmt.Errorf("status changed to %s but not recorded: %w", d.To, err)
+	}
+	d.TransitionID = t.ID
+	return nil
+}
+
+// newTransitionID returns an ID that sorts by time and stays unique if the clock does not move.
+func newTransitionID(t time.Time, jobID string) string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%s-%s-%x", t.UTC().Format("20060102T150405.000Z"), jobID, b)
+}
+
+// Snapshot is the export written before a job is archived.
+type Snapshot struct {
+	Job      katapultpro.Job                                    `json:"job"`
+	TakenAt  time.Time                                          `json:"taken_at"`
+	Entities map[snapshot.EntityKind]map[string]json.RawMessage `json:"entities"`
+}
+
+func (m *Manager) snapshot(ctx context.Context, jobID string, now time.Time) (string, error) {
+	if m.SnapshotDir == "" {
+		return "", errors.New("SnapshotDir is required to archive")
+	}
+	job, err := m.Client.GetJob(ctx, jobID, nil)
+	if err != nil {
+		return "", fmt.Errorf("snapshot: %w", err)
+	}
+	s, err := snapshot.Take(ctx, m.Client.Job(jobID))
+	if err != nil {
+		return "", fmt.Errorf("snapshot: %w", err)
+	}
+	b, err := json.MarshalIndent(Snapshot{Job: *job, TakenAt: now, Entities: s.Entities}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("snapshot: %w", err)
+	}
+	if err := os.MkdirAll(m.SnapshotDir, 0o755); err != nil {
+		return "", fmt.Errorf("snapshot: %w", err)
+	}
+	path := filepath.Join(m.SnapshotDir, jobID+"-"+now.UTC().Format("20060102T150405Z")+".json")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return "", fmt.Errorf("snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// Undo reverts the transition with the given ID and records the reversal. It fails if the job's
+// status has changed since, or the transition was already undone. Undoing an archive does not
+// delete its snapshot.
+func (m *Manager) Undo(ctx context.Context, transitionID string) (*Transition, error) {
+	if m.Ledger == nil {
+		return nil, errors.New("lifecycle: a Ledger is required to undo")
+	}
+	all, err := m.Ledger.Transitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var orig *Transition
+	for i := range all {
+		switch {
+		case all[i].ID == transitionID:
+			orig = &all[i]
+		case all[i].Undoes == transitionID:
+			return nil, fmt.Errorf("lifecycle: transition %s was already undone by %s", transitionID, all[i].ID)
+		}
+	}
+	if orig == nil {
+		return nil, fmt.Errorf("lifecycle: no transition %s", transitionID)
+	}
+	status, err := m.Client.GetJobStatus(ctx, orig.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("lifecycle: job %s: %w", orig.JobID, err)
+	}
+	if status != orig.To {
+		return nil, fmt.Errorf("lifecycle: job %s is %s, not %s as transition %s left it", orig.JobID, status, orig.To, transitionID)
+	}
+	if err := m.Client.UpdateJobStatus(ctx, orig.JobID, orig.From); err != nil {
+		return nil, fmt.Errorf("lifecycle: job %s: %w", orig.JobID, err)
+	}
+	now := m.now()
+	t := Transition{ID: newTransitionID(now, orig.JobID), Time: now, JobID: orig.JobID, JobName: orig.JobName,
+		From: orig.To, To: orig.From, Rule: RuleUndo, Undoes: orig.ID}
+	if err := m.Ledger.Append(ctx, t); err != nil {
+		return nil, fmt.Errorf("lifecycle: job %s status restored to %s but not recorded: %w", orig.JobID, orig.From, err)
+	}
+	return &t, nil
+}
+
+// WriteText writes a summary line and one line per decision.
+func (r *Report) WriteText(w io.Writer) error {
+	applied, failed := 0, 0
+	for _, d := range r.Decisions {
+		switch d.Status {
+		case StatusApplied:
+			applied++
+		case StatusFailed:
+			failed++
+		}
+	}
+	skipped := ""
+	if len(r.Skipped) > 0 {
+		skipped = fmt.Sprintf(" (%d skipped without a status)", len(r.Skipped))
+	}
+	if _, err := fmt.Fprintf(w, "lifecycle: %d job(s) evaluated%s, %d decision(s), %d applied, %d failed\n",
+		r.Evaluated, skipped, len(r.Decisions), applied, failed); err != nil {
+		return err
+	}
+	for _, d := range r.Decisions {
+		line := fmt.Sprintf("  %-8s %s  %s -> %s  (%s)", d.Status, d.JobID, d.From, d.To, d.Rule)
+		if d.Error != "" {
+			line += "  " + d.Error
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package lifecycle_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapultmock"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/lifecycle"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/snapshot"
+)
+
+func TestManager(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	stale := srv.AddJob(katapultpro.Job{Name: "Stale", Done: true})
+	srv.AddPhoto(stale.ID, katapultpro.Photo{UploadDate: now.AddDate(0, -8, 0).UnixMilli()})
+	srv.AddNode(stale.ID, katapultpro.Node{Latitude: 40, Longitude: -80})
+	busy := srv.AddJob(katapultpro.Job{Name: "Busy", Done: true})
+	srv.AddPhoto(busy.ID, katapultpro.Photo{DateTaken: now.AddDate(0, 0, -3).Unix()})
+	reopen := srv.AddJob(katapultpro.Job{Name: "Reopen", Status: katapultpro.JobStatusArchived, Metadata: map[string]interface{}{"reopen": true}})
+	srv.AddJob(katapultpro.Job{Name: "Open"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	m := &lifecycle.Manager{
+		Client:   katapultpro.NewAPI(client),
+		Activity: lifecycle.PhotoUploads(katapultpro.NewAPI(client)),
+		Rules: []lifecycle.Rule{
+			{Name: "reopened", To: katapultpro.JobStatusActive, When: lifecycle.MetadataEquals("reopen", true)},
+			{Name: "stale", To: katapultpro.JobStatusArchived, When: lifecycle.All(lifecycle.Done(), lifecycle.InactiveFor(180*24*time.Hour))},
+		},
+		Ledger:      &lifecycle.FileLedger{Path: filepath.Join(dir, "ledger.jsonl")},
+		SnapshotDir: filepath.Join(dir, "snapshots"),
+		Now:         func() time.Time { return now },
+	}
+	report, err := m.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, d := range report.Decisions {
+		got = append(got, d.JobID+" "+string(d.From)+">"+string(d.To)+" "+d.Rule+" "+d.Status)
+	}
+	want := map[string]bool{
+		stale.ID + " active>archived stale dry_run":     true,
+		reopen.ID + " archived>active reopened dry_run": true,
+	}
+	if len(got) != 2 || !want[got[0]] || !want[got[1]] || report.Evaluated != 4 {
+		t.Fatalf("dry run: %d evaluated\n%s", report.Evaluated, strings.Join(got, "\n"))
+	}
+	// List entries lack the full job data, so every job is fetched with the fields rules read.
+	fetched := 0
+	for _, r := range srv.Requests() {
+		if r.Method == "GET" && r.Query.Get("paths") == "name,status,done,metadata" {
+			fetched++
+		}
+	}
+	if fetched != 4 {
+		t.Errorf("fetched %d jobs, want 4", fetched)
+	}
+	if status, _ := client.GetJobStatus(ctx, stale.ID); status != katapultpro.JobStatusActive {
+		t.Errorf("dry run changed status to %s", status)
+	}
+
+	m.Commit = true
+	if report, err = m.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var archived lifecycle.Decision
+	for _, d := range report.Decisions {
+		if d.Status != lifecycle.StatusApplied || d.TransitionID == "" {
+			t.Errorf("commit: %+v", d)
+		}
+		if d.JobID == stale.ID {
+			archived = d
+		}
+	}
+	if status, _ := client.GetJobStatus(ctx, stale.ID); status != katapultpro.JobStatusArchived {
+		t.Errorf("stale job status = %s", status)
+	}
+	if status, _ := client.GetJobStatus(ctx, reopen.ID); status != katapultpro.JobStatusActive {
+		t.Errorf("reopened job status = %s", status)
+	}
+
+	b, err := os.ReadFile(archived.Snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap lifecycle.Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil || snap.Job.Name != "Stale" || len(snap.Entities[snapshot.EntityNode]) != 1 || len(snap.Entities[snapshot.EntityPhoto]) != 1 {
+		t.Errorf("snapshot = %s, %v", b, err)
+	}
+
+	undo, err := m.Undo(ctx, archived.TransitionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if undo.Undoes != archived.TransitionID || undo.To != katapultpro.JobStatusActive || undo.Rule != lifecycle.RuleUndo {
+		t.Errorf("undo = %+v", undo)
+	}
+	if status, _ := client.GetJobStatus(ctx, stale.ID); status != katapultpro.JobStatusActive {
+		t.Errorf("undone job status = %s", status)
+	}
+	if _, err := m.Undo(ctx, archived.TransitionID); err == nil {
+		t.Error("second undo: expected error")
+	}
+	transitions, err := m.Ledger.Transitions(ctx)
+	if err != nil || len(transitions) != 3 {
+		t.Fatalf("ledger = %+v, %v", transitions, err)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "lifecycle: 4 job(s) evaluated, 2 decision(s), 2 applied, 0 failed\n") {
+		t.Errorf("text:\n%s", buf.String())
+	}
+}
+
+func TestUndoChangedStatus(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Done", Done: true})
+	client := srv.Client()
+	ctx := context.Background()
+	m := &lifecycle.Manager{
+		Client:      katapultpro.NewAPI(client),
+		Rules:       []lifecycle.Rule{{Name: "done", To: katapultpro.JobStatusArchived, When: lifecycle.Done()}},
+		Ledger:      &lifecycle.MemoryLedger{},
+		SnapshotDir: t.TempDir(),
+		Commit:      true,
+	}
+	report, err := m.Run(ctx)
+	if err != nil || len(report.Decisions) != 1 {
+		t.Fatalf("run: %+v, %v", report, err)
+	}
+	if err := client.UpdateJobStatus(ctx, job.ID, katapultpro.JobStatusActive); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Undo(ctx, report.Decisions[0].TransitionID); err == nil || !strings.Contains(err.Error(), "is active") {
+		t.Errorf("undo after manual change: %v", err)
+	}
+
+	m.SnapshotDir = ""
+	if err := client.UpdateJobStatus(ctx, job.ID, katapultpro.JobStatusActive); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Run(ctx); err == nil {
+		t.Error("archive without SnapshotDir: expected error")
+	}
+}
+
+func TestConditions(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	activity := lifecycle.ActivityFunc(func(context.Context, katapultpro.Job) (time.Time, error) {
+		calls++
+		return now.Add(-48 * time.Hour), nil
+	})
+	m := &lifecycle.Manager{Activity: activity, Now: func() time.Time { return now }, Rules: []lifecycle.Rule{
+		{Name: "keep", To: katapultpro.JobStatusActive, When: lifecycle.Any(lifecycle.MetadataSet("pinned"), lifecycle.ActiveWithin(24*time.Hour))},
+		{Name: "idle", To: katapultpro.JobStatusArchived, When: lifecycle.All(lifecycle.Not(lifecycle.Done()), lifecycle.InactiveFor(24*time.Hour))},
+	}}
+	active := katapultpro.JobStatusActive
+	report, err := m.Evaluate(ctx, []katapultpro.Job{
+		{ID: "a", Status: active},
+		{ID: "b", Status: active, Metadata: map[string]interface{}{"pinned": "yes"}},
+		{ID: "c", Status: active, Done: true},
+		{ID: "d"}, // No status, as in a ListJobs entry
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Evaluated != 3 || len(report.Skipped) != 1 || report.Skipped[0] != "d" {
+		t.Errorf("evaluated %d, skipped %v", report.Evaluated, report.Skipped)
+	}
+	if len(report.Decisions) != 1 || report.Decisions[0].JobID != "a" || report.Decisions[0].LastActivity.IsZero() {
+		t.Errorf("decisions = %+v", report.Decisions)
+	}
+	if calls != 2 {
+		t.Errorf("activity looked up %d times, want once each for a and c", calls)
+	}
+}
+
+func TestManagerMock(t *testing.T) {
+	ctx := context.Background()
+	status := katapultpro.JobStatusArchived
+	api := &katapultmock.ClientAPI{
+		ListJobsFunc: func(context.Context, *katapultpro.ListJobsOptions) ([]katapultpro.Job, error) {
+			return []katapultpro.Job{{ID: "j1", Name: "Reopen"}}, nil
+		},
+		GetJobFunc: func(context.Context, string, *katapultpro.GetJobOptions) (*katapultpro.Job, error) {
+			return &katapultpro.Job{Name: "Reopen", Status: katapultpro.JobStatusArchived, Metadata: map[string]interface{}{"reopen": true}}, nil
+		},
+		GetJobStatusFunc: func(context.Context, string) (katapultpro.JobStatus, error) { return status, nil },
+		UpdateJobStatusFunc: func(_ context.Context, _ string, s katapultpro.JobStatus) error {
+			status = s
+			return nil
+		},
+	}
+	m := &lifecycle.Manager{
+		Client: api,
+		Rules:  []lifecycle.Rule{{Name: "reopened", To: katapultpro.JobStatusActive, When: lifecycle.MetadataEquals("reopen", true)}},
+		Ledger: &lifecycle.MemoryLedger{},
+		Commit: true,
+	}
+	report, err := m.Run(ctx)
+	if err != nil || len(report.Decisions) != 1 || report.Decisions[0].Status != lifecycle.StatusApplied {
+		t.Fatalf("run: %+v, %v", report, err)
+	}
+	api.AssertCalled(t, "UpdateJobStatus", "j1", katapultpro.JobStatusActive)
+	if _, err := m.Undo(ctx, report.Decisions[0].TransitionID); err != nil || status != katapultpro.JobStatusArchived {
+		t.Errorf("undo: status %s, %v", status, err)
+	}
+}
@@ -0,0 +1,212 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Change actions.
+const (
+	// ActionRemoveInstances removes InstanceIDs of Attribute from a node, connection, or section
+	// (ParentID is the section's connection).
+	ActionRemoveInstances = "remove_instances"
+	// ActionAssociate sets the association of PhotoID with node ID to Association: "main", "true",
+	// or "" to remove it.
+	ActionAssociate = "associate"
+	// ActionDeleteTrace deletes trace ID.
+	ActionDeleteTrace = "delete_trace"
+)
+
+// Change is one API call that fixes a finding.
+type Change struct {
+	Action      string   `json:"action"`
+	Check       string   `json:"check,omitempty"`
+	Entity      string   `json:"entity"`
+	ID          string   `json:"id"`
+	ParentID    string   `json:"parent_id,omitempty"`
+	Attribute   string   `json:"attribute,omitempty"`
+	InstanceIDs []string `json:"instance_ids,omitempty"`
+	PhotoID     string   `json:"photo_id,omitempty"`
+	Association string   `json:"association,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Action {
+	case ActionRemoveInstances:
+		return fmt.Sprintf("remove %s instance(s) %s from %s %s", c.Attribute, strings.Join(c.InstanceIDs, ", "), c.Entity, pathOf(c.ParentID, c.ID))
+	case ActionAssociate:
+		if c.Association == "" {
+			return fmt.Sprintf("remove association of photo %s with %s %s", c.PhotoID, c.Entity, c.ID)
+		}
+		return fmt.Sprintf("associate photo %s with %s %s as %s", c.PhotoID, c.Entity, c.ID, c.Association)
+	case ActionDeleteTrace:
+		return "delete trace " + c.ID
+	}
+	return c.Action + " " + c.Entity + " " + c.ID
+}
+
+func pathOf(parentID, id string) string {
+	if parentID != "" {
+		return parentID + "/" + id
+	}
+	return id
+}
+
+// Plan is the list of changes that fix a job's findings, for review before Apply.
+type Plan struct {
+	JobID   string   `json:"job_id"`
+	Changes []Change `json:"changes"`
+}
+
+// PlanFixes returns the fixes of the findings that have one, in finding order.
+func PlanFixes(findings []Finding) []Change {
+	var out []Change
+	for _, f := range findings {
+		if f.Fix != nil {
+			c := *f.Fix
+			c.Check = f.Check
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// WriteText writes one line per change.
+func (p *Plan) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "fix plan for job %s: %d change(s)\n", p.JobID, len(p.Changes))
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "%-30s %s\n", c.Check, c)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Change statuses in the audit log.
+const (
+	StatusDryRun  = "dry_run"
+	StatusApplied = "applied"
+	StatusFailed  = "failed"
+)
+
+// AuditEntry records what happened to one change.
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	JobID  string    `json:"job_id"`
+	Change Change    `json:"change"`
+	Status string    `json:"status"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Fixer plans and applies the mechanical fixes of lint findings. It is a dry run unless Commit is
+// set.
+type Fixer struct {
+	Job    *katapultpro.JobScope
+	Config *Config
+	// Commit applies changes; when false, Apply only records them as dry runs.
+	Commit bool
+	// Audit, if set, receives one JSON line per change (an AuditEntry) as it is applied.
+	Audit io.Writer
+	// Concurrency bounds the element listings in flight while planning. Zero means DefaultConcurrency.
+	Concurrency int
+	// Now returns the audit time. Nil means time.Now.
+	Now func() time.Time
+}
+
+// Plan lints the job and returns the fixes for its findings.
+func (f *Fixer) Plan(ctx context.Context) (*Plan, error) {
+	cfg := Config{}
+	if f.Config != nil {
+		cfg = *f.Config
+	}
+	// Element listings are only needed by a check that has no fix.
+	cfg.Disable = append(append([]string(nil), cfg.Disable...), "element-no-height")
+	report, err := (&Linter{Job: f.Job, Config: &cfg, Concurrency: f.Concurrency}).Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{JobID: f.Job.JobID(), Changes: PlanFixes(report.Findings)}, nil
+}
+
+// Apply applies the plan's changes in order, continuing past failures, and returns the audit entries.
+// It returns an error joining the failures, or an error writing the audit log, which stops it.
+func (f *Fixer) Apply(ctx context.Context, plan *Plan) ([]AuditEntry, error) {
+	now := f.Now
+	if now == nil {
+		now = time.Now
+	}
+	var entries []AuditEntry
+	var errs []error
+	for _, c := range plan.Changes {
+		e := AuditEntry{JobID: f.Job.JobID(), Change: c, Status: StatusDryRun}
+		if f.Commit {
+			if err := f.apply(ctx, c); err != nil {
+				e.Status, e.Error = StatusFailed, err.Error()
+				errs = append(errs, fmt.Errorf("lint: %s: %w", c, err))
+			} else {
+				e.Status = StatusApplied
+			}
+		}
+		e.Time = now().UTC()
+		entries = append(entries, e)
+		if f.Audit != nil {
+			b, err := json.Marshal(e)
+			if err != nil {
+				return entries, err
+			}
+			if _, err := f.Audit.Write(append(b, '\n')); err != nil {
+				return entries, fmt.Errorf("lint: write audit log: %w", err)
+			}
+		}
+	}
+	return entries, errors.Join(errs...)
+}
+
+func (f *Fixer) apply(ctx context.Context, c Change) error {
+	switch c.Action {
+	case ActionRemoveInstances:
+		remove := katapultpro.EntityAttributeList{c.Attribute: {}}
+		for _, id := range c.InstanceIDs {
+			remove[c.Attribute][id] = nil
+		}
+		switch c.Entity {
+		case EntityNode:
+			_, err := f.Job.Nodes().Node(c.ID).Update(ctx, &katapultpro.UpdateNodeRequest{Attributes: remove},
+				&katapultpro.UpdateNodeOptions{OnlyIfExists: true})
+			return err
+		case EntityConnection:
+			_, err := f.Job.Connections().Connection(c.ID).Update(ctx, &katapultpro.UpdateConnectionRequest{Attributes: remove},
+				&katapultpro.UpdateConnectionOptions{OnlyIfExists: true})
+			return err
+		case EntitySection:
+			_, err := f.Job.Connections().Sections(c.ParentID).Update(ctx, c.ID, &katapultpro.UpdateSectionRequest{Attributes: remove},
+				&katapultpro.UpdateSectionOptions{OnlyIfExists: true})
+			return err
+		}
+	case ActionAssociate:
+		if c.Entity != EntityNode {
+			break
+		}
+		req := &katapultpro.AssociatePhotoRequest{NodeID: c.ID}
+		switch c.Association {
+		case "main":
+			req.AssociationValue = katapultpro.PtrPhotoAssociationMain()
+		case "true":
+			req.AssociationValue = katapultpro.PtrPhotoAssociationTrue()
+		case "": // A null association_value removes the association.
+		default:
+			return fmt.Errorf("unknown association %q", c.Association)
+		}
+		return f.Job.Photos().Associate(ctx, c.PhotoID, req)
+	case ActionDeleteTrace:
+		return f.Job.Traces().Delete(ctx, c.ID)
+	}
+	return fmt.Errorf("unsupported change %s on %s", c.Action, c.Entity)
+}
@@ -0,0 +1,710 @@
+// Package lint runs data quality checks over a job before delivery.
+//
+//	l := &lint.Linter{Job: client.Job("job-123"), Config: cfg}
+//	report, err := l.Run(ctx)
+//	_ = report.WriteText(os.Stdout)
+//	if report.Count(lint.SeverityError) > 0 { ... }
+//
+// Each finding names a check, a severity, the entity it is about, and a hint for fixing it.
+// Findings with a mechanical fix carry it as a Change; see Fixer. Checks can be disabled or have
+// their severity changed in the Config, which is usually loaded from JSON:
+//
+//	{
+//	  "required_node_attributes": ["scid", "pole_tag"],
+//	  "corridor_width": 30,
+//	  "min_anchor_score": 0.6,
+//	  "disable": ["unassociated-photo"],
+//	  "severity": {"element-no-height": "error"}
+//	}
+//
+// Photo associations with deleted nodes are removed through the photo associate endpoint with a
+// null association_value, which the API documents as unassociating the photo. The documentation
+// does not say whether the node must still exist; if the API rejects the request, Apply records
+// the change as failed and the finding remains.
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Severity is how serious a finding is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	}
+	return 2
+}
+
+// Entity types of findings.
+const (
+	EntityNode       = "node"
+	EntityConnection = "connection"
+	EntitySection    = "section"
+	EntityPhoto      = "photo"
+	EntityElement    = "element"
+	EntityAnchor     = "anchor"
+	EntityTrace      = "trace"
+)
+
+// Finding is one problem found by a check.
+type Finding struct {
+	Check    string   `json:"check"`
+	Severity Severity `json:"severity"`
+	Entity   string   `json:"entity"` // One of the Entity constants
+	ID       string   `json:"id"`
+	// ParentID is the connection of a section, or the photo of an element or anchor.
+	ParentID string `json:"parent_id,omitempty"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+	// Fix is the mechanical correction for the finding, if it has one. See Fixer.
+	Fix *Change `json:"fix,omitempty"`
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%-7s %s %s %s: %s", f.Severity, f.Check, f.Entity, f.entityPath(), f.Message)
+	if f.Hint != "" {
+		s += " (" + f.Hint + ")"
+	}
+	return s
+}
+
+func (f Finding) entityPath() string {
+	if f.ParentID != "" {
+		return f.ParentID + "/" + f.ID
+	}
+	return f.ID
+}
+
+// Defaults for Config.
+const (
+	DefaultSCIDAttribute  = "scid"
+	DefaultCorridorWidth  = 30.0 // Meters
+	DefaultMinAnchorScore = 0.5
+)
+
+// Config selects and tunes the checks. The zero value runs every check with its defaults.
+type Config struct {
+	// RequiredNodeAttributes are attributes every node must have a non-empty value for.
+	RequiredNodeAttributes []string `json:"required_node_attributes,omitempty"`
+	// SCIDAttribute is the node attribute checked for duplicates. Default DefaultSCIDAttribute.
+	SCIDAttribute string `json:"scid_attribute,omitempty"`
+	// SingleValuedAttributes are node, connection, and section attributes that should have one
+	// instance. Default: the SCID attribute.
+	SingleValuedAttributes []string `json:"single_valued_attributes,omitempty"`
+	// CorridorWidth is how far, in meters, a section may lie from the line between its
+	// connection's nodes. Default DefaultCorridorWidth.
+	CorridorWidth float64 `json:"corridor_width,omitempty"`
+	// MinAnchorScore is the lowest acceptable _score of an auto-detected calibration anchor.
+	// Default DefaultMinAnchorScore.
+	MinAnchorScore float64 `json:"min_anchor_score,omitempty"`
+	// Disable lists checks not to run.
+	Disable []string `json:"disable,omitempty"`
+	// Severity overrides the default severity of checks.
+	Severity map[string]Severity `json:"severity,omitempty"`
+}
+
+// LoadConfig decodes a JSON config and validates it. Unknown fields are rejected.
+func LoadConfig(r io.Reader) (*Config, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("lint: decode config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate reports unknown check names and severities.
+func (cfg *Config) Validate() error {
+	for _, name := range cfg.Disable {
+		if lookup(name) == nil {
+			return fmt.Errorf("lint: disable: unknown check %q", name)
+		}
+	}
+	for name, sev := range cfg.Severity {
+		if lookup(name) == nil {
+			return fmt.Errorf("lint: severity: unknown check %q", name)
+		}
+		if sev != SeverityError && sev != SeverityWarning && sev != SeverityInfo {
+			return fmt.Errorf("lint: severity of %s: unknown severity %q", name, sev)
+		}
+	}
+	if cfg.CorridorWidth < 0 || cfg.MinAnchorScore < 0 {
+		return fmt.Errorf("lint: corridor_width and min_anchor_score must not be negative")
+	}
+	return nil
+}
+
+func (cfg *Config) scidAttribute() string {
+	if cfg.SCIDAttribute == "" {
+		return DefaultSCIDAttribute
+	}
+	return cfg.SCIDAttribute
+}
+
+func (cfg *Config) singleValuedAttributes() []string {
+	if len(cfg.SingleValuedAttributes) == 0 {
+		return []string{cfg.scidAttribute()}
+	}
+	return cfg.SingleValuedAttributes
+}
+
+func (cfg *Config) corridorWidth() float64 {
+	if cfg.CorridorWidth == 0 {
+		return DefaultCorridorWidth
+	}
+	return cfg.CorridorWidth
+}
+
+func (cfg *Config) minAnchorScore() float64 {
+	if cfg.MinAnchorScore == 0 {
+		return DefaultMinAnchorScore
+	}
+	return cfg.MinAnchorScore
+}
+
+// Data is the job content the checks run over.
+type Data struct {
+	Nodes       []katapultpro.Node
+	Connections []katapultpro.Connection
+	Photos      []katapultpro.Photo
+	// Elements maps photo IDs to their photo elements. Photos without an entry are not checked by
+	// element checks.
+	Elements map[string][]katapultpro.PhotoElement
+	Traces   []katapultpro.Trace
+}
+
+// Check is a named check.
+type Check struct {
+	Name     string
+	Severity Severity // Default severity
+	Doc      string
+	run      func(d *Data, cfg *Config) []Finding
+}
+
+// Checks returns the available checks in the order they run.
+func Checks() []Check { return append([]Check(nil), checks...) }
+
+func lookup(name string) *Check {
+	for i := range checks {
+		if checks[i].Name == name {
+			return &checks[i]
+		}
+	}
+	return nil
+}
+
+var checks = []Check{
+	{"node-required-attributes", SeverityError, "nodes missing a required attribute", checkRequiredAttributes},
+	{"duplicate-scid", SeverityError, "nodes sharing an SCID", checkDuplicateSCIDs},
+	{"duplicate-attribute-instances", SeverityWarning, "single-valued attributes with more than one instance", checkAttributeInstances},
+	{"connection-missing-node", SeverityError, "connections whose node_id_1 or node_id_2 does not exist", checkConnectionNodes},
+	{"section-outside-corridor", SeverityWarning, "sections farther than corridor_width from their connection", checkSectionCorridor},
+	{"unassociated-photo", SeverityWarning, "photos not associated with any node, connection, or section", checkUnassociatedPhotos},
+	{"stale-photo-association", SeverityWarning, "photos associated with nodes that no longer exist", checkStaleAssociations},
+	{"no-main-photo", SeverityWarning, "nodes with photos but none marked main", checkMainPhotos},
+	{"low-anchor-score", SeverityWarning, "auto-detected calibration anchors scoring below min_anchor_score", checkAnchorScores},
+	{"stick-align-errors", SeverityWarning, "photos whose stick alignment reported errors", checkStickAlign},
+	{"element-no-height", SeverityWarning, "photo elements with neither a manual nor a measured height", checkElementHeights},
+	{"trace-no-items", SeverityWarning, "traces with no items", checkTraceItems},
+}
+
+// Lint runs the enabled checks over d. Findings are sorted by severity, then check order, then
+// entity.
+func Lint(d *Data, cfg *Config) []Finding {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	var out []Finding
+	order := map[string]int{}
+	for i, c := range checks {
+		order[c.Name] = i
+		if containsString(cfg.Disable, c.Name) {
+			continue
+		}
+		sev := c.Severity
+		if s, ok := cfg.Severity[c.Name]; ok {
+			sev = s
+		}
+		for _, f := range c.run(d, cfg) {
+			f.Check, f.Severity = c.Name, sev
+			out = append(out, f)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Severity.rank() != b.Severity.rank() {
+			return a.Severity.rank() < b.Severity.rank()
+		}
+		return order[a.Check] < order[b.Check]
+	})
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRequiredAttributes(d *Data, cfg *Config) []Finding {
+	var out []Finding
+	for _, n := range sortedNodes(d.Nodes) {
+		for _, attr := range cfg.RequiredNodeAttributes {
+			if firstValue(n.Attributes[attr]) == "" {
+				out = append(out, Finding{Entity: EntityNode, ID: n.ID,
+					Message: fmt.Sprintf("missing attribute %s", attr),
+					Hint:    fmt.Sprintf("set %s on the node", attr)})
+			}
+		}
+	}
+	return out
+}
+
+func checkDuplicateSCIDs(d *Data, cfg *Config) []Finding {
+	attr := cfg.scidAttribute()
+	byValue := map[string][]string{}
+	for _, n := range sortedNodes(d.Nodes) {
+		if v := firstValue(n.Attributes[attr]); v != "" {
+			byValue[v] = append(byValue[v], n.ID)
+		}
+	}
+	var out []Finding
+	for _, n := range sortedNodes(d.Nodes) {
+		v := firstValue(n.Attributes[attr])
+		if ids := byValue[v]; len(ids) > 1 {
+			out = append(out, Finding{Entity: EntityNode, ID: n.ID,
+				Message: fmt.Sprintf("%s %q is shared by nodes %s", attr, v, strings.Join(ids, ", ")),
+				Hint:    "renumber the nodes so each " + attr + " is unique"})
+		}
+	}
+	return out
+}
+
+func checkAttributeInstances(d *Data, cfg *Config) []Finding {
+	var out []Finding
+	check := func(entity, id, parentID string, attrs katapultpro.EntityAttributeList) {
+		for _, name := range cfg.singleValuedAttributes() {
+			instances := attrs[name]
+			if len(instances) < 2 {
+				continue
+			}
+			ids := sortedKeys(instances)
+			f := Finding{Entity: entity, ID: id, ParentID: parentID,
+				Message: fmt.Sprintf("attribute %s has %d instances", name, len(ids)),
+				Hint:    "keep one instance of " + name}
+			if allEqual(instances) {
+				f.Fix = &Change{Action: ActionRemoveInstances, Entity: entity, ID: id, ParentID: parentID, Attribute: name, InstanceIDs: ids[1:]}
+			} else {
+				f.Hint = "the values differ; choose the right " + name + " and remove the other instances"
+			}
+			out = append(out, f)
+		}
+	}
+	for _, n := range sortedNodes(d.Nodes) {
+		check(EntityNode, n.ID, "", n.Attributes)
+	}
+	for _, c := range sortedConnections(d.Connections) {
+		check(EntityConnection, c.ID, "", c.Attributes)
+		for _, id := range sortedKeys(c.Sections) {
+			check(EntitySection, id, c.ID, c.Sections[id].MultiAttributes)
+		}
+	}
+	return out
+}
+
+func allEqual(instances map[string]interface{}) bool {
+	var first string
+	i := 0
+	for _, v := range instances {
+		b, _ := json.Marshal(v)
+		if i > 0 && string(b) != first {
+			return false
+		}
+		first = string(b)
+		i++
+	}
+	return true
+}
+
+func checkConnectionNodes(d *Data, _ *Config) []Finding {
+	nodes := nodeIndex(d.Nodes)
+	var out []Finding
+	for _, c := range sortedConnections(d.Connections) {
+		for _, id := range []string{c.NodeID1, c.NodeID2} {
+			if nodes[id] == nil {
+				msg := "references missing node " + id
+				if id == "" {
+					msg = "has an empty node ID"
+				}
+				out = append(out, Finding{Entity: EntityConnection, ID: c.ID, Message: msg,
+					Hint: "reconnect it to an existing node or delete the connection"})
+			}
+		}
+	}
+	return out
+}
+
+func checkSectionCorridor(d *Data, cfg *Config) []Finding {
+	nodes := nodeIndex(d.Nodes)
+	width := cfg.corridorWidth()
+	var out []Finding
+	for _, c := range sortedConnections(d.Connections) {
+		n1, n2 := nodes[c.NodeID1], nodes[c.NodeID2]
+		if n1 == nil || n2 == nil || !hasPosition(n1.Latitude, n1.Longitude) || !hasPosition(n2.Latitude, n2.Longitude) {
+			continue
+		}
+		for _, id := range sortedKeys(c.Sections) {
+			s := c.Sections[id]
+			if !hasPosition(s.Latitude, s.Longitude) {
+				continue
+			}
+			if dist := segmentDistance(s.Latitude, s.Longitude, n1.Latitude, n1.Longitude, n2.Latitude, n2.Longitude); dist > width {
+				out = append(out, Finding{Entity: EntitySection, ID: id, ParentID: c.ID,
+					Message: fmt.Sprintf("is %.1fm from the line between nodes %s and %s", dist, n1.ID, n2.ID),
+					Hint:    "move the section onto its span or attach it to the right connection"})
+			}
+		}
+	}
+	return out
+}
+
+func checkUnassociatedPhotos(d *Data, _ *Config) []Finding {
+	associated := map[string]bool{}
+	mark := func(m katapultpro.PhotoAssociationMap) {
+		for id := range m {
+			associated[id] = true
+		}
+	}
+	for _, n := range d.Nodes {
+		mark(n.Photos)
+	}
+	for _, c := range d.Connections {
+		mark(c.Photos)
+		for _, s := range c.Sections {
+			mark(s.Photos)
+		}
+	}
+	var out []Finding
+	for _, p := range sortedPhotos(d.Photos) {
+		if !associated[p.ID] && len(p.AssociatedLocations) == 0 {
+			out = append(out, Finding{Entity: EntityPhoto, ID: p.ID, Message: "is not associated with any node, connection, or section",
+				Hint: "associate it with the pole or span it shows, or delete it"})
+		}
+	}
+	return out
+}
+
+func checkStaleAssociations(d *Data, _ *Config) []Finding {
+	nodes := nodeIndex(d.Nodes)
+	var out []Finding
+	for _, p := range sortedPhotos(d.Photos) {
+		for _, id := range sortedKeys(p.AssociatedLocations) {
+			if p.AssociatedLocations[id] == EntityNode && nodes[id] == nil {
+				out = append(out, Finding{Entity: EntityPhoto, ID: p.ID,
+					Message: "is associated with missing node " + id,
+					Hint:    "remove the association",
+					Fix:     &Change{Action: ActionAssociate, Entity: EntityNode, ID: id, PhotoID: p.ID}})
+			}
+		}
+	}
+	return out
+}
+
+func checkMainPhotos(d *Data, _ *Config) []Finding {
+	photos := map[string]*katapultpro.Photo{}
+	for i := range d.Photos {
+		photos[d.Photos[i].ID] = &d.Photos[i]
+	}
+	var out []Finding
+	for _, n := range sortedNodes(d.Nodes) {
+		if len(n.Photos) == 0 {
+			continue
+		}
+		var candidates []string
+		hasMain := false
+		for id, a := range n.Photos {
+			if s, ok := a.Association.(string); ok && s == "main" {
+				hasMain = true
+			}
+			candidates = append(candidates, id)
+		}
+		if hasMain {
+			continue
+		}
+		f := Finding{Entity: EntityNode, ID: n.ID, Message: fmt.Sprintf("has %d photo(s) but no main photo", len(candidates)),
+			Hint: "mark the best photo of the pole as main"}
+		// Propose the earliest photo taken, as the first photo of a pole is usually its overview.
+		sort.Slice(candidates, func(i, j int) bool {
+			ti, tj := dateTaken(photos[candidates[i]]), dateTaken(photos[candidates[j]])
+			if ti != tj {
+				return ti < tj
+			}
+			return candidates[i] < candidates[j]
+		})
+		f.Fix = &Change{Action: ActionAssociate, Entity: EntityNode, ID: n.ID, PhotoID: candidates[0], Association: "main"}
+		out = append(out, f)
+	}
+	return out
+}
+
+// dateTaken sorts photos without a known date last.
+func dateTaken(p *katapultpro.Photo) int64 {
+	if p == nil || p.DateTaken == 0 {
+		return math.MaxInt64
+	}
+	return p.DateTaken
+}
+
+func checkAnchorScores(d *Data, cfg *Config) []Finding {
+	min := cfg.minAnchorScore()
+	var out []Finding
+	for _, p := range sortedPhotos(d.Photos) {
+		if p.PhotofirstData == nil {
+			continue
+		}
+		for _, id := range sortedKeys(p.PhotofirstData.AnchorCalibration) {
+			a := p.PhotofirstData.AnchorCalibration[id]
+			// Score is only set on anchors placed by auto-detection.
+			if a.RoutineInstanceID != "" && a.Score < min {
+				out = append(out, Finding{Entity: EntityAnchor, ID: id, ParentID: p.ID,
+					Message: fmt.Sprintf("auto-detected anchor scores %.2f, below %.2f", a.Score, min),
+					Hint:    "check the anchor placement and height on the photo"})
+			}
+		}
+	}
+	return out
+}
+
+func checkStickAlign(d *Data, _ *Config) []Finding {
+	var out []Finding
+	for _, p := range sortedPhotos(d.Photos) {
+		if p.StickAlign == nil || len(p.StickAlign.ErrorMessages) == 0 {
+			continue
+		}
+		types := make([]string, 0, len(p.StickAlign.ErrorMessages))
+		for _, e := range p.StickAlign.ErrorMessages {
+			types = append(types, e.Type)
+		}
+		out = append(out, Finding{Entity: EntityPhoto, ID: p.ID,
+			Message: "stick alignment reported " + strings.Join(types, ", "),
+			Hint:    "redo the stick alignment or calibrate the photo with anchors"})
+	}
+	return out
+}
+
+func checkElementHeights(d *Data, _ *Config) []Finding {
+	var out []Finding
+	for _, photoID := range sortedKeys(d.Elements) {
+		els := append([]katapultpro.PhotoElement(nil), d.Elements[photoID]...)
+		sort.Slice(els, func(i, j int) bool { return els[i].ID < els[j].ID })
+		for _, e := range els {
+			if e.ManualHeight == "" && e.MeasuredHeight == 0 {
+				out = append(out, Finding{Entity: EntityElement, ID: e.ID, ParentID: photoID,
+					Message: fmt.Sprintf("%s has neither a manual nor a measured height", e.Type()),
+					Hint:    "calibrate the photo or enter a manual height"})
+			}
+		}
+	}
+	return out
+}
+
+func checkTraceItems(d *Data, _ *Config) []Finding {
+	traces := append([]katapultpro.Trace(nil), d.Traces...)
+	sort.Slice(traces, func(i, j int) bool { return traces[i].ID < traces[j].ID })
+	var out []Finding
+	for _, t := range traces {
+		if !hasItems(t) {
+			out = append(out, Finding{Entity: EntityTrace, ID: t.ID, Message: "has no items",
+				Hint: "link its wires to it or delete the trace",
+				Fix:  &Change{Action: ActionDeleteTrace, Entity: EntityTrace, ID: t.ID}})
+		}
+	}
+	return out
+}
+
+func hasItems(t katapultpro.Trace) bool {
+	for _, item := range t.Items {
+		for _, els := range item {
+			for _, on := range els {
+				if on {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Report is the result of linting a job.
+type Report struct {
+	JobID    string    `json:"job_id"`
+	Findings []Finding `json:"findings"`
+}
+
+// Count returns the number of findings with the given severity.
+func (r *Report) Count(s Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == s {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteText writes a summary line followed by one line per finding.
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "lint job %s: %d error(s), %d warning(s), %d info\n",
+		r.JobID, r.Count(SeverityError), r.Count(SeverityWarning), r.Count(SeverityInfo))
+	for _, f := range r.Findings {
+		b.WriteString(f.String())
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DefaultConcurrency is the number of photos whose elements are fetched at once when
+// Linter.Concurrency is zero.
+const DefaultConcurrency = 4
+
+// Linter fetches a job and lints it.
+type Linter struct {
+	Job    *katapultpro.JobScope
+	Config *Config
+	// Concurrency bounds the element listings in flight. Zero means DefaultConcurrency.
+	Concurrency int
+}
+
+// Run fetches the job's nodes, connections, photos, traces, and photo elements, and lints them.
+// Element listings are skipped when the element-no-height check is disabled.
+func (l *Linter) Run(ctx context.Context) (*Report, error) {
+	d, err := l.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Report{JobID: l.Job.JobID(), Findings: Lint(d, l.Config)}, nil
+}
+
+func (l *Linter) load(ctx context.Context) (*Data, error) {
+	var d Data
+	var err error
+	if d.Nodes, err = l.Job.Nodes().List(ctx); err != nil {
+		return nil, fmt.Errorf("lint: list nodes: %w", err)
+	}
+	if d.Connections, err = l.Job.Connections().List(ctx); err != nil {
+		return nil, fmt.Errorf("lint: list connections: %w", err)
+	}
+	if d.Photos, err = l.Job.Photos().List(ctx); err != nil {
+		return nil, fmt.Errorf("lint: list photos: %w", err)
+	}
+	if d.Traces, err = l.Job.Traces().List(ctx); err != nil {
+		return nil, fmt.Errorf("lint: list traces: %w", err)
+	}
+	if l.Config != nil && containsString(l.Config.Disable, "element-no-height") {
+		return &d, nil
+	}
+
+	n := l.Concurrency
+	if n <= 0 {
+		n = DefaultConcurrency
+	}
+	ids := make([]string, len(d.Photos))
+	for i, p := range d.Photos {
+		ids[i] = p.ID
+	}
+	if d.Elements, err = l.Job.Photos().ListAllElements(ctx, ids, n); err != nil {
+		return nil, fmt.Errorf("lint: %w", err)
+	}
+	return &d, nil
+}
+
+func nodeIndex(nodes []katapultpro.Node) map[string]*katapultpro.Node {
+	m := make(map[string]*katapultpro.Node, len(nodes))
+	for i := range nodes {
+		m[nodes[i].ID] = &nodes[i]
+	}
+	return m
+}
+
+func sortedNodes(nodes []katapultpro.Node) []katapultpro.Node {
+	out := append([]katapultpro.Node(nil), nodes...)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func sortedConnections(conns []katapultpro.Connection) []katapultpro.Connection {
+	out := append([]katapultpro.Connection(nil), conns...)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func sortedPhotos(photos []katapultpro.Photo) []katapultpro.Photo {
+	out := append([]katapultpro.Photo(nil), photos...)
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// firstValue returns the value of the lowest instance ID as a string, or "".
+func firstValue(instances map[string]interface{}) string {
+	for _, k := range sortedKeys(instances) {
+		if v := instances[k]; v != nil {
+			if s := fmt.Sprint(v); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+func hasPosition(lat, lon float64) bool { return lat != 0 || lon != 0 }
+
+// segmentDistance returns the distance in meters from a point to the segment between two others,
+// using a local flat projection, which is accurate over span lengths.
+func segmentDistance(lat, lon, lat1, lon1, lat2, lon2 float64) float64 {
+	const metersPerDegree = 111194.9 // Mean earth radius × π/180
+	k := math.Cos(lat1 * math.Pi / 180)
+	x, y := (lon-lon1)*k*metersPerDegree, (lat-lat1)*metersPerDegree
+	x2, y2 := (lon2-lon1)*k*metersPerDegree, (lat2-lat1)*metersPerDegree
+	t := 0.0
+	if l2 := x2*x2 + y2*y2; l2 > 0 {
+		t = math.Max(0, math.Min(1, (x*x2+y*y2)/l2))
+	}
+	return math.Hypot(x-t*x2, y-t*y2)
+}
@@ -0,0 +1,181 @@
+package lint_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/lint"
+)
+
+func testData() *lint.Data {
+	return &lint.Data{
+		Nodes: []katapultpro.Node{
+			{ID: "n1", Latitude: 40, Longitude: -111, Attributes: katapultpro.EntityAttributeList{"scid": {"-a": "1"}, "pole_tag": {"-a": "T1"}},
+				Photos: katapultpro.PhotoAssociationMap{"p1": {Association: "main"}}},
+			{ID: "n2", Latitude: 40, Longitude: -110.999, Attributes: katapultpro.EntityAttributeList{"scid": {"-a": "1"}}},
+		},
+		Connections: []katapultpro.Connection{
+			{ID: "c1", NodeID1: "n1", NodeID2: "n2", Sections: map[string]katapultpro.EmbeddedSection{
+				"s-on":  {Latitude: 40.00005, Longitude: -110.9995},
+				"s-off": {Latitude: 40.001, Longitude: -110.9995}, // About 111m north of the span
+			}},
+			{ID: "c2", NodeID1: "n1", NodeID2: "gone"},
+		},
+		Photos: []katapultpro.Photo{
+			{ID: "p1", PhotofirstData: &katapultpro.PhotofirstData{AnchorCalibration: map[string]katapultpro.AnchorCalibration{
+				"a-low":    {RoutineInstanceID: "r1", Score: 0.2, Height: 20},
+				"a-good":   {RoutineInstanceID: "r1", Score: 0.9, Height: 25},
+				"a-manual": {Height: 10},
+			}}},
+			{ID: "p2", StickAlign: &katapultpro.StickAlign{ErrorMessages: []katapultpro.StickAlignError{{Type: "stickNotVertical"}}}},
+		},
+		Elements: map[string][]katapultpro.PhotoElement{
+			"p1": {{ID: "e1", ElementType: "wire"}, {ID: "e2", ElementType: "wire", ManualHeight: "20-0"}},
+		},
+		Traces: []katapultpro.Trace{
+			{ID: "t1", Items: map[string]katapultpro.TraceItem{"p1": {"wire": {"e2": true}}}},
+			{ID: "t2"},
+		},
+	}
+}
+
+func TestLint(t *testing.T) {
+	cfg := &lint.Config{RequiredNodeAttributes: []string{"pole_tag"}}
+	var got []string
+	for _, f := range lint.Lint(testData(), cfg) {
+		got = append(got, string(f.Severity)+" "+f.Check+" "+f.Entity+" "+f.ID)
+	}
+	want := []string{
+		"error node-required-attributes node n2",
+		"error duplicate-scid node n1",
+		"error duplicate-scid node n2",
+		"error connection-missing-node connection c2",
+		"warning section-outside-corridor section s-off",
+		"warning unassociated-photo photo p2",
+		"warning low-anchor-score anchor a-low",
+		"warning stick-align-errors photo p2",
+		"warning element-no-height element e1",
+		"warning trace-no-items trace t2",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLint_Config(t *testing.T) {
+	cfg, err := lint.LoadConfig(strings.NewReader(`{"disable":["duplicate-scid","unassociated-photo"],"severity":{"trace-no-items":"info"},"corridor_width":200}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	findings := lint.Lint(testData(), cfg)
+	for _, f := range findings {
+		if f.Check == "duplicate-scid" || f.Check == "unassociated-photo" || f.Check == "section-outside-corridor" {
+			t.Errorf("unexpected finding %v", f)
+		}
+	}
+	if last := findings[len(findings)-1]; last.Check != "trace-no-items" || last.Severity != lint.SeverityInfo {
+		t.Errorf("last finding = %v", last)
+	}
+
+	for _, bad := range []string{`{"disable":["nope"]}`, `{"severity":{"trace-no-items":"fatal"}}`, `{"bogus":1}`} {
+		if _, err := lint.LoadConfig(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadConfig(%s): expected error", bad)
+		}
+	}
+}
+
+func TestLinter_Run(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Lint"})
+	srv.AddPhoto(job.ID, katapultpro.Photo{})
+	srv.AddTrace(job.ID, katapultpro.Trace{TraceType: "cable"})
+
+	report, err := (&lint.Linter{Job: srv.Client().Job(job.ID)}).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(lint.SeverityWarning) != 2 || report.Count(lint.SeverityError) != 0 {
+		t.Errorf("findings = %v", report.Findings)
+	}
+	var buf bytes.Buffer
+	if err := report.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "lint job "+job.ID+": 0 error(s), 2 warning(s)") {
+		t.Errorf("text:\n%s", buf.String())
+	}
+}
+
+func TestFixer(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Fix"})
+	later := srv.AddPhoto(job.ID, katapultpro.Photo{DateTaken: 200})
+	first := srv.AddPhoto(job.ID, katapultpro.Photo{DateTaken: 100})
+	stale := srv.AddPhoto(job.ID, katapultpro.Photo{AssociatedLocations: map[string]string{"deleted-node": "node"}})
+	node := srv.AddNode(job.ID, katapultpro.Node{
+		Attributes: katapultpro.EntityAttributeList{"scid": {"-a": "7", "-b": "7"}},
+		Photos:     katapultpro.PhotoAssociationMap{later.ID: {Association: true}, first.ID: {Association: true}},
+	})
+	trace := srv.AddTrace(job.ID, katapultpro.Trace{TraceType: "cable"})
+	scope := srv.Client().Job(job.ID)
+	ctx := context.Background()
+
+	var audit bytes.Buffer
+	fixer := &lint.Fixer{Job: scope, Audit: &audit}
+	plan, err := fixer.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range plan.Changes {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"remove scid instance(s) -b from node " + node.ID,
+		"remove association of photo " + stale.ID + " with node deleted-node",
+		"associate photo " + first.ID + " with node " + node.ID + " as main",
+		"delete trace " + trace.ID,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("plan:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	entries, err := fixer.Apply(ctx, plan)
+	if err != nil || len(entries) != 4 || entries[0].Status != lint.StatusDryRun {
+		t.Fatalf("dry run: %+v, %v", entries, err)
+	}
+	if _, err := scope.Traces().Get(ctx, trace.ID); err != nil {
+		t.Fatalf("dry run deleted the trace: %v", err)
+	}
+
+	audit.Reset()
+	fixer.Commit = true
+	if _, err = fixer.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(audit.String()), "\n"); len(lines) != 4 || !strings.Contains(lines[3], `"status":"applied"`) {
+		t.Errorf("audit log:\n%s", audit.String())
+	}
+	var unassociated bool
+	for _, r := range srv.Requests() {
+		if r.Path == "/v3/jobs/"+job.ID+"/photos/"+stale.ID+"/associate" {
+			unassociated = strings.Contains(string(r.Body), `"association_value":null`) && strings.Contains(string(r.Body), `"node_id":"deleted-node"`)
+		}
+	}
+	if !unassociated {
+		t.Error("stale association not removed with a null association_value")
+	}
+	if plan, err := fixer.Plan(ctx); err != nil || len(plan.Changes) != 0 {
+		t.Errorf("plan after fixing = %+v, %v", plan, err)
+	}
+	n, err := scope.Nodes().Get(ctx, node.ID)
+	if err != nil || len(n.Attributes["scid"]) != 1 || n.Photos[first.ID].Association != "main" {
+		t.Errorf("node after fixing = %+v, %v", n, err)
+	}
+}
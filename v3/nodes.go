@@ -27,7 +27,7 @@ func (c *Client) UpdateNode(ctx context.Context, jobID, nodeID string, req *Upda
 	return nodes.NewClient(c, jobID).Update(ctx, nodeID, req, opts)
 }
 
-// UploadNodePhoto uploads a photo (image/jpeg) and associates it to the node (v3).
+// UploadNodePhoto uploads a photo and associates it to the node (v3).
 func (c *Client) UploadNodePhoto(ctx context.Context, jobID, nodeID string, imageData io.Reader, opts *UploadNodePhotoOptions) (*Photo, error) {
 	return nodes.NewClient(c, jobID).UploadPhoto(ctx, nodeID, imageData, opts)
 }
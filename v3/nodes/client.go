@@ -62,7 +62,7 @@ func (s *NodeScope) Update(ctx context.Context, req *UpdateNodeRequest, opts *Up
 	return &node, nil
 }
 
-// UploadPhoto uploads a photo (image/jpeg) and associates it to the node.
+// UploadPhoto uploads a photo and associates it to the node.
 func (s *NodeScope) UploadPhoto(ctx context.Context, imageData io.Reader, opts *UploadNodePhotoOptions) (*photos.Photo, error) {
 	path := "v3/jobs/" + s.jobID + "/nodes/" + s.nodeID + "/photos"
 	var q url.Values
@@ -70,11 +70,11 @@ func (s *NodeScope) UploadPhoto(ctx context.Context, imageData io.Reader, opts *
 		q = url.Values{}
 		q.Set("association_value", opts.AssociationValue.String())
 	}
-	var photo photos.Photo
-	if err := s.do.DoWithBody(ctx, http.MethodPost, path, q, "image/jpeg", imageData, &photo); err != nil {
-		return nil, err
+	var upload *photos.UploadOptions
+	if opts != nil {
+		upload = &opts.UploadOptions
 	}
-	return &photo, nil
+	return photos.Send(ctx, s.do, path, q, imageData, upload)
 }
 
 // Delete deletes the node.
@@ -128,7 +128,7 @@ func (c *Client) Update(ctx context.Context, nodeID string, req *UpdateNodeReque
 	return &node, nil
 }
 
-// UploadPhoto uploads a photo (image/jpeg) and associates it to the node (v3).
+// UploadPhoto uploads a photo and associates it to the node (v3).
 func (c *Client) UploadPhoto(ctx context.Context, nodeID string, imageData io.Reader, opts *UploadNodePhotoOptions) (*photos.Photo, error) {
 	path := "v3/jobs/" + c.jobID + "/nodes/" + nodeID + "/photos"
 	var q url.Values
@@ -136,11 +136,11 @@ func (c *Client) UploadPhoto(ctx context.Context, nodeID string, imageData io.Re
 		q = url.Values{}
 		q.Set("association_value", opts.AssociationValue.String())
 	}
-	var photo photos.Photo
-	if err := c.do.DoWithBody(ctx, http.MethodPost, path, q, "image/jpeg", imageData, &photo); err != nil {
-		return nil, err
+	var upload *photos.UploadOptions
+	if opts != nil {
+		upload = &opts.UploadOptions
 	}
-	return &photo, nil
+	return photos.Send(ctx, c.do, path, q, imageData, upload)
 }
 
 // Delete deletes the specified node (v3).
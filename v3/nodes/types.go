@@ -39,7 +39,9 @@ type UpdateNodeOptions struct {
 	OnlyIfExists bool
 }
 
-// UploadNodePhotoOptions are optional query parameters for UploadNodePhoto.
+// UploadNodePhotoOptions are optional parameters for UploadNodePhoto. The embedded UploadOptions
+// add content type detection, progress, size verification, and retries.
 type UploadNodePhotoOptions struct {
 	AssociationValue photos.PhotoAssociationQuery
+	photos.UploadOptions
 }
@@ -0,0 +1,239 @@
+// Package photomirror mirrors the photos of a job to a local directory.
+//
+//	m := &photomirror.Mirror{Job: client.Job("job-123"), Dir: "/data/job-123", Size: katapultpro.PhotoSizeFull}
+//	report, err := m.Run(ctx)
+//	_ = report.WriteText(os.Stdout)
+//
+// Each photo is stored as <photo ID><ext> (the extension follows the image type). Downloads are
+// written to <photo ID>.part first and renamed when complete; a later run skips completed photos
+// and resumes partial files with a range request, so an interrupted mirror can simply be rerun.
+package photomirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// DefaultConcurrency is the number of downloads in flight when Mirror.Concurrency is zero.
+const DefaultConcurrency = 4
+
+// partSuffix marks an incomplete download.
+const partSuffix = ".part"
+
+// Status is the outcome for one photo.
+type Status string
+
+const (
+	StatusDownloaded Status = "downloaded" // Downloaded from the start.
+	StatusResumed    Status = "resumed"    // Completed from a partial file.
+	StatusSkipped    Status = "skipped"    // Already mirrored.
+	StatusFailed     Status = "failed"     // Left as a partial file, if any bytes arrived.
+)
+
+// Result describes what happened to one photo.
+type Result struct {
+	PhotoID string `json:"photo_id"`
+	Path    string `json:"path,omitempty"`
+	Status  Status `json:"status"`
+	Bytes   int64  `json:"bytes"` // Bytes transferred in this run
+	Error   string `json:"error,omitempty"`
+}
+
+// Report is the result of a run, one entry per photo in the job's listing order.
+type Report struct {
+	JobID   string                `json:"job_id"`
+	Size    katapultpro.PhotoSize `json:"size"`
+	Results []Result              `json:"results"`
+}
+
+// Failed returns the photos that could not be mirrored.
+func (r *Report) Failed() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Status == StatusFailed {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// WriteText writes a summary line followed by one line per failed photo.
+func (r *Report) WriteText(w io.Writer) error {
+	counts := map[Status]int{}
+	var total int64
+	for _, res := range r.Results {
+		counts[res.Status]++
+		total += res.Bytes
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "mirror of job %s (%s): %d photo(s), %d downloaded, %d resumed, %d skipped, %d failed, %d bytes\n",
+		r.JobID, r.Size, len(r.Results), counts[StatusDownloaded], counts[StatusResumed], counts[StatusSkipped], counts[StatusFailed], total)
+	for _, res := range r.Failed() {
+		fmt.Fprintf(&b, "failed %s: %s\n", res.PhotoID, res.Error)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Mirror downloads every photo of a job into Dir.
+type Mirror struct {
+	Job *katapultpro.JobScope
+	Dir string
+	// Size is the image variant to download. Empty means PhotoSizeFull.
+	Size katapultpro.PhotoSize
+	// Concurrency bounds the number of downloads in flight. Zero means DefaultConcurrency.
+	Concurrency int
+	// OnResult, if set, is called after each photo is processed (serially, in completion order).
+	OnResult func(Result)
+}
+
+// Run mirrors the job's photos. It returns an error only if the photos cannot be listed or the
+// directory cannot be created; per-photo failures are in the report.
+func (m *Mirror) Run(ctx context.Context) (*Report, error) {
+	if m.Job == nil {
+		return nil, errors.New("photomirror: Mirror.Job is nil")
+	}
+	size := m.Size
+	if size == "" {
+		size = katapultpro.PhotoSizeFull
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("photomirror: %w", err)
+	}
+	list, err := m.Job.Photos().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("photomirror: list photos: %w", err)
+	}
+
+	report := &Report{JobID: m.Job.JobID(), Size: size, Results: make([]Result, len(list))}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	workers := m.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				res := m.fetch(ctx, list[i].ID, size)
+				mu.Lock()
+				report.Results[i] = res
+				if m.OnResult != nil {
+					m.OnResult(res)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range list {
+		if ctx.Err() != nil {
+			break
+		}
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return report, ctx.Err()
+}
+
+// fetch mirrors one photo.
+func (m *Mirror) fetch(ctx context.Context, photoID string, size katapultpro.PhotoSize) Result {
+	res := Result{PhotoID: photoID}
+	if photoID == "" || strings.ContainsAny(photoID, `/\`) || photoID == "." || photoID == ".." {
+		res.Status, res.Error = StatusFailed, "invalid photo ID"
+		return res
+	}
+	if done := completed(m.Dir, photoID); done != "" {
+		res.Path, res.Status = done, StatusSkipped
+		return res
+	}
+	part := filepath.Join(m.Dir, photoID+partSuffix)
+	var offset int64
+	if fi, err := os.Stat(part); err == nil {
+		offset = fi.Size()
+	}
+	n, img, err := m.download(ctx, photoID, size, part, offset)
+	res.Bytes = n
+	if err != nil {
+		res.Status, res.Error = StatusFailed, err.Error()
+		return res
+	}
+	res.Path = filepath.Join(m.Dir, photoID+extension(img.ContentType))
+	if err := os.Rename(part, res.Path); err != nil {
+		res.Status, res.Error = StatusFailed, err.Error()
+		return res
+	}
+	res.Status = StatusDownloaded
+	if img.Offset > 0 {
+		res.Status = StatusResumed
+	}
+	return res
+}
+
+// download appends the image to the partial file from offset (or rewrites it if the server sends
+// the whole image) and checks the final size when the server reports it.
+func (m *Mirror) download(ctx context.Context, photoID string, size katapultpro.PhotoSize, part string, offset int64) (int64, *katapultpro.PhotoImage, error) {
+	img, err := m.Job.Photos().Photo(photoID).OpenImage(ctx, size, offset)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer img.Close()
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if img.Offset == 0 {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return 0, img, err
+	}
+	n, err := io.Copy(f, img)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return n, img, err
+	}
+	if got := img.Offset + n; img.Size >= 0 && got != img.Size {
+		return n, img, fmt.Errorf("short download: got %d of %d bytes", got, img.Size)
+	}
+	return n, img, nil
+}
+
+// completed returns the path of a finished download of the photo, or "".
+func completed(dir, photoID string) string {
+	matches, _ := filepath.Glob(filepath.Join(dir, globEscape(photoID)+".*"))
+	for _, p := range matches {
+		if !strings.HasSuffix(p, partSuffix) {
+			return p
+		}
+	}
+	return ""
+}
+
+func globEscape(s string) string {
+	r := strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return r.Replace(s)
+}
+
+func extension(contentType string) string {
+	switch strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]) {
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	case "image/heic":
+		return ".heic"
+	}
+	return ".jpg"
+}
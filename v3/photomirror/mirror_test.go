@@ -0,0 +1,83 @@
+package photomirror_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/photomirror"
+)
+
+func TestMirror_DownloadsResumesAndSkips(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Mirror"})
+	a := srv.AddPhoto(job.ID, katapultpro.Photo{Filename: "a.jpg"})
+	b := srv.AddPhoto(job.ID, katapultpro.Photo{Filename: "b.jpg"})
+	c := srv.AddPhoto(job.ID, katapultpro.Photo{Filename: "c.jpg"})
+	imgA := bytes.Repeat([]byte("a"), 1000)
+	imgB := bytes.Repeat([]byte("b"), 1000)
+	srv.AddPhotoImage(job.ID, a.ID, imgA)
+	srv.AddPhotoImage(job.ID, b.ID, imgB)
+	srv.AddPhotoImage(job.ID, c.ID, []byte("c"))
+
+	dir := t.TempDir()
+	// b was interrupted after 400 bytes; c was mirrored by an earlier run.
+	if err := os.WriteFile(filepath.Join(dir, b.ID+".part"), imgB[:400], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, c.ID+".jpg"), []byte("c"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &photomirror.Mirror{Job: srv.Client().Job(job.ID), Dir: dir, Concurrency: 2}
+	report, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]photomirror.Result{}
+	for _, res := range report.Results {
+		got[res.PhotoID] = res
+	}
+	if r := got[a.ID]; r.Status != photomirror.StatusDownloaded || r.Bytes != 1000 {
+		t.Errorf("a: %+v", r)
+	}
+	if r := got[b.ID]; r.Status != photomirror.StatusResumed || r.Bytes != 600 {
+		t.Errorf("b: %+v", r)
+	}
+	if r := got[c.ID]; r.Status != photomirror.StatusSkipped || r.Bytes != 0 {
+		t.Errorf("c: %+v", r)
+	}
+	for id, want := range map[string][]byte{a.ID: imgA, b.ID: imgB} {
+		data, err := os.ReadFile(filepath.Join(dir, id+".jpg"))
+		if err != nil || !bytes.Equal(data, want) {
+			t.Errorf("%s: read %d bytes, err %v", id, len(data), err)
+		}
+	}
+	if parts, _ := filepath.Glob(filepath.Join(dir, "*.part")); len(parts) != 0 {
+		t.Errorf("partial files left: %v", parts)
+	}
+
+	var text strings.Builder
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "3 photo(s), 1 downloaded, 1 resumed, 1 skipped, 0 failed, 1600 bytes") {
+		t.Errorf("report text: %q", text.String())
+	}
+
+	report, err = m.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range report.Results {
+		if res.Status != photomirror.StatusSkipped {
+			t.Errorf("second run: %+v", res)
+		}
+	}
+}
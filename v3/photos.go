@@ -17,11 +17,75 @@ func (c *Client) GetPhoto(ctx context.Context, jobID, photoID string) (*Photo, e
 	return photos.NewClient(c, jobID).Get(ctx, photoID)
 }
 
-// UploadJobPhoto uploads a photo (image/jpeg) to the job (v3).
+// UploadJobPhoto uploads a photo to the job (v3). The content type is detected from the data.
 func (c *Client) UploadJobPhoto(ctx context.Context, jobID string, imageData io.Reader) (*Photo, error) {
 	return photos.NewClient(c, jobID).Upload(ctx, imageData)
 }
 
+// UploadJobPhotoWithOptions uploads a photo to the job with progress, verification, and retries (v3).
+// With opts.Open set, imageData may be nil.
+func (c *Client) UploadJobPhotoWithOptions(ctx context.Context, jobID string, imageData io.Reader, opts *PhotoUploadOptions) (*Photo, error) {
+	return photos.NewClient(c, jobID).UploadWithOptions(ctx, imageData, opts)
+}
+
+// OpenPhotoFile returns a PhotoOpener for the file at path, for retryable uploads.
+func OpenPhotoFile(path string) PhotoOpener {
+	return photos.OpenFile(path)
+}
+
+// OpenPhotoReaderAt returns a PhotoOpener that reads size bytes of r from the start, for retryable uploads.
+func OpenPhotoReaderAt(r io.ReaderAt, size int64) PhotoOpener {
+	return photos.OpenReaderAt(r, size)
+}
+
+// ParseHeight parses a height in feet-inches ("16-6"), feet and inch marks (16' 6-1/2"), explicit
+// units ("198in", "5.03m"), or decimal feet ("16.5"). See photos.ParseHeight.
+func ParseHeight(s string) (Height, error) {
+	return photos.ParseHeight(s)
+}
+
+// ParseManualHeight parses the strict feet-inches notation of PhotoElement.ManualHeight ("16-6").
+func ParseManualHeight(s string) (Height, error) {
+	return photos.ParseManualHeight(s)
+}
+
+// Inches returns a Height of in inches, the unit of MeasuredHeight fields.
+func Inches(in float64) Height { return photos.Inches(in) }
+
+// Feet returns a Height of ft decimal feet, the unit of calibration anchor heights.
+func Feet(ft float64) Height { return photos.Feet(ft) }
+
+// FeetInches returns a Height of ft feet plus in inches.
+func FeetInches(ft, in float64) Height { return photos.FeetInches(ft, in) }
+
+// Meters returns a Height of m meters.
+func Meters(m float64) Height { return photos.Meters(m) }
+
+// LookupElementType returns the catalog entry for an element type.
+func LookupElementType(t ElementType) (ElementSpec, bool) {
+	return photos.LookupElementType(t)
+}
+
+// NewElement returns a create request for an element of type t, checked against the element catalog.
+func NewElement(t ElementType, opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	return photos.NewElement(t, opts)
+}
+
+// NewWireElement returns a create request for a wire on the given trace.
+func NewWireElement(traceID string, opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	return photos.NewWireElement(traceID, opts)
+}
+
+// NewPoleTopElement returns a create request for a pole top; extension marks a pole top extension.
+func NewPoleTopElement(extension bool, opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	return photos.NewPoleTopElement(extension, opts)
+}
+
+// NewPoleHeightElement returns a create request for a pole height measurement.
+func NewPoleHeightElement(opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	return photos.NewPoleHeightElement(opts)
+}
+
 // AssociatePhoto associates (or unassociates) the photo to a node or section (v3).
 func (c *Client) AssociatePhoto(ctx context.Context, jobID, photoID string, req *AssociatePhotoRequest) error {
 	return photos.NewClient(c, jobID).Associate(ctx, photoID, req)
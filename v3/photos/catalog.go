@@ -0,0 +1,182 @@
+package photos
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ElementType is the type of a photo element: the photofirst_data key the element is stored under.
+// Types not in the catalog are valid and pass through unchanged.
+type ElementType string
+
+const (
+	// ElementTypeWire is a wire measurement. Wires belong to a trace.
+	ElementTypeWire ElementType = "wire"
+	// ElementTypePoleTop marks the top of the pole, optionally on a pole top extension.
+	ElementTypePoleTop ElementType = "pole_top"
+	// ElementTypePoleHeight marks a pole height measurement.
+	ElementTypePoleHeight ElementType = "poleHeight"
+)
+
+// reservedElementTypes are photofirst_data keys that are not element types.
+var reservedElementTypes = map[ElementType]bool{"anchor_calibration": true, "_editors": true}
+
+// String returns the API value.
+func (t ElementType) String() string { return string(t) }
+
+// IsKnown reports whether t is in the catalog.
+func (t ElementType) IsKnown() bool {
+	_, ok := elementCatalog[t]
+	return ok
+}
+
+// AttributeKind is the JSON type of a known element attribute.
+type AttributeKind string
+
+const (
+	AttributeBool   AttributeKind = "bool"
+	AttributeString AttributeKind = "string"
+	AttributeNumber AttributeKind = "number"
+)
+
+// ElementSpec describes what the SDK knows about an element type.
+type ElementSpec struct {
+	Type ElementType
+	// RequiresTrace is set for elements that must belong to a trace.
+	RequiresTrace bool
+	// Attributes lists the attributes with a known type. Others are passed through unchecked.
+	Attributes map[string]AttributeKind
+}
+
+var elementCatalog = map[ElementType]ElementSpec{
+	ElementTypeWire:       {Type: ElementTypeWire, RequiresTrace: true},
+	ElementTypePoleTop:    {Type: ElementTypePoleTop},
+	ElementTypePoleHeight: {Type: ElementTypePoleHeight},
+}
+
+// LookupElementType returns the catalog entry for t.
+func LookupElementType(t ElementType) (ElementSpec, bool) {
+	spec, ok := elementCatalog[t]
+	return spec, ok
+}
+
+// ElementTypes returns the catalog's element types, sorted.
+func ElementTypes() []ElementType {
+	out := make([]ElementType, 0, len(elementCatalog))
+	for t := range elementCatalog {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// Type returns the element's type, falling back to the type in its context.
+func (e PhotoElement) Type() ElementType {
+	if e.ElementType != "" {
+		return ElementType(e.ElementType)
+	}
+	if e.Context != nil {
+		return ElementType(e.Context.Type)
+	}
+	return ""
+}
+
+// ElementType returns the context's element type.
+func (c PhotoElementContext) ElementType() ElementType { return ElementType(c.Type) }
+
+// ElementError is returned by the element constructors when a request would be invalid.
+type ElementError struct {
+	Type    ElementType
+	Field   string
+	Problem string
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("photos: %s element: %s %s", e.Type, e.Field, e.Problem)
+}
+
+// ElementOptions are the optional fields of a new element.
+type ElementOptions struct {
+	PixelSelection *PixelSelection
+	// ManualHeight, if set, is sent in feet-inches notation. It must not be negative.
+	ManualHeight *Height
+	ParentID     string
+	TraceID      string
+	// Attributes are sent as the element's flat attribute map. Attributes the catalog knows are
+	// type-checked; others are sent as given. Element fields such as pole_top_extension are not
+	// attributes and are rejected.
+	Attributes map[string]interface{}
+}
+
+// NewElement returns a create request for an element of type t after checking it against the
+// catalog. Types not in the catalog are accepted with any attributes.
+func NewElement(t ElementType, opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	if t == "" || reservedElementTypes[t] {
+		return nil, &ElementError{Type: t, Field: "element_type", Problem: "is not a valid element type"}
+	}
+	spec := elementCatalog[t]
+	if spec.RequiresTrace && opts.TraceID == "" {
+		return nil, &ElementError{Type: t, Field: "trace_id", Problem: "is required"}
+	}
+	for name, v := range opts.Attributes {
+		if photoElementFields[name] {
+			return nil, &ElementError{Type: t, Field: "attribute " + name, Problem: "is an element field, not an attribute"}
+		}
+		if kind, ok := spec.Attributes[name]; ok && !kind.accepts(v) {
+			return nil, &ElementError{Type: t, Field: "attribute " + name, Problem: fmt.Sprintf("must be a %s, got %T", kind, v)}
+		}
+	}
+	req := &CreatePhotoElementRequest{
+		ElementType:    t.String(),
+		PixelSelection: opts.PixelSelection,
+		Attributes:     opts.Attributes,
+		ParentID:       opts.ParentID,
+		TraceID:        opts.TraceID,
+	}
+	if opts.ManualHeight != nil {
+		if *opts.ManualHeight < 0 {
+			return nil, &ElementError{Type: t, Field: "manual_height", Problem: "must not be negative"}
+		}
+		req.ManualHeight = opts.ManualHeight.String()
+	}
+	return req, nil
+}
+
+// NewWireElement returns a create request for a wire on the given trace.
+func NewWireElement(traceID string, opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	opts.TraceID = traceID
+	return NewElement(ElementTypeWire, opts)
+}
+
+// NewPoleTopElement returns a create request for a pole top; extension marks a pole top extension.
+func NewPoleTopElement(extension bool, opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	req, err := NewElement(ElementTypePoleTop, opts)
+	if err != nil {
+		return nil, err
+	}
+	req.PoleTopExtension = extension
+	return req, nil
+}
+
+// NewPoleHeightElement returns a create request for a pole height measurement.
+func NewPoleHeightElement(opts ElementOptions) (*CreatePhotoElementRequest, error) {
+	return NewElement(ElementTypePoleHeight, opts)
+}
+
+func (k AttributeKind) accepts(v interface{}) bool {
+	switch k {
+	case AttributeBool:
+		_, ok := v.(bool)
+		return ok
+	case AttributeString:
+		_, ok := v.(string)
+		return ok
+	case AttributeNumber:
+		switch v.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package photos
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewElement_Validation(t *testing.T) {
+	h := FeetInches(22, 3)
+	req, err := NewWireElement("trace-1", ElementOptions{ManualHeight: &h, PixelSelection: &PixelSelection{PercentX: 10, PercentY: 20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.ElementType != "wire" || req.TraceID != "trace-1" || req.ManualHeight != "22-3" {
+		t.Errorf("wire request = %+v", req)
+	}
+
+	var elErr *ElementError
+	if _, err := NewWireElement("", ElementOptions{}); !errors.As(err, &elErr) || elErr.Field != "trace_id" {
+		t.Errorf("wire without trace: got %v", err)
+	}
+	if _, err := NewElement("anchor_calibration", ElementOptions{}); !errors.As(err, &elErr) {
+		t.Errorf("reserved type: got %v", err)
+	}
+	if _, err := NewElement(ElementTypePoleTop, ElementOptions{Attributes: map[string]interface{}{"pole_top_extension": true}}); !errors.As(err, &elErr) {
+		t.Errorf("element field as attribute: got %v", err)
+	}
+	neg := Inches(-1)
+	if _, err := NewPoleHeightElement(ElementOptions{ManualHeight: &neg}); err == nil {
+		t.Error("negative manual height: want error")
+	}
+
+	attrs := map[string]interface{}{"note": "leaning"}
+	req, err = NewPoleTopElement(true, ElementOptions{Attributes: attrs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !req.PoleTopExtension || !reflect.DeepEqual(req.Attributes, attrs) {
+		t.Errorf("pole top request = %+v", req)
+	}
+
+	req, err = NewElement("guy_attachment", ElementOptions{Attributes: map[string]interface{}{"anything": 1}})
+	if err != nil || req.ElementType != "guy_attachment" {
+		t.Errorf("unknown type: %+v, %v", req, err)
+	}
+	if ElementType("guy_attachment").IsKnown() || !ElementTypeWire.IsKnown() {
+		t.Error("IsKnown")
+	}
+	if got := ElementTypes(); !reflect.DeepEqual(got, []ElementType{ElementTypePoleHeight, ElementTypePoleTop, ElementTypeWire}) {
+		t.Errorf("ElementTypes() = %v", got)
+	}
+}
+
+func TestPhotoElement_UnmarshalJSON_KeepsRootAttributes(t *testing.T) {
+	body := []byte(`{
+		"id": "el-1",
+		"_context": {"id": "el-1", "path": "crossarm/el-1", "type": "crossarm"},
+		"pixel_selection": [{"percentX": 1, "percentY": 2}],
+		"attributes": {"owner": "PowerCo"},
+		"owner": "ignored, attributes wins",
+		"arm_length": 8,
+		"_measured_height": 300
+	}`)
+	var e PhotoElement
+	if err := json.Unmarshal(body, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type() != "crossarm" || e.MeasuredHeight != 300 || len(e.PixelSelection) != 1 {
+		t.Errorf("element = %+v", e)
+	}
+	want := map[string]interface{}{"owner": "PowerCo", "arm_length": float64(8)}
+	if !reflect.DeepEqual(e.Attributes, want) {
+		t.Errorf("attributes = %v, want %v", e.Attributes, want)
+	}
+
+	out, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again PhotoElement
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, e) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", again, e)
+	}
+}
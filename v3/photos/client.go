@@ -2,9 +2,11 @@ package photos
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/request"
 )
@@ -198,14 +200,10 @@ func (c *Client) Get(ctx context.Context, photoID string) (*Photo, error) {
 	return &photo, nil
 }
 
-// Upload uploads a photo (image/jpeg) to the job (v3).
+// Upload uploads a photo to the job (v3). The content type is detected from the data (JPEG, PNG,
+// or HEIC; image/jpeg otherwise). Use UploadWithOptions for progress, verification, and retries.
 func (c *Client) Upload(ctx context.Context, imageData io.Reader) (*Photo, error) {
-	path := "v3/jobs/" + c.jobID + "/photos"
-	var photo Photo
-	if err := c.do.DoWithBody(ctx, http.MethodPost, path, nil, "image/jpeg", imageData, &photo); err != nil {
-		return nil, err
-	}
-	return &photo, nil
+	return c.UploadWithOptions(ctx, imageData, nil)
 }
 
 // Associate associates (or unassociates) the photo to a node or section (v3).
@@ -224,6 +222,62 @@ func (c *Client) ListElements(ctx context.Context, photoID string) ([]PhotoEleme
 	return out, nil
 }
 
+// ListAllElements lists the elements of each photo, with at most concurrency listings in flight
+// (at least one). The result maps photo IDs to their elements. After the first failure, or once ctx
+// is done, no further listings are started and the error is returned.
+func (c *Client) ListAllElements(ctx context.Context, photoIDs []string, concurrency int) (map[string][]PhotoElement, error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := make(map[string][]PhotoElement, len(photoIDs))
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+	launched := 0
+	for _, id := range photoIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		launched++
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			els, err := c.ListElements(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("photos: list elements of photo %s: %w", id, err)
+					cancel()
+				}
+				return
+			}
+			out[id] = els
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if launched < len(photoIDs) {
+		return nil, parent.Err()
+	}
+	return out, nil
+}
+
 // GetElement returns the specified photo element (v3).
 func (c *Client) GetElement(ctx context.Context, photoID, elementID string) (*PhotoElement, error) {
 	path := "v3/jobs/" + c.jobID + "/photos/" + photoID + "/photo_elements/" + elementID
@@ -0,0 +1,154 @@
+package photos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PhotoSize is an image size variant for downloads.
+type PhotoSize string
+
+const (
+	// PhotoSizeFull is the original full-resolution image.
+	PhotoSizeFull PhotoSize = "full"
+	// PhotoSizeExtraLarge is the size typically used when viewing photos in Katapult Pro.
+	PhotoSizeExtraLarge PhotoSize = "extra_large"
+	// PhotoSizeLarge is a large thumbnail.
+	PhotoSizeLarge PhotoSize = "large"
+	// PhotoSizeSmall is a small thumbnail.
+	PhotoSizeSmall PhotoSize = "small"
+	// PhotoSizeTiny is the smallest thumbnail.
+	PhotoSizeTiny PhotoSize = "tiny"
+)
+
+// String returns the API value.
+func (s PhotoSize) String() string { return string(s) }
+
+// IsValid reports whether s is a defined photo size.
+func (s PhotoSize) IsValid() bool {
+	switch s {
+	case PhotoSizeFull, PhotoSizeExtraLarge, PhotoSizeLarge, PhotoSizeSmall, PhotoSizeTiny:
+		return true
+	}
+	return false
+}
+
+// ErrDownloadUnsupported is returned by download methods when the Doer cannot resolve photo URLs
+// or stream responses. *katapultpro.Client supports both.
+var ErrDownloadUnsupported = errors.New("photos: downloads need a Doer with PhotoURL and DoStream (e.g. *katapultpro.Client)")
+
+// downloader is implemented by *katapultpro.Client.
+type downloader interface {
+	PhotoURL(ctx context.Context, jobID, photoID string, size PhotoSize) (string, error)
+	InvalidatePhotoURL(jobID, photoID string, size PhotoSize)
+	DoStream(ctx context.Context, method, path string, query url.Values, header http.Header) (*http.Response, error)
+}
+
+// DownloadError is returned when the storage host rejects an image request.
+type DownloadError struct {
+	PhotoID    string
+	StatusCode int
+}
+
+func (e *DownloadError) Error() string {
+	return fmt.Sprintf("photos: download %s: HTTP %d", e.PhotoID, e.StatusCode)
+}
+
+// Retryable reports whether the download may succeed if repeated.
+func (e *DownloadError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Image is an open photo image. Close it when done.
+type Image struct {
+	io.ReadCloser
+	// Offset is the position of the first byte read: the requested offset, or 0 if the server
+	// ignored the range and is sending the whole image.
+	Offset int64
+	// Size is the total image size in bytes, or -1 if unknown.
+	Size int64
+	// ContentType is the image media type reported by the storage host.
+	ContentType string
+}
+
+// URL returns a signed, temporary URL for the photo image at size.
+func (s *PhotoScope) URL(ctx context.Context, size PhotoSize) (string, error) {
+	d, ok := s.do.(downloader)
+	if !ok {
+		return "", ErrDownloadUnsupported
+	}
+	return d.PhotoURL(ctx, s.jobID, s.photoID, size)
+}
+
+// Download streams the photo image at size to w and returns the number of bytes written.
+// Cancelling ctx stops the transfer.
+func (s *PhotoScope) Download(ctx context.Context, size PhotoSize, w io.Writer) (int64, error) {
+	img, err := s.OpenImage(ctx, size, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer img.Close()
+	return io.Copy(w, img)
+}
+
+// OpenImage opens the photo image at size for reading from offset, for resuming partial downloads.
+// Check Image.Offset: servers that ignore the range send the whole image from 0. A signed URL the
+// storage host rejects as expired is refreshed once.
+func (s *PhotoScope) OpenImage(ctx context.Context, size PhotoSize, offset int64) (*Image, error) {
+	d, ok := s.do.(downloader)
+	if !ok {
+		return nil, ErrDownloadUnsupported
+	}
+	var header http.Header
+	if offset > 0 {
+		header = http.Header{"Range": {"bytes=" + strconv.FormatInt(offset, 10) + "-"}}
+	}
+	for attempt := 0; ; attempt++ {
+		u, err := d.PhotoURL(ctx, s.jobID, s.photoID, size)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := d.DoStream(ctx, http.MethodGet, u, nil, header)
+		if err != nil {
+			return nil, err
+		}
+		img := &Image{ReadCloser: resp.Body, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return img, nil
+		case resp.StatusCode == http.StatusPartialContent:
+			img.Offset = offset
+			img.Size = contentRangeSize(resp.Header.Get("Content-Range"))
+			return img, nil
+		case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && contentRangeSize(resp.Header.Get("Content-Range")) == offset:
+			// The partial download is already complete.
+			resp.Body.Close()
+			return &Image{ReadCloser: http.NoBody, Offset: offset, Size: offset}, nil
+		}
+		resp.Body.Close()
+		if (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) && attempt == 0 {
+			d.InvalidatePhotoURL(s.jobID, s.photoID, size)
+			continue
+		}
+		return nil, &DownloadError{PhotoID: s.photoID, StatusCode: resp.StatusCode}
+	}
+}
+
+// contentRangeSize returns the total from a Content-Range header ("bytes 0-9/100" or "bytes */100"), or -1.
+func contentRangeSize(h string) int64 {
+	i := strings.LastIndexByte(h, '/')
+	if i < 0 {
+		return -1
+	}
+	n, err := strconv.ParseInt(h[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,41 @@
+package photos
+
+import "encoding/json"
+
+// photoElementFields are the JSON keys PhotoElement decodes into fields.
+var photoElementFields = map[string]bool{
+	"id": true, "element_type": true, "pixel_selection": true, "manual_height": true, "_measured_height": true,
+	"_routine_instance_id": true, "_trace": true, "pole_top_extension": true, "_context": true, "attributes": true,
+	"parent_id": true, "trace_id": true,
+}
+
+// UnmarshalJSON keeps attributes the API returns at the root of the element. Any key that is not a
+// PhotoElement field is added to Attributes (unless Attributes already has it), so elements of
+// types and with attributes the SDK does not know survive a decode and re-encode.
+func (e *PhotoElement) UnmarshalJSON(b []byte) error {
+	type alias PhotoElement
+	if err := json.Unmarshal(b, (*alias)(e)); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	for k, v := range raw {
+		if photoElementFields[k] {
+			continue
+		}
+		if _, ok := e.Attributes[k]; ok {
+			continue
+		}
+		var val interface{}
+		if err := json.Unmarshal(v, &val); err != nil {
+			return err
+		}
+		if e.Attributes == nil {
+			e.Attributes = map[string]interface{}{}
+		}
+		e.Attributes[k] = val
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package photos
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Height is a vertical distance, stored in inches. The API uses three notations for heights:
+// PhotoElement.ManualHeight is feet-inches ("16-6"), MeasuredHeight fields are inches, and anchor
+// heights are decimal feet. Convert them all to Height before comparing.
+type Height float64
+
+// Inches returns a Height of in inches.
+func Inches(in float64) Height { return Height(in) }
+
+// Feet returns a Height of ft decimal feet.
+func Feet(ft float64) Height { return Height(ft * 12) }
+
+// FeetInches returns a Height of ft feet plus in inches. Use a negative ft (or, for heights under a
+// foot, a negative in) for a negative height: FeetInches(-1, 6) is -1'6".
+func FeetInches(ft, in float64) Height {
+	if ft < 0 || (ft == 0 && in < 0) {
+		return -Height(math.Abs(ft)*12 + math.Abs(in))
+	}
+	return Height(ft*12 + in)
+}
+
+// Meters returns a Height of m meters.
+func Meters(m float64) Height { return Height(m / 0.0254) }
+
+// Inches returns h in inches, the unit of MeasuredHeight fields.
+func (h Height) Inches() float64 { return float64(h) }
+
+// Feet returns h in decimal feet, the unit of calibration anchor heights.
+func (h Height) Feet() float64 { return float64(h) / 12 }
+
+// Meters returns h in meters.
+func (h Height) Meters() float64 { return float64(h) * 0.0254 }
+
+// String returns h in the API's feet-inches notation ("16-6", "16-6.25", "-0-3"), with inches
+// rounded to two decimals. It is the format for ManualHeight.
+func (h Height) String() string {
+	hundredths := int64(math.Round(math.Abs(float64(h)) * 100))
+	sign := ""
+	if h < 0 && hundredths > 0 {
+		sign = "-"
+	}
+	ft, rem := hundredths/1200, hundredths%1200
+	return sign + strconv.FormatInt(ft, 10) + "-" + strconv.FormatFloat(float64(rem)/100, 'f', -1, 64)
+}
+
+// FormatFraction returns h as feet and inches with the inches rounded to the nearest 1/denom
+// (e.g. 8 or 16) and shown as a reduced fraction: 16' 6-1/2". A denom below 1 means whole inches.
+func (h Height) FormatFraction(denom int) string {
+	if denom < 1 {
+		denom = 1
+	}
+	d := int64(denom)
+	units := int64(math.Round(math.Abs(float64(h)) * float64(d)))
+	sign := ""
+	if h < 0 && units > 0 {
+		sign = "-"
+	}
+	ft, rem := units/(12*d), units%(12*d)
+	whole, num := rem/d, rem%d
+	if num == 0 {
+		return fmt.Sprintf("%s%d' %d\"", sign, ft, whole)
+	}
+	g := gcd(num, d)
+	return fmt.Sprintf("%s%d' %d-%d/%d\"", sign, ft, whole, num/g, d/g)
+}
+
+// UnmarshalJSON accepts a number of inches or a string in any notation ParseHeight accepts, so
+// configuration files can say "15-6" or "186in". Heights marshal as a number of inches.
+func (h *Height) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		v, err := ParseHeight(s)
+		if err != nil {
+			return err
+		}
+		*h = v
+		return nil
+	}
+	var in float64
+	if err := json.Unmarshal(b, &in); err != nil {
+		return fmt.Errorf("photos: height must be inches or a string like \"15-6\": %w", err)
+	}
+	*h = Height(in)
+	return nil
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// ParseHeight parses a height in any of these notations, each with an optional leading sign:
+//
+//	16-6   16-6.5   16-6 1/2   16-6-1/2   feet-inches, as in ManualHeight
+//	16' 6-1/2"   16'   6.5"              feet and inch marks
+//	198in   16.5ft   5.03m                explicit units
+//	16.5                                  decimal feet, as in anchor heights
+//
+// The sign applies to the whole height: "-1-6" is minus one foot six inches.
+func ParseHeight(s string) (Height, error) {
+	t := strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(t, "-") || strings.HasPrefix(t, "+") {
+		neg = t[0] == '-'
+		t = strings.TrimSpace(t[1:])
+	}
+	h, ok := parseUnsignedHeight(t)
+	if !ok {
+		return 0, fmt.Errorf("photos: invalid height %q", s)
+	}
+	if neg {
+		h = -h
+	}
+	return h, nil
+}
+
+// ParseManualHeight parses the strict feet-inches notation the API accepts for ManualHeight
+// ("16-6" or "16-6.5", with an optional leading "-").
+func ParseManualHeight(s string) (Height, error) {
+	t := strings.TrimPrefix(s, "-")
+	ft, in, ok := strings.Cut(t, "-")
+	if ok && isDigits(ft) {
+		whole, frac, hasFrac := strings.Cut(in, ".")
+		if isDigits(whole) && (!hasFrac || isDigits(frac)) {
+			return ParseHeight(s)
+		}
+	}
+	return 0, fmt.Errorf("photos: invalid manual height %q: want feet-inches like 16-6", s)
+}
+
+func parseUnsignedHeight(t string) (Height, bool) {
+	if t == "" {
+		return 0, false
+	}
+	if ft, rest, ok := strings.Cut(t, "'"); ok {
+		f, ok := parseNumber(ft)
+		rest = strings.TrimSpace(rest)
+		if !ok {
+			return 0, false
+		}
+		if rest == "" {
+			return Feet(f), true
+		}
+		in, ok := strings.CutSuffix(rest, `"`)
+		if !ok {
+			return 0, false
+		}
+		i, ok := parseInches(in)
+		return Feet(f) + Inches(i), ok
+	}
+	if in, ok := strings.CutSuffix(t, `"`); ok {
+		i, ok := parseInches(in)
+		return Inches(i), ok
+	}
+	for _, u := range []struct {
+		suffix string
+		conv   func(float64) Height
+	}{{"in", Inches}, {"ft", Feet}, {"m", Meters}} {
+		if v, ok := strings.CutSuffix(t, u.suffix); ok {
+			n, ok := parseNumber(v)
+			return u.conv(n), ok
+		}
+	}
+	if ft, in, ok := strings.Cut(t, "-"); ok {
+		f, ok1 := parseNumber(ft)
+		i, ok2 := parseInches(in)
+		return Feet(f) + Inches(i), ok1 && ok2
+	}
+	f, ok := parseNumber(t)
+	return Feet(f), ok
+}
+
+// parseInches parses "6", "6.5", "1/2", "6 1/2", or "6-1/2".
+func parseInches(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	num, den, ok := strings.Cut(s, "/")
+	if !ok {
+		return parseNumber(s)
+	}
+	whole := 0.0
+	if i := strings.LastIndexAny(num, " -"); i >= 0 {
+		w, ok := parseNumber(num[:i])
+		if !ok {
+			return 0, false
+		}
+		whole, num = w, num[i+1:]
+	}
+	n, ok1 := parseNumber(num)
+	d, ok2 := parseNumber(den)
+	if !ok1 || !ok2 || d == 0 {
+		return 0, false
+	}
+	return whole + n/d, true
+}
+
+// parseNumber parses a non-negative decimal number.
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || s[0] == '-' || s[0] == '+' {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, false
+	}
+	return v, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Height returns the element's height: the manual height when it is set and valid, otherwise the
+// measured height. ok is false when neither is available.
+func (e PhotoElement) Height() (h Height, ok bool) {
+	if e.ManualHeight != "" {
+		if h, err := ParseHeight(e.ManualHeight); err == nil {
+			return h, true
+		}
+	}
+	if e.MeasuredHeight != 0 {
+		return Inches(e.MeasuredHeight), true
+	}
+	return 0, false
+}
+
+// ManualHeightValue parses ManualHeight. It returns an error if the field is empty or invalid.
+func (e PhotoElement) ManualHeightValue() (Height, error) {
+	if e.ManualHeight == "" {
+		return 0, fmt.Errorf("photos: element %s has no manual height", e.ID)
+	}
+	return ParseHeight(e.ManualHeight)
+}
+
+// MeasuredHeightValue returns MeasuredHeight (inches) as a Height.
+func (e PhotoElement) MeasuredHeightValue() Height { return Inches(e.MeasuredHeight) }
+
+// Height returns MeasuredHeight (inches) as a Height.
+func (m WireMeasurement) Height() Height { return Inches(m.MeasuredHeight) }
+
+// Height returns MeasuredHeight (inches) as a Height.
+func (m PoleTopMeasurement) Height() Height { return Inches(m.MeasuredHeight) }
+
+// HeightValue returns Height (decimal feet) as a Height.
+func (a PhotoCalibrationAnchor) HeightValue() Height { return Feet(a.Height) }
+
+// HeightValue returns Height (decimal feet) as a Height.
+func (a AnchorCalibration) HeightValue() Height { return Feet(a.Height) }
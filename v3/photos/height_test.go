@@ -0,0 +1,143 @@
+package photos
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseHeight(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    float64 // inches
+		wantErr bool
+	}{
+		{"16-6", 198, false},
+		{"16-6.5", 198.5, false},
+		{"16-6 1/2", 198.5, false},
+		{"16-6-1/2", 198.5, false},
+		{"16-0", 192, false},
+		{"0-3", 3, false},
+		{"-0-3", -3, false},
+		{"-1-6", -18, false},
+		{"+2-0", 24, false},
+		{`16' 6-1/2"`, 198.5, false},
+		{`16'6"`, 198, false},
+		{`16'`, 192, false},
+		{`6.5"`, 6.5, false},
+		{`3/4"`, 0.75, false},
+		{"198in", 198, false},
+		{"16.5ft", 198, false},
+		{"1m", 1 / 0.0254, false},
+		{"16.5", 198, false},
+		{" 20-1 ", 241, false},
+		{"", 0, true},
+		{"-", 0, true},
+		{"abc", 0, true},
+		{"16-", 0, true},
+		{"16--6", 0, true},
+		{"16-6/0", 0, true},
+		{`16' 6`, 0, true},
+		{"NaN", 0, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseHeight(tc.input)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("ParseHeight(%q) = %v, want error", tc.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHeight(%q): %v", tc.input, err)
+			}
+			if math.Abs(got.Inches()-tc.want) > 1e-9 {
+				t.Errorf("ParseHeight(%q) = %v in, want %v", tc.input, got.Inches(), tc.want)
+			}
+		})
+	}
+}
+
+func TestParseManualHeight(t *testing.T) {
+	for _, s := range []string{"16-6", "16-6.25", "-0-3"} {
+		if _, err := ParseManualHeight(s); err != nil {
+			t.Errorf("ParseManualHeight(%q): %v", s, err)
+		}
+	}
+	for _, s := range []string{"16.5", `16' 6"`, "16-6 1/2", "198in", "16-"} {
+		if _, err := ParseManualHeight(s); err == nil {
+			t.Errorf("ParseManualHeight(%q): want error", s)
+		}
+	}
+}
+
+func TestHeight_Format(t *testing.T) {
+	cases := []struct {
+		h        Height
+		str      string
+		fraction string // FormatFraction(8)
+	}{
+		{Inches(198), "16-6", `16' 6"`},
+		{Inches(198.5), "16-6.5", `16' 6-1/2"`},
+		{Inches(198.3), "16-6.3", `16' 6-1/4"`},
+		{Inches(203.999), "17-0", `17' 0"`},
+		{Inches(-3), "-0-3", `-0' 3"`},
+		{FeetInches(-1, 6), "-1-6", `-1' 6"`},
+		{Inches(-0.001), "0-0", `0' 0"`},
+		{Feet(16.5), "16-6", `16' 6"`},
+	}
+	for _, tc := range cases {
+		if got := tc.h.String(); got != tc.str {
+			t.Errorf("Height(%v).String() = %q, want %q", float64(tc.h), got, tc.str)
+		}
+		if got := tc.h.FormatFraction(8); got != tc.fraction {
+			t.Errorf("Height(%v).FormatFraction(8) = %q, want %q", float64(tc.h), got, tc.fraction)
+		}
+		if back, err := ParseHeight(tc.h.String()); err != nil || math.Abs(float64(back-tc.h)) > 0.005 {
+			t.Errorf("round trip %q: got %v, %v", tc.h.String(), back, err)
+		}
+	}
+}
+
+func TestHeight_Accessors(t *testing.T) {
+	el := PhotoElement{ManualHeight: "20-1", MeasuredHeight: 230}
+	if h, ok := el.Height(); !ok || h.Inches() != 241 {
+		t.Errorf("manual height: got %v, %v", h, ok)
+	}
+	el.ManualHeight = ""
+	if h, ok := el.Height(); !ok || h.Inches() != 230 {
+		t.Errorf("measured height: got %v, %v", h, ok)
+	}
+	if _, err := el.ManualHeightValue(); err == nil {
+		t.Error("ManualHeightValue on empty field: want error")
+	}
+	if _, ok := (PhotoElement{}).Height(); ok {
+		t.Error("empty element: want ok false")
+	}
+	if got := (WireMeasurement{MeasuredHeight: 240}).Height().Feet(); got != 20 {
+		t.Errorf("wire feet = %v", got)
+	}
+	if got := (PoleTopMeasurement{MeasuredHeight: 420}).Height().String(); got != "35-0" {
+		t.Errorf("pole top = %q", got)
+	}
+	if got := (PhotoCalibrationAnchor{Height: 5.25}).HeightValue().Inches(); got != 63 {
+		t.Errorf("anchor inches = %v", got)
+	}
+	if got := (AnchorCalibration{Height: 2}).HeightValue().Inches(); got != 24 {
+		t.Errorf("anchor calibration inches = %v", got)
+	}
+}
+
+func TestHeight_UnmarshalJSON(t *testing.T) {
+	var v struct{ A, B, C Height }
+	if err := json.Unmarshal([]byte(`{"A":"15-6","B":40,"C":"1m"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.A != 186 || v.B != 40 || math.Abs(v.C.Meters()-1) > 1e-12 {
+		t.Errorf("got %+v", v)
+	}
+	if err := json.Unmarshal([]byte(`{"A":"tall"}`), &v); err == nil {
+		t.Error("want error for invalid height")
+	}
+}
@@ -244,6 +244,7 @@ type CreatePhotoElementRequest struct {
 	Attributes      map[string]interface{} `json:"attributes,omitempty"`     // Flat map.
 	ParentID        string                 `json:"parent_id,omitempty"`     // Child element; omit to not nest.
 	TraceID         string                 `json:"trace_id,omitempty"`
+	PoleTopExtension bool                  `json:"pole_top_extension,omitempty"` // Pole tops only.
 }
 
 // UpdatePhotoElementRequest is the body for POST /v3/jobs/:job_id/photos/:photo_id/photo_elements/:element_id.
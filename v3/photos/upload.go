@@ -0,0 +1,227 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3/internal/request"
+)
+
+// Content types recognized by DetectContentType.
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeHEIC = "image/heic"
+)
+
+// DefaultUploadBackoff is the delay before the first upload retry; it doubles on each attempt.
+const DefaultUploadBackoff = time.Second
+
+// maxUploadBackoff caps the delay between upload retries.
+const maxUploadBackoff = time.Minute
+
+// Opener opens the photo data from the start. Uploads with an Opener can be retried.
+type Opener func() (io.ReadCloser, error)
+
+// OpenFile returns an Opener for the file at path.
+func OpenFile(path string) Opener {
+	return func() (io.ReadCloser, error) { return os.Open(path) }
+}
+
+// OpenReaderAt returns an Opener that reads size bytes of r from offset 0 on each call.
+func OpenReaderAt(r io.ReaderAt, size int64) Opener {
+	return func() (io.ReadCloser, error) { return sectionCloser{io.NewSectionReader(r, 0, size)}, nil }
+}
+
+type sectionCloser struct{ *io.SectionReader }
+
+func (sectionCloser) Close() error { return nil }
+
+// UploadOptions configures photo uploads. The zero value uploads once with the detected content type.
+type UploadOptions struct {
+	// ContentType overrides detection; when empty it is detected from the data (JPEG, PNG, or HEIC,
+	// falling back to image/jpeg).
+	ContentType string
+	// Size is the total size in bytes, sent as Content-Length and passed to Progress. When zero it is
+	// taken from the source if it is a file or has a Len or Size method, and is otherwise unknown (-1).
+	Size int64
+	// Progress, if set, is called as data is sent with the bytes sent so far in the current attempt.
+	Progress func(sent, total int64)
+	// VerifySize compares the stored Photo.OriginalSize with the bytes sent and returns a
+	// *SizeMismatchError when they differ.
+	VerifySize bool
+	// Open re-opens the source for each attempt; when set, the reader argument to the upload method
+	// is ignored and may be nil. Retries require Open, because a consumed reader cannot be rewound.
+	Open Opener
+	// Retries is the number of extra attempts after a network error, a 429, or a 5xx response.
+	Retries int
+	// Backoff is the delay before the first retry, doubling after each. Zero means DefaultUploadBackoff.
+	Backoff time.Duration
+}
+
+// SizeMismatchError is returned when UploadOptions.VerifySize is set and the API stored a different
+// number of bytes than were sent. Photo is the record that was created.
+type SizeMismatchError struct {
+	Sent   int64
+	Stored int64
+	Photo  *Photo
+}
+
+func (e *SizeMismatchError) Error() string {
+	return fmt.Sprintf("photos: uploaded %d bytes but photo %s has original_size %d", e.Sent, e.Photo.ID, e.Stored)
+}
+
+// DetectContentType returns the image content type of data from its leading bytes
+// (at least 12 are needed for HEIC), or "" if it is not JPEG, PNG, or HEIC.
+func DetectContentType(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return ContentTypeJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return ContentTypePNG
+	case len(data) >= 12 && string(data[4:8]) == "ftyp":
+		switch string(data[8:12]) {
+		case "heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1":
+			return ContentTypeHEIC
+		}
+	}
+	return ""
+}
+
+// UploadWithOptions uploads a photo to the job using opts (v3).
+func (c *Client) UploadWithOptions(ctx context.Context, imageData io.Reader, opts *UploadOptions) (*Photo, error) {
+	return Send(ctx, c.do, "v3/jobs/"+c.jobID+"/photos", nil, imageData, opts)
+}
+
+// Send posts photo data to an upload path with opts, retrying as configured. It backs the photo
+// upload methods of this package and of the nodes and sections packages.
+func Send(ctx context.Context, do request.Doer, path string, query url.Values, imageData io.Reader, opts *UploadOptions) (*Photo, error) {
+	if opts == nil {
+		opts = &UploadOptions{}
+	}
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = DefaultUploadBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		photo, retry, err := sendOnce(ctx, do, path, query, imageData, opts)
+		if err == nil || !retry || opts.Open == nil || attempt >= opts.Retries {
+			return photo, err
+		}
+		t := time.NewTimer(retryDelay(backoff, attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// retryDelay returns backoff doubled attempt times, at most maxUploadBackoff.
+func retryDelay(backoff time.Duration, attempt int) time.Duration {
+	if attempt > 16 {
+		attempt = 16
+	}
+	if d := backoff << attempt; d > 0 && d < maxUploadBackoff {
+		return d
+	}
+	return maxUploadBackoff
+}
+
+// sendOnce performs one attempt and reports whether a failure may be retried.
+func sendOnce(ctx context.Context, do request.Doer, path string, query url.Values, r io.Reader, opts *UploadOptions) (*Photo, bool, error) {
+	if opts.Open != nil {
+		rc, err := opts.Open()
+		if err != nil {
+			return nil, false, fmt.Errorf("photos: open upload source: %w", err)
+		}
+		defer rc.Close()
+		r = rc
+	}
+	if r == nil {
+		return nil, false, errors.New("photos: no upload data")
+	}
+	total := opts.Size
+	if total <= 0 {
+		total = sourceSize(r)
+	}
+	contentType := opts.ContentType
+	if contentType == "" {
+		head := make([]byte, 12)
+		n, err := io.ReadFull(r, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, true, fmt.Errorf("photos: read upload data: %w", err)
+		}
+		head = head[:n]
+		if contentType = DetectContentType(head); contentType == "" {
+			contentType = ContentTypeJPEG
+		}
+		r = io.MultiReader(bytes.NewReader(head), r)
+	}
+	body := &countingReader{r: r, total: total, progress: opts.Progress}
+	var photo Photo
+	if err := do.DoWithBody(ctx, http.MethodPost, path, query, contentType, body, &photo); err != nil {
+		return nil, retryable(ctx, err), err
+	}
+	if opts.VerifySize && photo.OriginalSize != 0 && photo.OriginalSize != body.n {
+		return &photo, false, &SizeMismatchError{Sent: body.n, Stored: photo.OriginalSize, Photo: &photo}
+	}
+	return &photo, false, nil
+}
+
+// retryable reports whether err is worth another attempt: 429s, 5xx responses, and failures to get
+// a response at all. Anything else, such as a 2xx response that could not be decoded, may mean the
+// photo was stored, so repeating the upload could duplicate it.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var r interface{ Retryable() bool }
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+func sourceSize(r io.Reader) int64 {
+	switch s := r.(type) {
+	case interface{ Len() int }:
+		return int64(s.Len())
+	case interface{ Size() int64 }:
+		return s.Size()
+	case *os.File:
+		if fi, err := s.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
+// countingReader counts bytes read and reports progress.
+type countingReader struct {
+	r        io.Reader
+	n, total int64
+	progress func(sent, total int64)
+}
+
+// ContentLength lets the transport send a Content-Length header instead of a chunked body.
+func (c *countingReader) ContentLength() int64 { return c.total }
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	if c.progress != nil && n > 0 {
+		c.progress(c.n, c.total)
+	}
+	return n, err
+}
@@ -3,9 +3,15 @@ package katapultpro_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/romer-pro/katapultpro-go-sdk/v3"
 )
@@ -96,3 +102,226 @@ func TestAssociatePhoto(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestUploadJobPhotoWithOptions_RetryProgressAndType(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{1}, 100)...)
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"status":"error","message":"try again"}`))
+			return
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "image/png" {
+			t.Errorf("Content-Type = %s", ct)
+		}
+		if r.ContentLength != int64(len(png)) || !bytes.Equal(body, png) {
+			t.Errorf("got %d bytes, Content-Length %d", len(body), r.ContentLength)
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"id":"p1","original_size":108},"meta":{"token_count":9999,"last_refill_time":0}}`))
+	}))
+	defer srv.Close()
+
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL))
+	var lastSent, lastTotal int64
+	photo, err := client.UploadJobPhotoWithOptions(context.Background(), "j1", nil, &katapultpro.PhotoUploadOptions{
+		Open:       katapultpro.OpenPhotoReaderAt(bytes.NewReader(png), int64(len(png))),
+		Retries:    2,
+		Backoff:    time.Millisecond,
+		VerifySize: true,
+		Progress:   func(sent, total int64) { lastSent, lastTotal = sent, total },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.ID != "p1" || attempts != 2 {
+		t.Errorf("got photo %+v after %d attempts", photo, attempts)
+	}
+	if lastSent != 108 || lastTotal != 108 {
+		t.Errorf("progress %d/%d", lastSent, lastTotal)
+	}
+}
+
+func TestUploadJobPhotoWithOptions_SizeMismatchAndNoRetryOn4xx(t *testing.T) {
+	var attempts int32
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			_, _ = w.Write([]byte(`{"status":"error","message":"bad photo"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"id":"p1","original_size":3},"meta":{"token_count":9999,"last_refill_time":0}}`))
+	}))
+	defer srv.Close()
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	_, err := client.UploadJobPhotoWithOptions(ctx, "j1", bytes.NewReader([]byte("\xff\xd8\xffjpeg")), &katapultpro.PhotoUploadOptions{VerifySize: true})
+	var mismatch *katapultpro.PhotoSizeMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Sent != 7 || mismatch.Stored != 3 {
+		t.Errorf("expected size mismatch, got %v", err)
+	}
+
+	status, attempts = http.StatusBadRequest, 0
+	open := katapultpro.PhotoOpener(func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader([]byte("x"))), nil })
+	_, err = client.UploadJobPhotoWithOptions(ctx, "j1", nil, &katapultpro.PhotoUploadOptions{Open: open, Retries: 3, Backoff: time.Millisecond})
+	var apiErr *katapultpro.APIError
+	if !errors.As(err, &apiErr) || apiErr.Retryable() || attempts != 1 {
+		t.Errorf("400 should not be retried: %v after %d attempts", err, attempts)
+	}
+
+	// A 2xx whose body cannot be decoded may have stored the photo; uploading again could duplicate it.
+	attempts = 0
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":`))
+	}))
+	defer broken.Close()
+	client, _ = katapultpro.NewClient("key", katapultpro.WithBaseURL(broken.URL))
+	if _, err = client.UploadJobPhotoWithOptions(ctx, "j1", nil, &katapultpro.PhotoUploadOptions{Open: open, Retries: 3, Backoff: time.Millisecond}); err == nil || attempts != 1 {
+		t.Errorf("undecodable 2xx should not be retried: %v after %d attempts", err, attempts)
+	}
+}
+
+func TestDownloadPhoto_CachesSignedURLAndRefreshesWhenRejected(t *testing.T) {
+	var urlCalls, imageCalls atomic.Int32
+	expires := time.Now().Add(time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/jobs/j1/photoURL/p1":
+			if r.URL.Query().Get("file_size") != "large" || r.URL.Query().Get("api_key") != "key" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			n := urlCalls.Add(1)
+			_, _ = fmt.Fprintf(w, `{"url":"http://%s/img/p1?Expires=%d&n=%d"}`, r.Host, expires, n)
+		case "/img/p1":
+			if r.URL.Query().Get("api_key") != "" {
+				t.Error("api key sent to storage host")
+			}
+			// The third image request presents the first URL again; reject it as expired.
+			if imageCalls.Add(1) == 3 && r.URL.Query().Get("n") == "1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write([]byte("jpeg-bytes"))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL))
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+		n, err := client.DownloadPhoto(ctx, "j1", "p1", katapultpro.PhotoSizeLarge, &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 10 || buf.String() != "jpeg-bytes" {
+			t.Fatalf("download %d: got %d bytes %q", i, n, buf.String())
+		}
+	}
+	if got := urlCalls.Load(); got != 2 {
+		t.Errorf("photoURL called %d times, want 2 (cached, then refreshed after 403)", got)
+	}
+	if got := imageCalls.Load(); got != 4 {
+		t.Errorf("image requested %d times, want 4", got)
+	}
+}
+
+func TestPhotoOpenImage_ResumesWithRange(t *testing.T) {
+	data := []byte("0123456789")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/jobs/j1/photoURL/p1" {
+			_, _ = fmt.Fprintf(w, `{"url":"http://%s/img/p1"}`, r.Host)
+			return
+		}
+		if r.URL.Path != "/img/p1" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "p1.jpg", time.Time{}, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL), katapultpro.WithPhotoURLTTL(0))
+	photo := client.Job("j1").Photos().Photo("p1")
+	img, err := photo.OpenImage(context.Background(), katapultpro.PhotoSizeFull, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest, _ := io.ReadAll(img)
+	img.Close()
+	if img.Offset != 4 || img.Size != 10 || string(rest) != "456789" {
+		t.Errorf("got offset %d size %d rest %q", img.Offset, img.Size, rest)
+	}
+
+	img, err = photo.OpenImage(context.Background(), katapultpro.PhotoSizeFull, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img.Close()
+	if img.Offset != 10 || img.Size != 10 {
+		t.Errorf("complete download: got offset %d size %d", img.Offset, img.Size)
+	}
+
+	_, err = client.Job("j1").Photos().Photo("missing").URL(context.Background(), katapultpro.PhotoSizeFull)
+	var apiErr *katapultpro.APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("missing photo: got %v, want *APIError", err)
+	}
+}
+
+func TestListAllElements_StopsAfterFirstError(t *testing.T) {
+	var mu sync.Mutex
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v3/jobs/j1/photos/p2/photo_elements" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"status":"error","message":"photo not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":[{"id":"e1","element_type":"wire"}],"meta":{"token_count":9999,"last_refill_time":0}}`))
+	}))
+	defer srv.Close()
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	els, err := client.Job("j1").Photos().ListAllElements(ctx, []string{"p1", "p3"}, 4)
+	if err != nil || len(els) != 2 || els["p3"][0].ID != "e1" {
+		t.Fatalf("got %v, %v", els, err)
+	}
+
+	// With one listing in flight, nothing after the failing photo is requested.
+	mu.Lock()
+	requested = nil
+	mu.Unlock()
+	_, err = client.Job("j1").Photos().ListAllElements(ctx, []string{"p1", "p2", "p3", "p4"}, 1)
+	var apiErr *katapultpro.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("got %v, want the 404", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requested) != 2 {
+		t.Errorf("requested %v after the failure", requested)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.Job("j1").Photos().ListAllElements(canceled, []string{"p1"}, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled context: got %v", err)
+	}
+}
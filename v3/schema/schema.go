@@ -0,0 +1,555 @@
+// Package schema validates node, connection, and section attributes against a company's attribute
+// model: which attributes are allowed, their types, enums, patterns, ranges, whether they are
+// required, and whether they may have more than one instance.
+//
+// Schemas are written in YAML or JSON:
+//
+//	node:
+//	  attributes:
+//	    node_type: {type: string, enum: [pole, reference, building], required: true}
+//	    scid: {type: string, pattern: "^[0-9]{3}[A-Z]?$", required: true}
+//	    pole_height: {type: number, min: 20, max: 150}
+//	    note: {type: string, multiple: true}
+//	connection:
+//	  allow_unknown: true
+//	  attributes:
+//	    connection_type: {type: string, enum: [aerial cable, underground cable]}
+//
+// Validate requests before sending them, and fetched entities when auditing a job:
+//
+//	s, err := schema.Load(f)
+//	if err := s.ValidateCreateNode(req); err != nil { ... } // *schema.ValidationError
+//	violations, err := s.Audit(ctx, client.Job("job-123"))
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// Entity kinds.
+const (
+	KindNode       = "node"
+	KindConnection = "connection"
+	KindSection    = "section"
+)
+
+// Attribute types.
+const (
+	TypeAny     = ""
+	TypeString  = "string"
+	TypeNumber  = "number"  // A JSON number or a numeric string
+	TypeInteger = "integer" // A whole number or a string of one
+	TypeBoolean = "boolean"
+)
+
+// Schema is an attribute model for the three kinds of entity that carry attribute lists.
+type Schema struct {
+	Node       Entity `json:"node"`
+	Connection Entity `json:"connection"`
+	Section    Entity `json:"section"`
+}
+
+// Entity lists the attributes of one kind of entity.
+type Entity struct {
+	Attributes map[string]*Attribute `json:"attributes"`
+	// AllowUnknown accepts attributes not listed in Attributes. An entity with no attributes
+	// listed accepts anything.
+	AllowUnknown bool `json:"allow_unknown,omitempty"`
+}
+
+// Attribute describes one attribute.
+type Attribute struct {
+	Type string `json:"type,omitempty"` // One of the Type constants; empty accepts any value
+	// Enum lists the allowed values, compared as strings.
+	Enum EnumValues `json:"enum,omitempty"`
+	// Pattern is a regular expression string values must match in full.
+	Pattern string `json:"pattern,omitempty"`
+	// Min and Max bound number and integer values.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Multiple allows more than one instance.
+	Multiple bool `json:"multiple,omitempty"`
+	// Required attributes must have a non-empty value on entities and create requests.
+	Required bool `json:"required,omitempty"`
+	// GoName overrides the struct field name cmd/attrgen derives from the attribute name.
+	GoName string `json:"go_name,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// EnumValues are the allowed values of an attribute. A schema may list numbers and booleans; they
+// are kept as the strings values are compared with: numbers in shortest decimal form ("1", "2.5"),
+// booleans as "true" and "false".
+type EnumValues []string
+
+// UnmarshalJSON accepts strings, numbers, and booleans.
+func (e *EnumValues) UnmarshalJSON(b []byte) error {
+	var raw []any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	out := make(EnumValues, len(raw))
+	for i, v := range raw {
+		switch t := v.(type) {
+		case string:
+			out[i] = t
+		case float64:
+			out[i] = strconv.FormatFloat(t, 'f', -1, 64)
+		case bool:
+			out[i] = strconv.FormatBool(t)
+		default:
+			return fmt.Errorf("enum value %d: must be a string, number, or boolean, got %s", i, jsonType(v))
+		}
+	}
+	*e = out
+	return nil
+}
+
+// Load reads a schema in JSON, or in YAML when the input does not start with '{'. Unknown fields
+// are rejected and the schema is compiled.
+func Load(r io.Reader) (*Schema, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("schema: read: %w", err)
+	}
+	if t := bytes.TrimSpace(b); len(t) == 0 || t[0] != '{' {
+		v, err := parseYAML(string(b))
+		if err != nil {
+			return nil, err
+		}
+		if b, err = json.Marshal(v); err != nil {
+			return nil, fmt.Errorf("schema: %w", err)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var s Schema
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("schema: decode: %w", err)
+	}
+	if err := s.Compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Compile checks the schema and compiles its patterns. Load calls it; call it yourself on a
+// Schema built in code.
+func (s *Schema) Compile() error {
+	for _, k := range []struct {
+		kind string
+		e    *Entity
+	}{{KindNode, &s.Node}, {KindConnection, &s.Connection}, {KindSection, &s.Section}} {
+		for _, name := range sortedKeys(k.e.Attributes) {
+			a := k.e.Attributes[name]
+			if a == nil {
+				a = &Attribute{}
+				k.e.Attributes[name] = a
+			}
+			switch a.Type {
+			case TypeAny, TypeString, TypeNumber, TypeInteger, TypeBoolean:
+			default:
+				return fmt.Errorf("schema: %s attribute %s: unknown type %q", k.kind, name, a.Type)
+			}
+			if a.Pattern != "" {
+				re, err := regexp.Compile(`^(?:` + a.Pattern + `)$`)
+				if err != nil {
+					return fmt.Errorf("schema: %s attribute %s: pattern: %w", k.kind, name, err)
+				}
+				a.re = re
+			}
+			if a.Min != nil && a.Max != nil && *a.Min > *a.Max {
+				return fmt.Errorf("schema: %s attribute %s: min is greater than max", k.kind, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Violation is one way an entity or request does not match the schema.
+type Violation struct {
+	Kind      string `json:"kind"`
+	EntityID  string `json:"entity_id,omitempty"`
+	ParentID  string `json:"parent_id,omitempty"` // The connection of a section
+	Attribute string `json:"attribute"`
+	// InstanceID is set for problems with one instance's value.
+	InstanceID string `json:"instance_id,omitempty"`
+	Problem    string `json:"problem"`
+}
+
+func (v Violation) String() string {
+	var b strings.Builder
+	b.WriteString(v.Kind)
+	if v.EntityID != "" {
+		b.WriteString(" ")
+		if v.ParentID != "" {
+			b.WriteString(v.ParentID + "/")
+		}
+		b.WriteString(v.EntityID)
+	}
+	b.WriteString(": " + v.Attribute)
+	if v.InstanceID != "" {
+		b.WriteString(" (" + v.InstanceID + ")")
+	}
+	return b.String() + " " + v.Problem
+}
+
+// ValidationError is returned by the request validators.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msg := "schema: " + e.Violations[0].String()
+	if n := len(e.Violations) - 1; n > 0 {
+		msg += fmt.Sprintf(" (and %d more)", n)
+	}
+	return msg
+}
+
+func asError(vs []Violation) error {
+	if len(vs) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: vs}
+}
+
+// ValidateNode checks a fetched node.
+func (s *Schema) ValidateNode(n *katapultpro.Node) []Violation {
+	return s.Node.check(KindNode, n.ID, "", n.Attributes, true)
+}
+
+// ValidateConnection checks a fetched connection's attributes and those of its embedded sections.
+func (s *Schema) ValidateConnection(c *katapultpro.Connection) []Violation {
+	out := s.Connection.check(KindConnection, c.ID, "", c.Attributes, true)
+	for _, id := range sortedKeys(c.Sections) {
+		out = append(out, s.Section.check(KindSection, id, c.ID, c.Sections[id].MultiAttributes, true)...)
+	}
+	return out
+}
+
+// ValidateSection checks a fetched section of the given connection.
+func (s *Schema) ValidateSection(connectionID string, sec *katapultpro.Section) []Violation {
+	id := sec.ID
+	if id == "" {
+		id = sec.Key
+	}
+	attrs := sec.Attributes
+	if attrs == nil {
+		attrs = sec.MultiAttributes
+	}
+	return s.Section.check(KindSection, id, connectionID, attrs, true)
+}
+
+// ValidateCreateNode checks a create request, including required attributes. It returns a
+// *ValidationError.
+func (s *Schema) ValidateCreateNode(req *katapultpro.CreateNodeRequest) error {
+	return asError(s.Node.check(KindNode, "", "", created(req.Attributes, req.AddAttributes), true))
+}
+
+// ValidateUpdateNode checks an update request. With the node's current state, it checks the node
+// as it will be after the update; with a nil current, it checks the values sent and that no
+// required attribute is removed. It returns a *ValidationError.
+func (s *Schema) ValidateUpdateNode(id string, req *katapultpro.UpdateNodeRequest, current *katapultpro.Node) error {
+	var cur katapultpro.EntityAttributeList
+	if current != nil {
+		cur = current.Attributes
+	}
+	return asError(s.Node.update(KindNode, id, "", req.RemoveAttributes, req.Attributes, req.AddAttributes, cur, current != nil))
+}
+
+// ValidateCreateConnection checks a create request, including required attributes.
+func (s *Schema) ValidateCreateConnection(req *katapultpro.CreateConnectionRequest) error {
+	return asError(s.Connection.check(KindConnection, "", "", created(req.Attributes, req.AddAttributes), true))
+}
+
+// ValidateUpdateConnection checks an update request like ValidateUpdateNode.
+func (s *Schema) ValidateUpdateConnection(id string, req *katapultpro.UpdateConnectionRequest, current *katapultpro.Connection) error {
+	var cur katapultpro.EntityAttributeList
+	if current != nil {
+		cur = current.Attributes
+	}
+	return asError(s.Connection.update(KindConnection, id, "", req.RemoveAttributes, req.Attributes, req.AddAttributes, cur, current != nil))
+}
+
+// ValidateCreateSection checks a create request, including required attributes.
+func (s *Schema) ValidateCreateSection(connectionID string, req *katapultpro.CreateSectionRequest) error {
+	return asError(s.Section.check(KindSection, "", connectionID, created(req.Attributes, req.AddAttributes), true))
+}
+
+// ValidateUpdateSection checks an update request like ValidateUpdateNode.
+func (s *Schema) ValidateUpdateSection(connectionID, id string, req *katapultpro.UpdateSectionRequest, current *katapultpro.Section) error {
+	var cur katapultpro.EntityAttributeList
+	if current != nil {
+		cur = current.Attributes
+		if cur == nil {
+			cur = current.MultiAttributes
+		}
+	}
+	return asError(s.Section.update(KindSection, id, connectionID, req.RemoveAttributes, req.Attributes, req.AddAttributes, cur, current != nil))
+}
+
+// Audit lists the job's nodes and connections and validates them and the connections' sections.
+func (s *Schema) Audit(ctx context.Context, job *katapultpro.JobScope) ([]Violation, error) {
+	nodes, err := job.Nodes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("schema: list nodes: %w", err)
+	}
+	conns, err := job.Connections().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("schema: list connections: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	sort.Slice(conns, func(i, j int) bool { return conns[i].ID < conns[j].ID })
+	var out []Violation
+	for i := range nodes {
+		out = append(out, s.ValidateNode(&nodes[i])...)
+	}
+	for i := range conns {
+		out = append(out, s.ValidateConnection(&conns[i])...)
+	}
+	return out, nil
+}
+
+// created returns the attribute list a create request produces. add_attributes instances get
+// placeholder IDs.
+func created(attrs katapultpro.EntityAttributeList, add map[string]interface{}) katapultpro.EntityAttributeList {
+	out := katapultpro.EntityAttributeList{}
+	for name, instances := range attrs {
+		out[name] = map[string]interface{}{}
+		for id, v := range instances {
+			if v != nil {
+				out[name][id] = v
+			}
+		}
+	}
+	addInstances(out, add)
+	return out
+}
+
+func addInstances(list katapultpro.EntityAttributeList, add map[string]interface{}) {
+	for _, name := range sortedKeys(add) {
+		if add[name] == nil {
+			continue
+		}
+		if list[name] == nil {
+			list[name] = map[string]interface{}{}
+		}
+		list[name]["add_attributes#"+strconv.Itoa(len(list[name]))] = add[name]
+	}
+}
+
+// update validates an update request, applying it to current when known (the API order:
+// remove_attributes, then attributes, where null removes, then add_attributes).
+func (e *Entity) update(kind, id, parentID string, remove []string, attrs katapultpro.EntityAttributeList, add map[string]interface{},
+	current katapultpro.EntityAttributeList, known bool) []Violation {
+	if known {
+		after := katapultpro.EntityAttributeList{}
+		for name, instances := range current {
+			after[name] = map[string]interface{}{}
+			for k, v := range instances {
+				after[name][k] = v
+			}
+		}
+		for _, name := range remove {
+			delete(after, name)
+		}
+		for name, instances := range attrs {
+			if instances == nil {
+				delete(after, name)
+				continue
+			}
+			if after[name] == nil {
+				after[name] = map[string]interface{}{}
+			}
+			for k, v := range instances {
+				if v == nil {
+					delete(after[name], k)
+				} else {
+					after[name][k] = v
+				}
+			}
+			if len(after[name]) == 0 {
+				delete(after, name)
+			}
+		}
+		addInstances(after, add)
+		return e.check(kind, id, parentID, after, true)
+	}
+
+	out := e.check(kind, id, parentID, created(attrs, add), false)
+	for _, name := range sortedKeys(e.Attributes) {
+		instances, set := attrs[name]
+		removed := containsString(remove, name) || (set && instances == nil)
+		if e.Attributes[name].Required && removed {
+			out = append(out, Violation{Kind: kind, EntityID: id, ParentID: parentID, Attribute: name, Problem: "is required and cannot be removed"})
+		}
+	}
+	return out
+}
+
+// check validates an attribute list. full also checks required attributes.
+func (e *Entity) check(kind, id, parentID string, attrs katapultpro.EntityAttributeList, full bool) []Violation {
+	var out []Violation
+	add := func(attr, instance, problem string) {
+		out = append(out, Violation{Kind: kind, EntityID: id, ParentID: parentID, Attribute: attr, InstanceID: instance, Problem: problem})
+	}
+	for _, name := range sortedKeys(attrs) {
+		instances := attrs[name]
+		spec := e.Attributes[name]
+		if spec == nil {
+			if !e.AllowUnknown && len(e.Attributes) > 0 {
+				add(name, "", "is not in the schema")
+			}
+			continue
+		}
+		if n := len(instances); n > 1 && !spec.Multiple {
+			add(name, "", fmt.Sprintf("has %d instances but allows one", n))
+		}
+		for _, inst := range sortedKeys(instances) {
+			if v := instances[inst]; v != nil {
+				if problem := spec.checkValue(v); problem != "" {
+					if strings.HasPrefix(inst, "add_attributes#") {
+						inst = ""
+					}
+					add(name, inst, problem)
+				}
+			}
+		}
+	}
+	if full {
+		for _, name := range sortedKeys(e.Attributes) {
+			if e.Attributes[name].Required && !hasValue(attrs[name]) {
+				add(name, "", "is required")
+			}
+		}
+	}
+	return out
+}
+
+func hasValue(instances map[string]interface{}) bool {
+	for _, v := range instances {
+		if v != nil && fmt.Sprint(v) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkValue returns a description of what is wrong with v, or "".
+func (a *Attribute) checkValue(v interface{}) string {
+	var num float64
+	switch a.Type {
+	case TypeString:
+		if _, ok := v.(string); !ok {
+			return fmt.Sprintf("must be a string, got %s", jsonType(v))
+		}
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return fmt.Sprintf("must be a boolean, got %s", jsonType(v))
+		}
+	case TypeNumber, TypeInteger:
+		n, ok := number(v)
+		if !ok {
+			return fmt.Sprintf("must be a number, got %s", quoted(v))
+		}
+		if a.Type == TypeInteger && n != math.Trunc(n) {
+			return fmt.Sprintf("must be a whole number, got %s", quoted(v))
+		}
+		num = n
+		if a.Min != nil && n < *a.Min {
+			return fmt.Sprintf("must be at least %g, got %g", *a.Min, n)
+		}
+		if a.Max != nil && n > *a.Max {
+			return fmt.Sprintf("must be at most %g, got %g", *a.Max, n)
+		}
+	}
+	s := fmt.Sprint(v)
+	if a.Type == TypeNumber || a.Type == TypeInteger {
+		s = strconv.FormatFloat(num, 'f', -1, 64)
+		if str, ok := v.(string); ok {
+			s = str
+		}
+	}
+	if len(a.Enum) > 0 && !containsString(a.Enum, s) {
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(a.Enum, ", "), s)
+	}
+	if a.re != nil && !a.re.MatchString(s) {
+		return fmt.Sprintf("must match %s, got %q", a.Pattern, s)
+	}
+	return ""
+}
+
+func number(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case json.Number:
+		n, err := t.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return n, err == nil && !math.IsNaN(n) && !math.IsInf(n, 0)
+	}
+	return 0, false
+}
+
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64, float32, int, int64, json.Number:
+		return "a number"
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "an array"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func quoted(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return jsonType(v)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
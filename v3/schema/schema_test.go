@@ -0,0 +1,193 @@
+package schema_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/schema"
+)
+
+const schemaYAML = `
+# Company attribute model
+node:
+  attributes:
+    node_type: {type: string, enum: [pole, reference], required: true}
+    scid:
+      type: string
+      pattern: "^[0-9]{3}[A-Z]?$"   # e.g. 012 or 012A
+      required: true
+    pole_height: {type: number, min: 20, max: 150}
+    note:
+      type: string
+      multiple: true
+connection:
+  allow_unknown: true
+  attributes:
+    connection_type:
+      type: string
+      enum:
+        - aerial cable
+        - 'underground cable'
+section:
+  attributes: {}
+`
+
+func load(t *testing.T) *schema.Schema {
+	t.Helper()
+	s, err := schema.Load(strings.NewReader(schemaYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func problems(vs []schema.Violation) []string {
+	var out []string
+	for _, v := range vs {
+		out = append(out, v.String())
+	}
+	return out
+}
+
+func TestLoad_YAMLMatchesJSON(t *testing.T) {
+	y := load(t)
+	j, err := schema.Load(strings.NewReader(`{"node":{"attributes":{
+		"node_type":{"type":"string","enum":["pole","reference"],"required":true},
+		"scid":{"type":"string","pattern":"^[0-9]{3}[A-Z]?$","required":true},
+		"pole_height":{"type":"number","min":20,"max":150},
+		"note":{"type":"string","multiple":true}}},
+		"connection":{"allow_unknown":true,"attributes":{"connection_type":{"type":"string","enum":["aerial cable","underground cable"]}}},
+		"section":{"attributes":{}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &katapultpro.Node{ID: "n1", Attributes: katapultpro.EntityAttributeList{
+		"node_type": {"-a": "tower"}, "scid": {"-a": "12"}, "pole_height": {"-a": "200"}, "bogus": {"-a": 1},
+	}}
+	if a, b := strings.Join(problems(y.ValidateNode(node)), "\n"), strings.Join(problems(j.ValidateNode(node)), "\n"); a != b || a == "" {
+		t.Errorf("yaml:\n%s\njson:\n%s", a, b)
+	}
+
+	for _, bad := range []string{
+		"node:\n  attributes:\n    x: {type: date}",
+		"node:\n  attributes:\n    x: {pattern: '('}",
+		"node:\n  bogus: true",
+		"node:\n  attributes:\n    x: &anchor {}",
+		"node:\n attributes: {}\n  extra: 1",
+		"node: {}\nnode: {}",
+	} {
+		if _, err := schema.Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("Load(%q): expected error", bad)
+		}
+	}
+}
+
+func TestValidateNode(t *testing.T) {
+	s := load(t)
+	node := &katapultpro.Node{ID: "n1", Attributes: katapultpro.EntityAttributeList{
+		"node_type":   {"-a": "tower"},
+		"scid":        {"-a": "012A", "-b": "013"},
+		"pole_height": {"-a": "tall"},
+		"note":        {"-a": "one", "-b": "two"},
+		"bogus":       {"-a": true},
+	}}
+	want := []string{
+		"node n1: bogus is not in the schema",
+		"node n1: node_type (-a) must be one of pole, reference, got \"tower\"",
+		"node n1: pole_height (-a) must be a number, got \"tall\"",
+		"node n1: scid has 2 instances but allows one",
+	}
+	if got := problems(s.ValidateNode(node)); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s", strings.Join(got, "\n"))
+	}
+
+	conn := &katapultpro.Connection{ID: "c1", Attributes: katapultpro.EntityAttributeList{"connection_type": {"-a": "underground cable"}, "other": {"-a": 1}},
+		Sections: map[string]katapultpro.EmbeddedSection{"s1": {MultiAttributes: katapultpro.EntityAttributeList{"anything": {"-a": 1}}}}}
+	if got := s.ValidateConnection(conn); len(got) != 0 {
+		t.Errorf("connection violations: %v", got)
+	}
+}
+
+func TestValidateRequests(t *testing.T) {
+	s := load(t)
+	err := s.ValidateCreateNode(&katapultpro.CreateNodeRequest{AddAttributes: map[string]interface{}{"node_type": "pole", "pole_height": 10}})
+	var verr *schema.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v", err)
+	}
+	if got := strings.Join(problems(verr.Violations), "\n"); got != "node: pole_height must be at least 20, got 10\nnode: scid is required" {
+		t.Errorf("create violations:\n%s", got)
+	}
+	if err := s.ValidateCreateNode(&katapultpro.CreateNodeRequest{AddAttributes: map[string]interface{}{"node_type": "pole", "scid": "001"}}); err != nil {
+		t.Errorf("valid create: %v", err)
+	}
+
+	// Without the current node, only the values sent and removals are checked.
+	if err := s.ValidateUpdateNode("n1", &katapultpro.UpdateNodeRequest{AddAttributes: map[string]interface{}{"pole_height": 40}}, nil); err != nil {
+		t.Errorf("partial update: %v", err)
+	}
+	if err := s.ValidateUpdateNode("n1", &katapultpro.UpdateNodeRequest{RemoveAttributes: []string{"scid"}}, nil); err == nil {
+		t.Error("removing a required attribute: expected error")
+	}
+
+	// With it, the result is checked: adding a second scid instance breaks cardinality.
+	current := &katapultpro.Node{ID: "n1", Attributes: katapultpro.EntityAttributeList{"node_type": {"-a": "pole"}, "scid": {"-a": "001"}}}
+	err = s.ValidateUpdateNode("n1", &katapultpro.UpdateNodeRequest{AddAttributes: map[string]interface{}{"scid": "002"}}, current)
+	if !errors.As(err, &verr) || verr.Violations[0].Problem != "has 2 instances but allows one" {
+		t.Errorf("update with current: %v", err)
+	}
+	replace := &katapultpro.UpdateNodeRequest{Attributes: katapultpro.EntityAttributeList{"scid": {"-a": "002"}}}
+	if err := s.ValidateUpdateNode("n1", replace, current); err != nil {
+		t.Errorf("replacing the instance: %v", err)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Audit"})
+	srv.AddNode(job.ID, katapultpro.Node{ID: "n1", Attributes: katapultpro.EntityAttributeList{"node_type": {"-a": "pole"}, "scid": {"-a": "001"}}})
+	srv.AddNode(job.ID, katapultpro.Node{ID: "n2", Attributes: katapultpro.EntityAttributeList{"node_type": {"-a": "pole"}}})
+
+	vs, err := load(t).Audit(context.Background(), srv.Client().Job(job.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := problems(vs); len(got) != 1 || got[0] != "node n2: scid is required" {
+		t.Errorf("audit = %v", got)
+	}
+}
+
+func TestLoad_NumericAndBooleanEnums(t *testing.T) {
+	y, err := schema.Load(strings.NewReader("node:\n  attributes:\n    phases: {type: integer, enum: [1, 2, 3]}\n    energized: {type: boolean, enum: [true]}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := schema.Load(strings.NewReader(`{"node":{"attributes":{"phases":{"type":"integer","enum":[1,2]},"energized":{"type":"boolean","enum":[true]}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	node := &katapultpro.Node{ID: "n1", Attributes: katapultpro.EntityAttributeList{"phases": {"-a": 3}, "energized": {"-a": false}}}
+	want := []string{
+		"node n1: energized (-a) must be one of true, got \"false\"",
+		"node n1: phases (-a) must be one of 1, 2, got \"3\"",
+	}
+	if got := problems(j.ValidateNode(node)); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("json violations:\n%s", strings.Join(got, "\n"))
+	}
+	if got := problems(y.ValidateNode(node)); len(got) != 1 || got[0] != want[0] {
+		t.Errorf("yaml violations: %v", got)
+	}
+	node.Attributes = katapultpro.EntityAttributeList{"phases": {"-a": "2"}, "energized": {"-a": true}}
+	if got := y.ValidateNode(node); len(got) != 0 {
+		t.Errorf("valid node: %v", got)
+	}
+
+	if _, err := schema.Load(strings.NewReader(`{"node":{"attributes":{"x":{"enum":[null]}}}}`)); err == nil {
+		t.Error("null enum value: expected error")
+	}
+}
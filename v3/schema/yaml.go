@@ -0,0 +1,406 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseYAML decodes the subset of YAML that schema files need: block mappings and sequences,
+// flow sequences and mappings ([a, b], {a: 1}), quoted and plain scalars, and comments. Anchors,
+// tags, block scalars (| and >), and multiple documents are rejected.
+func parseYAML(src string) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		if strings.Contains(raw, "\t") && strings.TrimLeft(raw, " ") != strings.TrimLeft(raw, " \t") {
+			return nil, fmt.Errorf("schema: yaml line %d: tabs are not allowed in indentation", i+1)
+		}
+		text := strings.TrimRight(stripComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || (trimmed == "---" && len(p.lines) == 0) {
+			continue
+		}
+		if trimmed == "---" || trimmed == "..." {
+			return nil, fmt.Errorf("schema: yaml line %d: multiple documents are not supported", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(p.lines) == 0 {
+		return nil, nil
+	}
+	v, err := p.block(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return v, nil
+}
+
+type yamlLine struct {
+	num, indent int
+	text        string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) errorf(format string, args ...any) error {
+	num := 0
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	} else if len(p.lines) > 0 {
+		num = p.lines[len(p.lines)-1].num
+	}
+	return fmt.Errorf("schema: yaml line %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+func isSeqItem(text string) bool { return text == "-" || strings.HasPrefix(text, "- ") }
+
+// block parses the mapping or sequence starting at the current line, which has the given indent.
+func (p *yamlParser) block(indent int) (any, error) {
+	if isSeqItem(p.lines[p.pos].text) {
+		return p.sequence(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) sequence(indent int) (any, error) {
+	out := []any{}
+	for p.pos < len(p.lines) {
+		l := &p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent || !isSeqItem(l.text) {
+			return nil, p.errorf("unexpected indentation")
+		}
+		rest := strings.TrimLeft(strings.TrimPrefix(l.text, "-"), " ")
+		if rest == "" {
+			p.pos++
+			v, err := p.nested(indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+			continue
+		}
+		if _, _, ok := splitKey(rest); ok && !strings.HasPrefix(rest, "[") && !strings.HasPrefix(rest, "{") {
+			// "- key: value" starts a mapping indented to the key's column.
+			l.indent += len(l.text) - len(rest)
+			l.text = rest
+			v, err := p.mapping(l.indent)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+			continue
+		}
+		v, err := scalar(rest)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		out = append(out, v)
+		p.pos++
+	}
+	return out, nil
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	out := map[string]any{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent || isSeqItem(l.text) {
+			return nil, p.errorf("unexpected indentation")
+		}
+		key, rest, ok := splitKey(l.text)
+		if !ok {
+			return nil, p.errorf("expected \"key: value\"")
+		}
+		if _, dup := out[key]; dup {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		p.pos++
+		if rest == "" {
+			v, err := p.nested(indent)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = v
+			continue
+		}
+		v, err := scalar(rest)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("%v", err)
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// nested parses the value of a key or sequence item with nothing after its colon or dash: a deeper
+// block, a sequence at the same indent as a mapping key, or null.
+func (p *yamlParser) nested(indent int) (any, error) {
+	if p.pos == len(p.lines) {
+		return nil, nil
+	}
+	next := p.lines[p.pos]
+	if next.indent > indent || (next.indent == indent && isSeqItem(next.text) && !p.inSequence(indent)) {
+		return p.block(next.indent)
+	}
+	return nil, nil
+}
+
+// inSequence reports whether the line before the current one is an item of a sequence at indent,
+// in which case a following "- " line is its sibling rather than a nested value.
+func (p *yamlParser) inSequence(indent int) bool {
+	prev := p.lines[p.pos-1]
+	return prev.indent == indent && isSeqItem(prev.text)
+}
+
+// splitKey splits "key: value" at the first colon followed by a space or the end of the line,
+// outside quotes. The key may be quoted.
+func splitKey(text string) (key, rest string, ok bool) {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 {
+				quote = c
+			}
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			k := strings.TrimSpace(text[:i])
+			if k == "" {
+				return "", "", false
+			}
+			if k[0] == '"' || k[0] == '\'' {
+				v, err := scalar(k)
+				s, isStr := v.(string)
+				if err != nil || !isStr {
+					return "", "", false
+				}
+				k = s
+			}
+			return k, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripComment removes a "#" comment that starts the line or follows a space, outside quotes.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// scalar parses a scalar or flow collection that makes up the whole of s.
+func scalar(s string) (any, error) {
+	f := &flow{s: s}
+	v, err := f.value()
+	if err != nil {
+		return nil, err
+	}
+	f.space()
+	if f.i != len(f.s) {
+		return nil, fmt.Errorf("unexpected %q after value", f.s[f.i:])
+	}
+	return v, nil
+}
+
+// flow parses flow-style values.
+type flow struct {
+	s string
+	i int
+}
+
+func (f *flow) space() {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+}
+
+func (f *flow) value() (any, error) {
+	f.space()
+	if f.i == len(f.s) {
+		return nil, nil
+	}
+	switch c := f.s[f.i]; c {
+	case '[':
+		return f.seq()
+	case '{':
+		return f.mapping()
+	case '"':
+		return f.doubleQuoted()
+	case '\'':
+		return f.singleQuoted()
+	case '&', '*', '!', '|', '>', '%', '@', '`':
+		return nil, fmt.Errorf("unsupported yaml syntax %q", string(c))
+	}
+	return f.plain(), nil
+}
+
+func (f *flow) seq() (any, error) {
+	f.i++ // [
+	out := []any{}
+	for {
+		f.space()
+		if f.i < len(f.s) && f.s[f.i] == ']' {
+			f.i++
+			return out, nil
+		}
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		f.space()
+		if f.i < len(f.s) && f.s[f.i] == ',' {
+			f.i++
+			continue
+		}
+		if f.i < len(f.s) && f.s[f.i] == ']' {
+			f.i++
+			return out, nil
+		}
+		return nil, fmt.Errorf("unterminated flow sequence")
+	}
+}
+
+func (f *flow) mapping() (any, error) {
+	f.i++ // {
+	out := map[string]any{}
+	for {
+		f.space()
+		if f.i < len(f.s) && f.s[f.i] == '}' {
+			f.i++
+			return out, nil
+		}
+		k, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			key = fmt.Sprint(k)
+		}
+		f.space()
+		if f.i >= len(f.s) || f.s[f.i] != ':' {
+			return nil, fmt.Errorf("expected ':' after flow mapping key %q", key)
+		}
+		f.i++
+		v, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+		f.space()
+		if f.i < len(f.s) && f.s[f.i] == ',' {
+			f.i++
+			continue
+		}
+		if f.i < len(f.s) && f.s[f.i] == '}' {
+			f.i++
+			return out, nil
+		}
+		return nil, fmt.Errorf("unterminated flow mapping")
+	}
+}
+
+func (f *flow) doubleQuoted() (any, error) {
+	start := f.i
+	for f.i++; f.i < len(f.s); f.i++ {
+		switch f.s[f.i] {
+		case '\\':
+			f.i++
+		case '"':
+			f.i++
+			var s string
+			if err := json.Unmarshal([]byte(f.s[start:f.i]), &s); err != nil {
+				return nil, fmt.Errorf("invalid quoted string %s", f.s[start:f.i])
+			}
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("unterminated quoted string")
+}
+
+func (f *flow) singleQuoted() (any, error) {
+	var b strings.Builder
+	for f.i++; f.i < len(f.s); f.i++ {
+		if f.s[f.i] == '\'' {
+			if f.i+1 < len(f.s) && f.s[f.i+1] == '\'' {
+				b.WriteByte('\'')
+				f.i++
+				continue
+			}
+			f.i++
+			return b.String(), nil
+		}
+		b.WriteByte(f.s[f.i])
+	}
+	return nil, fmt.Errorf("unterminated quoted string")
+}
+
+// plain reads an unquoted scalar up to the end of the value and resolves null, booleans, and
+// numbers. Inside a flow collection it stops at ',', ']', '}', and ": ".
+func (f *flow) plain() any {
+	start := f.i
+	for ; f.i < len(f.s); f.i++ {
+		c := f.s[f.i]
+		if f.inFlow() && (c == ',' || c == ']' || c == '}' || (c == ':' && (f.i+1 == len(f.s) || f.s[f.i+1] == ' '))) {
+			break
+		}
+	}
+	text := strings.TrimSpace(f.s[start:f.i])
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if n, err := strconv.ParseFloat(text, 64); err == nil && strings.IndexFunc(text, isNumberRune) == -1 {
+		return n
+	}
+	return text
+}
+
+// inFlow reports whether the value being read is inside a flow collection.
+func (f *flow) inFlow() bool { return strings.HasPrefix(f.s, "[") || strings.HasPrefix(f.s, "{") }
+
+// isNumberRune reports runes strconv.ParseFloat accepts that YAML does not treat as numbers
+// (hex floats, "inf", "nan", underscores).
+func isNumberRune(r rune) bool {
+	return !(r >= '0' && r <= '9' || r == '.' || r == '-' || r == '+' || r == 'e' || r == 'E')
+}
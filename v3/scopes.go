@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/romer-pro/katapultpro-go-sdk/v3/connections"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/jobs"
 	"github.com/romer-pro/katapultpro-go-sdk/v3/nodes"
 	"github.com/romer-pro/katapultpro-go-sdk/v3/photos"
 	"github.com/romer-pro/katapultpro-go-sdk/v3/sections"
@@ -14,13 +15,19 @@ import (
 // Nodes(), Connections(), Photos(), and Traces() return domain clients that hold the scope;
 // call their methods without passing the client again, e.g. client.Job("id").Nodes().List(ctx).
 type JobScope struct {
-	c     *Client
+	do    Doer
 	jobID string
 }
 
 // Job returns a scope for the given job.
 func (c *Client) Job(jobID string) *JobScope {
-	return &JobScope{c: c, jobID: jobID}
+	return &JobScope{do: c, jobID: jobID}
+}
+
+// NewJobScope returns a scope for the given job that sends requests through do.
+// Use it to drive the typed domain clients with your own Doer (e.g. a fake in tests).
+func NewJobScope(do Doer, jobID string) *JobScope {
+	return &JobScope{do: do, jobID: jobID}
 }
 
 // JobID returns the scoped job ID.
@@ -28,47 +35,47 @@ func (s *JobScope) JobID() string { return s.jobID }
 
 // Get returns partial or full job data. Use opts.Paths to request specific fields.
 func (s *JobScope) Get(ctx context.Context, opts *GetJobOptions) (*Job, error) {
-	return s.c.GetJob(ctx, s.jobID, opts)
+	return jobs.NewClient(s.do).Get(ctx, s.jobID, opts)
 }
 
 // Update updates the job.
 func (s *JobScope) Update(ctx context.Context, req *UpdateJobRequest) (*Job, error) {
-	return s.c.UpdateJob(ctx, s.jobID, req)
+	return jobs.NewClient(s.do).Update(ctx, s.jobID, req)
 }
 
 // Status returns the job status (active or archived).
 func (s *JobScope) Status(ctx context.Context) (JobStatus, error) {
-	return s.c.GetJobStatus(ctx, s.jobID)
+	return jobs.NewClient(s.do).GetStatus(ctx, s.jobID)
 }
 
 // SetStatus sets the job status.
 func (s *JobScope) SetStatus(ctx context.Context, status JobStatus) error {
-	return s.c.UpdateJobStatus(ctx, s.jobID, status)
+	return jobs.NewClient(s.do).UpdateStatus(ctx, s.jobID, status)
 }
 
 // Nodes returns a nodes client for this job. No need to pass the client into its methods.
 func (s *JobScope) Nodes() *nodes.Client {
-	return nodes.NewClient(s.c, s.jobID)
+	return nodes.NewClient(s.do, s.jobID)
 }
 
 // Connections returns a connections client for this job. Use .Sections(connectionID) for sections.
 func (s *JobScope) Connections() *connections.Client {
-	return connections.NewClient(s.c, s.jobID)
+	return connections.NewClient(s.do, s.jobID)
 }
 
 // Photos returns a photos client for this job.
 func (s *JobScope) Photos() *photos.Client {
-	return photos.NewClient(s.c, s.jobID)
+	return photos.NewClient(s.do, s.jobID)
 }
 
 // Traces returns a traces client for this job.
 func (s *JobScope) Traces() *traces.Client {
-	return traces.NewClient(s.c, s.jobID)
+	return traces.NewClient(s.do, s.jobID)
 }
 
 // Sections returns a job-scoped sections client for direct section access without connectionID.
 // Use this when you have a sectionID but not the connectionID.
 // For connection-scoped section operations, use Connections().Connection(connID).Sections() instead.
 func (s *JobScope) Sections() *sections.JobClient {
-	return sections.NewJobClient(s.c, s.jobID)
+	return sections.NewJobClient(s.do, s.jobID)
 }
@@ -27,7 +27,7 @@ func (c *Client) UpdateSection(ctx context.Context, jobID, connectionID, section
 	return sections.NewClient(c, jobID, connectionID).Update(ctx, sectionKey, req, opts)
 }
 
-// UploadSectionPhoto uploads a photo (image/jpeg) and associates it to the section (v3).
+// UploadSectionPhoto uploads a photo and associates it to the section (v3).
 func (c *Client) UploadSectionPhoto(ctx context.Context, jobID, connectionID, sectionID string, imageData io.Reader, opts *UploadSectionPhotoOptions) (*Photo, error) {
 	return sections.NewClient(c, jobID, connectionID).UploadPhoto(ctx, sectionID, imageData, opts)
 }
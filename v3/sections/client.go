@@ -64,7 +64,7 @@ func (s *SectionScope) Update(ctx context.Context, req *UpdateSectionRequest, op
 	return &section, nil
 }
 
-// UploadPhoto uploads a photo (image/jpeg) and associates it to the section.
+// UploadPhoto uploads a photo and associates it to the section.
 func (s *SectionScope) UploadPhoto(ctx context.Context, imageData io.Reader, opts *UploadSectionPhotoOptions) (*photos.Photo, error) {
 	path := "v3/jobs/" + s.jobID + "/connections/" + s.connectionID + "/sections/" + s.sectionKey + "/photos"
 	var q url.Values
@@ -72,11 +72,11 @@ func (s *SectionScope) UploadPhoto(ctx context.Context, imageData io.Reader, opt
 		q = url.Values{}
 		q.Set("association_value", opts.AssociationValue.String())
 	}
-	var photo photos.Photo
-	if err := s.do.DoWithBody(ctx, http.MethodPost, path, q, "image/jpeg", imageData, &photo); err != nil {
-		return nil, err
+	var upload *photos.UploadOptions
+	if opts != nil {
+		upload = &opts.UploadOptions
 	}
-	return &photo, nil
+	return photos.Send(ctx, s.do, path, q, imageData, upload)
 }
 
 // Delete deletes the section.
@@ -130,7 +130,7 @@ func (c *Client) Update(ctx context.Context, sectionKey string, req *UpdateSecti
 	return &section, nil
 }
 
-// UploadPhoto uploads a photo (image/jpeg) and associates it to the section (v3).
+// UploadPhoto uploads a photo and associates it to the section (v3).
 func (c *Client) UploadPhoto(ctx context.Context, sectionID string, imageData io.Reader, opts *UploadSectionPhotoOptions) (*photos.Photo, error) {
 	path := "v3/jobs/" + c.jobID + "/connections/" + c.connectionID + "/sections/" + sectionID + "/photos"
 	var q url.Values
@@ -138,11 +138,11 @@ func (c *Client) UploadPhoto(ctx context.Context, sectionID string, imageData io
 		q = url.Values{}
 		q.Set("association_value", opts.AssociationValue.String())
 	}
-	var photo photos.Photo
-	if err := c.do.DoWithBody(ctx, http.MethodPost, path, q, "image/jpeg", imageData, &photo); err != nil {
-		return nil, err
+	var upload *photos.UploadOptions
+	if opts != nil {
+		upload = &opts.UploadOptions
 	}
-	return &photo, nil
+	return photos.Send(ctx, c.do, path, q, imageData, upload)
 }
 
 // Delete deletes the specified section (v3).
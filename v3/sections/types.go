@@ -42,7 +42,9 @@ type UpdateSectionOptions struct {
 	OnlyIfExists bool
 }
 
-// UploadSectionPhotoOptions are optional query parameters for UploadSectionPhoto.
+// UploadSectionPhotoOptions are optional parameters for UploadSectionPhoto. The embedded UploadOptions
+// add content type detection, progress, size verification, and retries.
 type UploadSectionPhotoOptions struct {
 	AssociationValue photos.PhotoAssociationQuery
+	photos.UploadOptions
 }
@@ -0,0 +1,103 @@
+// Package snapshot records the entities of a job at one point in time: its nodes, connections,
+// sections, photos, and traces, each as the JSON encoding of its SDK type. The webhook package
+// diffs snapshots into events, and the lifecycle package exports one before archiving a job.
+//
+//	s, err := snapshot.Take(ctx, client.Job("job-123").API())
+//	fmt.Println(len(s.Entities[snapshot.EntityNode]))
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// EntityKind is the kind of job entity.
+type EntityKind string
+
+const (
+	EntityNode       EntityKind = "node"
+	EntityConnection EntityKind = "connection"
+	EntitySection    EntityKind = "section"
+	EntityPhoto      EntityKind = "photo"
+	EntityTrace      EntityKind = "trace"
+)
+
+// Snapshot is the state of a job's entities at one point in time, keyed by kind and entity ID.
+// Values are the JSON encoding of the SDK types so that comparisons are cheap and order-independent.
+// Section entity IDs are "connectionID/sectionID".
+type Snapshot struct {
+	JobID    string
+	TakenAt  time.Time
+	Entities map[EntityKind]map[string]json.RawMessage
+}
+
+// Take lists nodes, connections (with their sections), photos, and traces for the job.
+// Sections are read from the connection responses, so no per-connection requests are made.
+func Take(ctx context.Context, job katapultpro.JobAPI) (*Snapshot, error) {
+	s := &Snapshot{
+		JobID:    job.JobID(),
+		TakenAt:  time.Now(),
+		Entities: make(map[EntityKind]map[string]json.RawMessage),
+	}
+	nodes, err := job.Nodes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	for _, n := range nodes {
+		if err := s.put(EntityNode, n.ID, n); err != nil {
+			return nil, err
+		}
+	}
+	conns, err := job.Connections().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list connections: %w", err)
+	}
+	for _, c := range conns {
+		for id, sec := range c.Sections {
+			if err := s.put(EntitySection, c.ID+"/"+id, sec); err != nil {
+				return nil, err
+			}
+		}
+		c.Sections = nil
+		if err := s.put(EntityConnection, c.ID, c); err != nil {
+			return nil, err
+		}
+	}
+	photos, err := job.Photos().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list photos: %w", err)
+	}
+	for _, p := range photos {
+		if err := s.put(EntityPhoto, p.ID, p); err != nil {
+			return nil, err
+		}
+	}
+	traces, err := job.Traces().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list traces: %w", err)
+	}
+	for _, t := range traces {
+		if err := s.put(EntityTrace, t.ID, t); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *Snapshot) put(kind EntityKind, id string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %s %s: %w", kind, id, err)
+	}
+	m := s.Entities[kind]
+	if m == nil {
+		m = make(map[string]json.RawMessage)
+		s.Entities[kind] = m
+	}
+	m[id] = b
+	return nil
+}
@@ -39,6 +39,11 @@ type (
 	ListJobsOptions      = jobs.ListJobsOptions
 	GetJobOptions        = jobs.GetJobOptions
 	JobStatusResponse    = jobs.JobStatusResponse
+	MetadataPatch        = jobs.MetadataPatch
+	MetadataFilter       = jobs.MetadataFilter
+	MetadataCondition    = jobs.MetadataCondition
+	Sharing              = jobs.Sharing
+	SharingPatch         = jobs.SharingPatch
 )
 
 // Nodes domain types (re-exported).
@@ -92,6 +97,15 @@ type (
 	CreatePhotoCalibrationAnchorRequest = photos.CreatePhotoCalibrationAnchorRequest
 	UpdatePhotoCalibrationAnchorRequest = photos.UpdatePhotoCalibrationAnchorRequest
 	UpdatePhotoCalibrationAnchorOptions = photos.UpdatePhotoCalibrationAnchorOptions
+	PhotoUploadOptions                  = photos.UploadOptions
+	PhotoOpener                         = photos.Opener
+	PhotoSizeMismatchError              = photos.SizeMismatchError
+	PhotoImage                          = photos.Image
+	PhotoDownloadError                  = photos.DownloadError
+	Height                              = photos.Height
+	ElementSpec                         = photos.ElementSpec
+	ElementOptions                      = photos.ElementOptions
+	ElementError                        = photos.ElementError
 )
 
 // Photos scope types (re-exported for builder pattern).
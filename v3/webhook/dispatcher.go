@@ -0,0 +1,414 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint is a destination for Events. Secret is the HMAC key used to sign payloads.
+// Kinds filters which entity kinds are delivered; empty means all kinds.
+type Endpoint struct {
+	URL    string
+	Secret []byte
+	Kinds  []EntityKind
+}
+
+func (e Endpoint) wants(kind EntityKind) bool {
+	if len(e.Kinds) == 0 {
+		return true
+	}
+	for _, k := range e.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one Event bound for one Endpoint. Deliveries are stored as JSON files in the
+// dispatcher's spool directory until they succeed or are moved to the dead-letter directory.
+type Delivery struct {
+	ID          string    `json:"id"`
+	Endpoint    string    `json:"endpoint"`
+	Event       Event     `json:"event"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastStatus  int       `json:"last_status,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// Default retry settings used by NewDispatcher.
+const (
+	DefaultMaxAttempts    = 8
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = 5 * time.Minute
+	DefaultPollInterval   = time.Second
+)
+
+// Option configures a Dispatcher.
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the http.Client used for deliveries. The default is http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(d *Dispatcher) {
+		if hc != nil {
+			d.httpClient = hc
+		}
+	}
+}
+
+// WithMaxAttempts sets how many times a delivery is tried before it is dead-lettered.
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff sets the exponential backoff between attempts: initial after the first failure,
+// doubling up to max.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(d *Dispatcher) {
+		if initial >= 0 {
+			d.initialBackoff = initial
+		}
+		if max >= initial {
+			d.maxBackoff = max
+		}
+	}
+}
+
+// WithPollInterval sets how often Run rescans the spool for deliveries that became due.
+func WithPollInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		if interval > 0 {
+			d.pollInterval = interval
+		}
+	}
+}
+
+// Dispatcher POSTs Events to Endpoints with at-least-once semantics. Enqueue persists a Delivery per
+// endpoint before returning; a Delivery is removed only after the endpoint answers 2xx, so a crash
+// between sending and removing causes a redelivery rather than a loss. Receivers should deduplicate
+// on HeaderEventID. A Dispatcher is safe for concurrent use.
+type Dispatcher struct {
+	dir            string
+	endpoints      []Endpoint
+	httpClient     *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	pollInterval   time.Duration
+	now            func() time.Time
+
+	mu     sync.Mutex // serializes spool scans so a delivery is never sent twice concurrently
+	notify chan struct{}
+}
+
+// NewDispatcher returns a Dispatcher that spools deliveries under dir (created if missing).
+// Pending deliveries live in dir/pending and exhausted ones in dir/dead.
+func NewDispatcher(dir string, endpoints []Endpoint, opts ...Option) (*Dispatcher, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("webhook: at least one endpoint is required")
+	}
+	for _, e := range endpoints {
+		if e.URL == "" {
+			return nil, errors.New("webhook: endpoint URL is required")
+		}
+	}
+	d := &Dispatcher{
+		dir:            dir,
+		endpoints:      endpoints,
+		httpClient:     http.DefaultClient,
+		maxAttempts:    DefaultMaxAttempts,
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		pollInterval:   DefaultPollInterval,
+		now:            time.Now,
+		notify:         make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	for _, sub := range []string{"pending", "dead"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("create spool: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// Enqueue spools a Delivery for every endpoint interested in each event. A delivery that is already
+// pending for the same event and endpoint is left as is, so re-enqueuing a change is harmless.
+func (d *Dispatcher) Enqueue(events ...Event) error {
+	for _, ev := range events {
+		for _, ep := range d.endpoints {
+			if !ep.wants(ev.Kind) {
+				continue
+			}
+			del := &Delivery{
+				ID:          deliveryID(ev.ID, ep.URL),
+				Endpoint:    ep.URL,
+				Event:       ev,
+				NextAttempt: d.now(),
+			}
+			path := d.path("pending", del.ID)
+			if _, err := os.Stat(path); err == nil {
+				continue
+			}
+			if err := writeDelivery(path, del); err != nil {
+				return err
+			}
+		}
+	}
+	select {
+	case d.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers spooled deliveries until ctx is done, rescanning after each Enqueue and every poll interval.
+// It returns ctx.Err() when stopped.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := d.Flush(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-d.notify:
+		}
+	}
+}
+
+// Flush makes one pass over the spool and attempts every delivery that is due.
+// Delivery failures are recorded on the Delivery, not returned; the error is for spool I/O problems.
+func (d *Dispatcher) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending, err := d.list("pending")
+	if err != nil {
+		return err
+	}
+	for _, del := range pending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if del.NextAttempt.After(d.now()) {
+			continue
+		}
+		if err := d.attempt(ctx, del); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pending returns the deliveries waiting to be sent, oldest first.
+func (d *Dispatcher) Pending() ([]*Delivery, error) {
+	return d.list("pending")
+}
+
+// DeadLetters returns the deliveries that exhausted their attempts or were rejected permanently.
+func (d *Dispatcher) DeadLetters() ([]*Delivery, error) {
+	return d.list("dead")
+}
+
+// Redrive moves a dead-lettered delivery back to the pending spool with its attempt count reset.
+// id must be a delivery ID as returned by DeadLetters.
+func (d *Dispatcher) Redrive(id string) error {
+	if id == "" || id == "." || id == ".." || filepath.Base(id) != id || strings.ContainsAny(id, `/\`) {
+		return fmt.Errorf("webhook: invalid delivery ID %q", id)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	del, err := readDelivery(d.path("dead", id))
+	if err != nil {
+		return err
+	}
+	del.Attempts = 0
+	del.NextAttempt = d.now()
+	del.LastError = ""
+	del.LastStatus = 0
+	if err := writeDelivery(d.path("pending", id), del); err != nil {
+		return err
+	}
+	return os.Remove(d.path("dead", id))
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, del *Delivery) error {
+	ep, ok := d.endpoint(del.Endpoint)
+	if !ok {
+		del.LastError = "endpoint no longer configured"
+		return d.bury(del)
+	}
+	del.Attempts++
+	status, err := d.send(ctx, ep, del.Event)
+	if err == nil {
+		return os.Remove(d.path("pending", del.ID))
+	}
+	if ctx.Err() != nil {
+		// Shutting down; the attempt did not count.
+		del.Attempts--
+		return nil
+	}
+	del.LastStatus = status
+	del.LastError = err.Error()
+	if permanent(status) || del.Attempts >= d.maxAttempts {
+		return d.bury(del)
+	}
+	del.NextAttempt = d.now().Add(d.backoff(del.Attempts))
+	return writeDelivery(d.path("pending", del.ID), del)
+}
+
+func (d *Dispatcher) send(ctx context.Context, ep Endpoint, ev Event) (int, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return 0, fmt.Errorf("encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	ts := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, ev.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(ep.Secret, ts, body))
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// permanent reports whether a status means retrying cannot help (client errors other than timeouts and throttling).
+func permanent(status int) bool {
+	return status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.initialBackoff
+	for i := 1; i < attempts && b < d.maxBackoff; i++ {
+		b *= 2
+	}
+	if b > d.maxBackoff {
+		b = d.maxBackoff
+	}
+	return b
+}
+
+func (d *Dispatcher) bury(del *Delivery) error {
+	if err := writeDelivery(d.path("dead", del.ID), del); err != nil {
+		return err
+	}
+	return os.Remove(d.path("pending", del.ID))
+}
+
+func (d *Dispatcher) endpoint(url string) (Endpoint, bool) {
+	for _, ep := range d.endpoints {
+		if ep.URL == url {
+			return ep, true
+		}
+	}
+	return Endpoint{}, false
+}
+
+func (d *Dispatcher) path(sub, id string) string {
+	return filepath.Join(d.dir, sub, id+".json")
+}
+
+func (d *Dispatcher) list(sub string) ([]*Delivery, error) {
+	entries, err := os.ReadDir(filepath.Join(d.dir, sub))
+	if err != nil {
+		return nil, fmt.Errorf("read spool: %w", err)
+	}
+	var out []*Delivery
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		del, err := readDelivery(filepath.Join(d.dir, sub, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, del)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].NextAttempt.Equal(out[j].NextAttempt) {
+			return out[i].NextAttempt.Before(out[j].NextAttempt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func deliveryID(eventID, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return eventID + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+func readDelivery(path string) (*Delivery, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read delivery: %w", err)
+	}
+	var del Delivery
+	if err := json.Unmarshal(b, &del); err != nil {
+		return nil, fmt.Errorf("decode delivery %s: %w", filepath.Base(path), err)
+	}
+	return &del, nil
+}
+
+// writeDelivery writes del to path atomically (temp file, fsync, rename) so a crash never leaves a torn file.
+func writeDelivery(path string, del *Delivery) error {
+	b, err := json.Marshal(del)
+	if err != nil {
+		return fmt.Errorf("encode delivery: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write delivery: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write delivery: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write delivery: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write delivery: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write delivery: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Package webhook relays Katapult Pro job changes to HTTP endpoints.
+// A Watcher polls a job through the v3 client and turns differences between
+// snapshots into Events; a Dispatcher POSTs each Event as signed JSON to the
+// configured endpoints with retry, spooling deliveries on disk so they survive
+// restarts (at-least-once delivery). Receivers verify payloads with Verify.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/snapshot"
+)
+
+// EntityKind is the kind of job entity an Event refers to.
+type EntityKind = snapshot.EntityKind
+
+const (
+	EntityNode       = snapshot.EntityNode
+	EntityConnection = snapshot.EntityConnection
+	EntitySection    = snapshot.EntitySection
+	EntityPhoto      = snapshot.EntityPhoto
+	EntityTrace      = snapshot.EntityTrace
+)
+
+// ChangeType describes what happened to an entity between two snapshots.
+type ChangeType string
+
+const (
+	ChangeCreated ChangeType = "created"
+	ChangeUpdated ChangeType = "updated"
+	ChangeDeleted ChangeType = "deleted"
+)
+
+// Event is a single detected change to a job entity. It is the JSON payload POSTed to endpoints.
+// Before is empty for ChangeCreated and After is empty for ChangeDeleted.
+type Event struct {
+	ID         string          `json:"id"` // Stable for the same change; receivers can use it to deduplicate.
+	JobID      string          `json:"job_id"`
+	Kind       EntityKind      `json:"kind"`
+	EntityID   string          `json:"entity_id"`
+	Change     ChangeType      `json:"change"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	DetectedAt time.Time       `json:"detected_at"`
+}
+
+// Snapshot is the state of a job's entities at one point in time (see package snapshot).
+type Snapshot = snapshot.Snapshot
+
+// TakeSnapshot lists nodes, connections (with their sections), photos, and traces for the job
+// (see snapshot.Take).
+func TakeSnapshot(ctx context.Context, job *katapultpro.JobScope) (*Snapshot, error) {
+	return snapshot.Take(ctx, job.API())
+}
+
+// Diff returns the Events that turn prev into next, ordered by kind and entity ID.
+// A nil prev is treated as an empty job, so every entity in next is reported as created.
+// Section entity IDs are "connectionID/sectionID".
+func Diff(prev, next *Snapshot) []Event {
+	if next == nil {
+		return nil
+	}
+	empty := map[string]json.RawMessage{}
+	var events []Event
+	for _, kind := range []EntityKind{EntityNode, EntityConnection, EntitySection, EntityPhoto, EntityTrace} {
+		before := empty
+		if prev != nil && prev.Entities[kind] != nil {
+			before = prev.Entities[kind]
+		}
+		after := next.Entities[kind]
+		if after == nil {
+			after = empty
+		}
+		ids := make([]string, 0, len(before)+len(after))
+		for id := range before {
+			ids = append(ids, id)
+		}
+		for id := range after {
+			if _, ok := before[id]; !ok {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			b, hadBefore := before[id]
+			a, hasAfter := after[id]
+			var change ChangeType
+			switch {
+			case !hadBefore:
+				change = ChangeCreated
+			case !hasAfter:
+				change = ChangeDeleted
+			case !bytes.Equal(b, a):
+				change = ChangeUpdated
+			default:
+				continue
+			}
+			events = append(events, newEvent(next.JobID, kind, id, change, b, a, next.TakenAt))
+		}
+	}
+	return events
+}
+
+func newEvent(jobID string, kind EntityKind, id string, change ChangeType, before, after json.RawMessage, at time.Time) Event {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(jobID), []byte(kind), []byte(id), []byte(change), before, after} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return Event{
+		ID:         hex.EncodeToString(h.Sum(nil))[:32],
+		JobID:      jobID,
+		Kind:       kind,
+		EntityID:   id,
+		Change:     change,
+		Before:     before,
+		After:      after,
+		DetectedAt: at.UTC(),
+	}
+}
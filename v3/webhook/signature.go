@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names set on every delivery.
+const (
+	HeaderEventID   = "X-Katapult-Event-Id"
+	HeaderTimestamp = "X-Katapult-Timestamp"
+	HeaderSignature = "X-Katapult-Signature"
+)
+
+// ErrInvalidSignature is returned by Verify when the signature does not match or the timestamp is outside the tolerance.
+var ErrInvalidSignature = errors.New("webhook: invalid signature")
+
+// Sign returns the HeaderSignature value for body sent at timestamp (Unix seconds).
+// The MAC covers "<timestamp>.<body>" so a captured payload cannot be replayed with a new timestamp.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery's HeaderTimestamp and HeaderSignature values against body.
+// tolerance bounds how old (or how far in the future) the timestamp may be; zero disables the check.
+func Verify(secret []byte, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrInvalidSignature
+		}
+	}
+	want := Sign(secret, ts, body)
+	if !strings.HasPrefix(signatureHeader, "sha256=") || !hmac.Equal([]byte(want), []byte(signatureHeader)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// DefaultWatchInterval is the polling interval used when Watcher.Interval is zero.
+const DefaultWatchInterval = time.Minute
+
+// Watcher polls one job and enqueues the changes between consecutive snapshots on a Dispatcher.
+// The first poll only records a baseline unless EmitInitial is set.
+type Watcher struct {
+	Job         *katapultpro.JobScope
+	Dispatcher  *Dispatcher
+	Interval    time.Duration
+	EmitInitial bool // If true, the first poll reports every existing entity as created.
+
+	// OnError, if set, is called when a poll fails. Polling continues with the previous baseline.
+	OnError func(error)
+
+	last *Snapshot
+}
+
+// Poll takes one snapshot, enqueues the changes since the previous one, and returns them.
+func (w *Watcher) Poll(ctx context.Context) ([]Event, error) {
+	snap, err := TakeSnapshot(ctx, w.Job)
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	if w.last != nil || w.EmitInitial {
+		events = Diff(w.last, snap)
+	}
+	if len(events) > 0 {
+		if err := w.Dispatcher.Enqueue(events...); err != nil {
+			return nil, err
+		}
+	}
+	w.last = snap
+	return events, nil
+}
+
+// Run polls until ctx is done and returns ctx.Err(). Delivery happens in Dispatcher.Run,
+// which callers usually start in a separate goroutine.
+func (w *Watcher) Run(ctx context.Context) error {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Poll(ctx); err != nil && ctx.Err() == nil && w.OnError != nil {
+			w.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/webhook"
+)
+
+func TestDiff(t *testing.T) {
+	prev := &webhook.Snapshot{JobID: "j1", Entities: map[webhook.EntityKind]map[string]json.RawMessage{
+		webhook.EntityNode: {"n1": json.RawMessage(`{"id":"n1"}`), "n2": json.RawMessage(`{"id":"n2"}`)},
+	}}
+	next := &webhook.Snapshot{JobID: "j1", Entities: map[webhook.EntityKind]map[string]json.RawMessage{
+		webhook.EntityNode: {"n1": json.RawMessage(`{"id":"n1","latitude":1}`), "n3": json.RawMessage(`{"id":"n3"}`)},
+	}}
+	events := webhook.Diff(prev, next)
+	want := map[string]webhook.ChangeType{"n1": webhook.ChangeUpdated, "n2": webhook.ChangeDeleted, "n3": webhook.ChangeCreated}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for _, ev := range events {
+		if want[ev.EntityID] != ev.Change {
+			t.Errorf("%s: got %s want %s", ev.EntityID, ev.Change, want[ev.EntityID])
+		}
+	}
+	if again := webhook.Diff(prev, next); again[0].ID != events[0].ID {
+		t.Errorf("event IDs should be stable, got %s and %s", events[0].ID, again[0].ID)
+	}
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	secret := []byte("s3cret")
+	var got webhook.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify(secret, r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature), body, time.Minute); err != nil {
+			t.Errorf("verify: %v", err)
+		}
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d, err := webhook.NewDispatcher(t.TempDir(), []webhook.Endpoint{{URL: srv.URL, Secret: secret}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := webhook.Event{ID: "e1", JobID: "j1", Kind: webhook.EntityNode, EntityID: "n1", Change: webhook.ChangeCreated}
+	if err := d.Enqueue(ev); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "e1" || got.EntityID != "n1" {
+		t.Errorf("got event %+v", got)
+	}
+	if pending, _ := d.Pending(); len(pending) != 0 {
+		t.Errorf("expected empty spool, got %d", len(pending))
+	}
+}
+
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	d, err := webhook.NewDispatcher(dir, []webhook.Endpoint{{URL: srv.URL, Secret: []byte("k")}},
+		webhook.WithMaxAttempts(3), webhook.WithBackoff(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Enqueue(webhook.Event{ID: "e1", Kind: webhook.EntityNode}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := d.Flush(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls)
+	}
+	dead, _ := d.DeadLetters()
+	if len(dead) != 1 || dead[0].Attempts != 3 || dead[0].LastStatus != http.StatusBadGateway {
+		t.Fatalf("got dead letters %+v", dead)
+	}
+
+	// A new dispatcher over the same spool sees the dead letter and can redrive it.
+	d2, _ := webhook.NewDispatcher(dir, []webhook.Endpoint{{URL: srv.URL, Secret: []byte("k")}})
+	if err := d2.Redrive(dead[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := d2.Pending(); len(pending) != 1 || pending[0].Attempts != 0 {
+		t.Errorf("got pending %+v", pending)
+	}
+	for _, id := range []string{"../../x", "a/b", "..", ""} {
+		if err := d2.Redrive(id); err == nil {
+			t.Errorf("Redrive(%q): expected error", id)
+		}
+	}
+}
+
+func TestDispatcher_PermanentFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	d, _ := webhook.NewDispatcher(t.TempDir(), []webhook.Endpoint{{URL: srv.URL}}, webhook.WithBackoff(0, 0))
+	_ = d.Enqueue(webhook.Event{ID: "e1"})
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if dead, _ := d.DeadLetters(); len(dead) != 1 {
+		t.Errorf("expected 400 to dead-letter immediately, got %d dead letters", len(dead))
+	}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	var nodes atomic.Value
+	nodes.Store(`[{"id":"n1"}]`)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := `[]`
+		if r.URL.Path == "/v3/jobs/j1/nodes" {
+			data = nodes.Load().(string)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":` + data + `,"meta":{"token_count":9999,"last_refill_time":0}}`))
+	}))
+	defer api.Close()
+
+	client, _ := katapultpro.NewClient("key", katapultpro.WithBaseURL(api.URL))
+	d, _ := webhook.NewDispatcher(t.TempDir(), []webhook.Endpoint{{URL: "http://example.invalid"}})
+	w := &webhook.Watcher{Job: client.Job("j1"), Dispatcher: d}
+
+	ctx := context.Background()
+	if events, err := w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("baseline poll: events=%v err=%v", events, err)
+	}
+	nodes.Store(`[{"id":"n1"},{"id":"n2"}]`)
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].EntityID != "n2" || events[0].Change != webhook.ChangeCreated {
+		t.Errorf("got events %+v", events)
+	}
+	if pending, _ := d.Pending(); len(pending) != 1 {
+		t.Errorf("expected 1 spooled delivery, got %d", len(pending))
+	}
+}
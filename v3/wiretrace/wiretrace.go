@@ -0,0 +1,402 @@
+// Package wiretrace builds and checks traces: the records that link the wire elements measured on
+// different photos into one cable across a span.
+//
+// Trace.Items is a map of photo ID to element type to element ID. Assemble builds it from a list of
+// (photo, element) references, and an Index answers the questions Items makes awkward:
+//
+//	idx, err := wiretrace.Load(ctx, client.Job("job-123"))
+//	for _, m := range idx.Measurements(traceID) {
+//		fmt.Println(m.NodeID, m.PhotoID, m.ElementID, m.Height)
+//	}
+//	for _, p := range idx.Check() {
+//		fmt.Println(p) // orphan traces and wires whose _trace points to a missing trace
+//	}
+//
+// Link validates the references against the index, creates the trace, and sets the trace on each
+// element, which is how the API adds an element to a trace's items.
+package wiretrace
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+)
+
+// DefaultConcurrency is the number of photos whose elements are fetched at once by Load.
+const DefaultConcurrency = 4
+
+// Ref identifies an element on a photo.
+type Ref struct {
+	PhotoID   string `json:"photo_id"`
+	ElementID string `json:"element_id"`
+}
+
+// Assemble returns base with Items listing refs as wire elements. Items already in base are kept.
+func Assemble(base katapultpro.Trace, refs ...Ref) katapultpro.Trace {
+	items := make(map[string]katapultpro.TraceItem, len(base.Items)+len(refs))
+	for photoID, item := range base.Items {
+		cp := make(katapultpro.TraceItem, len(item))
+		for typ, els := range item {
+			cp[typ] = make(map[string]bool, len(els))
+			for id, on := range els {
+				cp[typ][id] = on
+			}
+		}
+		items[photoID] = cp
+	}
+	for _, r := range refs {
+		item := items[r.PhotoID]
+		if item == nil {
+			item = katapultpro.TraceItem{}
+			items[r.PhotoID] = item
+		}
+		if item[string(katapultpro.ElementTypeWire)] == nil {
+			item[string(katapultpro.ElementTypeWire)] = map[string]bool{}
+		}
+		item[string(katapultpro.ElementTypeWire)][r.ElementID] = true
+	}
+	base.Items = items
+	return base
+}
+
+// Refs returns the elements a trace's items list, sorted by photo then element.
+func Refs(t katapultpro.Trace) []Ref {
+	var out []Ref
+	for photoID, item := range t.Items {
+		for _, els := range item {
+			for id, on := range els {
+				if on {
+					out = append(out, Ref{PhotoID: photoID, ElementID: id})
+				}
+			}
+		}
+	}
+	sortRefs(out)
+	return out
+}
+
+func sortRefs(refs []Ref) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].PhotoID != refs[j].PhotoID {
+			return refs[i].PhotoID < refs[j].PhotoID
+		}
+		return refs[i].ElementID < refs[j].ElementID
+	})
+}
+
+// Problem kinds.
+const (
+	ProblemMissingPhoto   = "missing_photo"   // A reference names a photo that is not in the job
+	ProblemMissingElement = "missing_element" // A reference names an element that is not on the photo
+	ProblemNotWire        = "not_wire"        // A reference names an element that is not a wire
+	ProblemEmptyTrace     = "empty_trace"     // A trace has no existing elements
+	ProblemMissingTrace   = "missing_trace"   // An element's _trace names a trace that does not exist
+)
+
+// Problem is one finding of Validate or Check.
+type Problem struct {
+	Kind        string `json:"kind"`
+	TraceID     string `json:"trace_id,omitempty"`
+	PhotoID     string `json:"photo_id,omitempty"`
+	ElementID   string `json:"element_id,omitempty"`
+	ElementType string `json:"element_type,omitempty"`
+}
+
+func (p Problem) String() string {
+	switch p.Kind {
+	case ProblemMissingPhoto:
+		return fmt.Sprintf("trace %s: photo %s does not exist", p.TraceID, p.PhotoID)
+	case ProblemMissingElement:
+		return fmt.Sprintf("trace %s: element %s does not exist on photo %s", p.TraceID, p.ElementID, p.PhotoID)
+	case ProblemNotWire:
+		return fmt.Sprintf("trace %s: element %s on photo %s is a %s, not a wire", p.TraceID, p.ElementID, p.PhotoID, p.ElementType)
+	case ProblemEmptyTrace:
+		return fmt.Sprintf("trace %s has no elements", p.TraceID)
+	case ProblemMissingTrace:
+		return fmt.Sprintf("element %s on photo %s belongs to trace %s, which does not exist", p.ElementID, p.PhotoID, p.TraceID)
+	}
+	return p.Kind
+}
+
+// Measurement is one element of a trace, located by the node or section its photo belongs to.
+type Measurement struct {
+	TraceID      string `json:"trace_id"`
+	PhotoID      string `json:"photo_id"`
+	ElementID    string `json:"element_id"`
+	ElementType  string `json:"element_type"`
+	NodeID       string `json:"node_id,omitempty"`
+	ConnectionID string `json:"connection_id,omitempty"` // Set with SectionID for midspan photos
+	SectionID    string `json:"section_id,omitempty"`
+	// Height is the manual height when set, otherwise the measured height. HasHeight is false when
+	// the element has neither.
+	Height    katapultpro.Height `json:"height_in"`
+	HasHeight bool               `json:"has_height"`
+}
+
+// element is what the index knows about one element of a photo.
+type element struct {
+	typ       string
+	traceID   string // From _trace or trace_id on the element or wire measurement
+	height    katapultpro.Height
+	hasHeight bool
+}
+
+// location is a node or section a photo is associated with.
+type location struct {
+	nodeID, connectionID, sectionID string
+}
+
+// Index joins a job's traces with the elements and wire measurements of its photos.
+type Index struct {
+	traces   map[string]*katapultpro.Trace
+	traceIDs []string
+	photos   map[string]bool
+	listed   map[string]bool               // Photos whose element listing is known
+	elements map[string]map[string]element // Photo ID to element ID
+	where    map[string]location           // Photo ID to its first node or section
+}
+
+// NewIndex builds an index from fetched records. elements maps photo IDs to their photo elements and
+// may be nil, in which case only the wire measurements in photofirst_data are known. nodes and
+// conns are used only to locate measurements and may be nil.
+func NewIndex(photos []katapultpro.Photo, elements map[string][]katapultpro.PhotoElement, traces []katapultpro.Trace,
+	nodes []katapultpro.Node, conns []katapultpro.Connection) *Index {
+	idx := &Index{
+		traces:   make(map[string]*katapultpro.Trace, len(traces)),
+		photos:   make(map[string]bool, len(photos)),
+		listed:   make(map[string]bool, len(elements)),
+		elements: make(map[string]map[string]element, len(photos)),
+		where:    map[string]location{},
+	}
+	for i := range traces {
+		idx.traces[traces[i].ID] = &traces[i]
+		idx.traceIDs = append(idx.traceIDs, traces[i].ID)
+	}
+	sort.Strings(idx.traceIDs)
+	for _, p := range photos {
+		idx.photos[p.ID] = true
+		els := map[string]element{}
+		if p.PhotofirstData != nil {
+			for id, w := range p.PhotofirstData.Wire {
+				els[id] = element{typ: string(katapultpro.ElementTypeWire), traceID: w.Trace, height: w.Height(), hasHeight: w.MeasuredHeight != 0}
+			}
+		}
+		idx.elements[p.ID] = els
+	}
+	for photoID, list := range elements {
+		idx.listed[photoID] = true
+		els := idx.elements[photoID]
+		if els == nil {
+			els = map[string]element{}
+			idx.elements[photoID] = els
+		}
+		for _, e := range list {
+			el := els[e.ID]
+			if t := e.Type(); t != "" {
+				el.typ = t.String()
+			}
+			if e.Trace != "" {
+				el.traceID = e.Trace
+			} else if e.TraceID != "" {
+				el.traceID = e.TraceID
+			}
+			if h, ok := e.Height(); ok {
+				el.height, el.hasHeight = h, true
+			}
+			els[e.ID] = el
+		}
+	}
+	sortedNodes := append([]katapultpro.Node(nil), nodes...)
+	sort.Slice(sortedNodes, func(i, j int) bool { return sortedNodes[i].ID < sortedNodes[j].ID })
+	for _, n := range sortedNodes {
+		for photoID := range n.Photos {
+			if _, ok := idx.where[photoID]; !ok {
+				idx.where[photoID] = location{nodeID: n.ID}
+			}
+		}
+	}
+	sortedConns := append([]katapultpro.Connection(nil), conns...)
+	sort.Slice(sortedConns, func(i, j int) bool { return sortedConns[i].ID < sortedConns[j].ID })
+	for _, c := range sortedConns {
+		secIDs := make([]string, 0, len(c.Sections))
+		for id := range c.Sections {
+			secIDs = append(secIDs, id)
+		}
+		sort.Strings(secIDs)
+		for _, id := range secIDs {
+			for photoID := range c.Sections[id].Photos {
+				if _, ok := idx.where[photoID]; !ok {
+					idx.where[photoID] = location{connectionID: c.ID, sectionID: id}
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// Load fetches the job's photos, traces, nodes, connections, and the element listing of every photo,
+// and builds an index.
+func Load(ctx context.Context, job *katapultpro.JobScope) (*Index, error) {
+	photos, err := job.Photos().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wiretrace: list photos: %w", err)
+	}
+	traces, err := job.Traces().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wiretrace: list traces: %w", err)
+	}
+	nodes, err := job.Nodes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wiretrace: list nodes: %w", err)
+	}
+	conns, err := job.Connections().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("wiretrace: list connections: %w", err)
+	}
+
+	ids := make([]string, len(photos))
+	for i, p := range photos {
+		ids[i] = p.ID
+	}
+	elements, err := job.Photos().ListAllElements(ctx, ids, DefaultConcurrency)
+	if err != nil {
+		return nil, fmt.Errorf("wiretrace: %w", err)
+	}
+	return NewIndex(photos, elements, traces, nodes, conns), nil
+}
+
+// Trace returns the trace with the given ID, or nil.
+func (idx *Index) Trace(id string) *katapultpro.Trace { return idx.traces[id] }
+
+// Validate checks that each reference names an existing wire element. traceID only labels the
+// problems and may be empty for a trace not yet created. An element on a photo whose element listing
+// was not loaded is accepted if photofirst_data has a wire measurement with its ID.
+func (idx *Index) Validate(traceID string, refs ...Ref) []Problem {
+	var out []Problem
+	for _, r := range refs {
+		p := Problem{TraceID: traceID, PhotoID: r.PhotoID, ElementID: r.ElementID}
+		el, ok := idx.elements[r.PhotoID][r.ElementID]
+		switch {
+		case !idx.photos[r.PhotoID] && !idx.listed[r.PhotoID]:
+			p.Kind = ProblemMissingPhoto
+		case !ok:
+			p.Kind = ProblemMissingElement
+		case el.typ != string(katapultpro.ElementTypeWire):
+			p.Kind, p.ElementType = ProblemNotWire, el.typ
+		default:
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// members returns the elements of a trace: those its items list and those whose _trace names it.
+func (idx *Index) members(traceID string) []Ref {
+	seen := map[Ref]bool{}
+	var out []Ref
+	if t := idx.traces[traceID]; t != nil {
+		for _, r := range Refs(*t) {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	for photoID, els := range idx.elements {
+		for id, el := range els {
+			if r := (Ref{photoID, id}); el.traceID == traceID && !seen[r] {
+				seen[r] = true
+				out = append(out, r)
+			}
+		}
+	}
+	sortRefs(out)
+	return out
+}
+
+// Measurements returns the existing elements of a trace across all photos, sorted by photo then
+// element. An element belongs to the trace if the trace's items list it or its _trace names the trace.
+func (idx *Index) Measurements(traceID string) []Measurement {
+	var out []Measurement
+	for _, r := range idx.members(traceID) {
+		el, ok := idx.elements[r.PhotoID][r.ElementID]
+		if !ok {
+			continue
+		}
+		loc := idx.where[r.PhotoID]
+		out = append(out, Measurement{
+			TraceID: traceID, PhotoID: r.PhotoID, ElementID: r.ElementID, ElementType: el.typ,
+			NodeID: loc.nodeID, ConnectionID: loc.connectionID, SectionID: loc.sectionID,
+			Height: el.height, HasHeight: el.hasHeight,
+		})
+	}
+	return out
+}
+
+// Check reports, for every trace, listed elements that do not exist or are not wires and traces
+// with no existing elements, then elements whose _trace names a trace that does not exist.
+// Elements on photos whose listing was not loaded are only checked against photofirst_data.
+func (idx *Index) Check() []Problem {
+	var out []Problem
+	for _, id := range idx.traceIDs {
+		refs := Refs(*idx.traces[id])
+		out = append(out, idx.Validate(id, refs...)...)
+		if len(idx.Measurements(id)) == 0 {
+			out = append(out, Problem{Kind: ProblemEmptyTrace, TraceID: id})
+		}
+	}
+	var orphans []Problem
+	for photoID, els := range idx.elements {
+		for elID, el := range els {
+			if el.traceID != "" && idx.traces[el.traceID] == nil {
+				orphans = append(orphans, Problem{Kind: ProblemMissingTrace, TraceID: el.traceID, PhotoID: photoID, ElementID: elID, ElementType: el.typ})
+			}
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool {
+		if orphans[i].PhotoID != orphans[j].PhotoID {
+			return orphans[i].PhotoID < orphans[j].PhotoID
+		}
+		return orphans[i].ElementID < orphans[j].ElementID
+	})
+	return append(out, orphans...)
+}
+
+// ValidationError is returned by Link when references fail Validate.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "wiretrace: " + e.Problems[0].String()
+	}
+	return fmt.Sprintf("wiretrace: %s (and %d more problems)", e.Problems[0], len(e.Problems)-1)
+}
+
+// Link validates refs against idx, creates a trace of the given type and attributes, and sets the
+// trace on each referenced element. It returns the trace as stored after the elements are linked.
+// Nothing is created when validation fails; an error while linking leaves the trace with the
+// elements linked so far.
+func Link(ctx context.Context, job *katapultpro.JobScope, idx *Index, traceType string, attrs map[string]interface{}, refs ...Ref) (*katapultpro.Trace, error) {
+	if problems := idx.Validate("", refs...); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	trace, err := job.Traces().Create(ctx, &katapultpro.CreateTraceRequest{TraceType: traceType, Attributes: attrs})
+	if err != nil {
+		return nil, fmt.Errorf("wiretrace: create trace: %w", err)
+	}
+	for _, r := range refs {
+		req := &katapultpro.UpdatePhotoElementRequest{TraceID: trace.ID}
+		opts := &katapultpro.UpdatePhotoElementOptions{OnlyIfExists: true}
+		if _, err := job.Photos().Photo(r.PhotoID).Elements().Update(ctx, r.ElementID, req, opts); err != nil {
+			return trace, fmt.Errorf("wiretrace: link element %s on photo %s to trace %s: %w", r.ElementID, r.PhotoID, trace.ID, err)
+		}
+	}
+	linked, err := job.Traces().Get(ctx, trace.ID)
+	if err != nil {
+		return trace, fmt.Errorf("wiretrace: get trace %s: %w", trace.ID, err)
+	}
+	return linked, nil
+}
@@ -0,0 +1,132 @@
+package wiretrace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/romer-pro/katapultpro-go-sdk/v3"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/katapulttest"
+	"github.com/romer-pro/katapultpro-go-sdk/v3/wiretrace"
+)
+
+func TestAssembleAndRefs(t *testing.T) {
+	base := katapultpro.Trace{TraceType: "cable", Items: map[string]katapultpro.TraceItem{"p0": {"wire": {"w0": true}}}}
+	got := wiretrace.Assemble(base, wiretrace.Ref{PhotoID: "p2", ElementID: "w2"}, wiretrace.Ref{PhotoID: "p1", ElementID: "w1"})
+	if !got.Items["p1"]["wire"]["w1"] || !got.Items["p0"]["wire"]["w0"] || got.TraceType != "cable" {
+		t.Errorf("items = %v", got.Items)
+	}
+	if _, ok := base.Items["p1"]; ok {
+		t.Error("base items modified")
+	}
+	refs := wiretrace.Refs(got)
+	if len(refs) != 3 || refs[0].PhotoID != "p0" || refs[2] != (wiretrace.Ref{PhotoID: "p2", ElementID: "w2"}) {
+		t.Errorf("refs = %v", refs)
+	}
+}
+
+func TestIndex_MeasurementsAndCheck(t *testing.T) {
+	photos := []katapultpro.Photo{
+		{ID: "p1", PhotofirstData: &katapultpro.PhotofirstData{Wire: map[string]katapultpro.WireMeasurement{
+			"w1": {MeasuredHeight: 300, Trace: "t1"},
+			"w9": {MeasuredHeight: 280, Trace: "gone"},
+		}}},
+		{ID: "p2"},
+	}
+	elements := map[string][]katapultpro.PhotoElement{
+		"p2": {
+			{ID: "w2", ElementType: "wire", ManualHeight: "22-0"},
+			{ID: "top", ElementType: "pole_top"},
+		},
+	}
+	traces := []katapultpro.Trace{
+		{ID: "t1", Items: map[string]katapultpro.TraceItem{"p2": {"wire": {"w2": true}}}},
+		{ID: "t2", Items: map[string]katapultpro.TraceItem{"p2": {"wire": {"top": true, "nope": true}}}},
+		{ID: "t3"},
+	}
+	nodes := []katapultpro.Node{{ID: "n1", Photos: katapultpro.PhotoAssociationMap{"p1": {Association: "main"}}}}
+	conns := []katapultpro.Connection{{ID: "c1", Sections: map[string]katapultpro.EmbeddedSection{
+		"s1": {Photos: katapultpro.PhotoAssociationMap{"p2": {Association: true}}},
+	}}}
+	idx := wiretrace.NewIndex(photos, elements, traces, nodes, conns)
+
+	ms := idx.Measurements("t1")
+	if len(ms) != 2 {
+		t.Fatalf("measurements = %+v", ms)
+	}
+	if m := ms[0]; m.PhotoID != "p1" || m.NodeID != "n1" || m.Height != katapultpro.Inches(300) || !m.HasHeight {
+		t.Errorf("node measurement = %+v", m)
+	}
+	if m := ms[1]; m.ElementID != "w2" || m.ConnectionID != "c1" || m.SectionID != "s1" || m.Height != katapultpro.Feet(22) {
+		t.Errorf("midspan measurement = %+v", m)
+	}
+
+	var kinds []string
+	for _, p := range idx.Check() {
+		kinds = append(kinds, p.Kind+":"+p.TraceID+"/"+p.ElementID)
+	}
+	want := []string{
+		"missing_element:t2/nope",
+		"not_wire:t2/top",
+		"empty_trace:t3/",
+		"missing_trace:gone/w9",
+	}
+	if len(kinds) != len(want) {
+		t.Fatalf("problems = %v", kinds)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("problem %d = %s, want %s", i, kinds[i], want[i])
+		}
+	}
+}
+
+func TestLink(t *testing.T) {
+	srv := katapulttest.NewServer()
+	defer srv.Close()
+	job := srv.AddJob(katapultpro.Job{Name: "Span"})
+	p1 := srv.AddPhoto(job.ID, katapultpro.Photo{})
+	p2 := srv.AddPhoto(job.ID, katapultpro.Photo{})
+	client := srv.Client()
+	ctx := context.Background()
+	scope := client.Job(job.ID)
+
+	var refs []wiretrace.Ref
+	for _, p := range []katapultpro.Photo{p1, p2} {
+		req := &katapultpro.CreatePhotoElementRequest{ElementType: "wire", ManualHeight: "21-6"}
+		el, err := scope.Photos().Photo(p.ID).Elements().Create(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs = append(refs, wiretrace.Ref{PhotoID: p.ID, ElementID: el.ID})
+	}
+
+	idx, err := wiretrace.Load(ctx, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = wiretrace.Link(ctx, scope, idx, "cable", nil, wiretrace.Ref{PhotoID: p1.ID, ElementID: "missing"})
+	var verr *wiretrace.ValidationError
+	if !errors.As(err, &verr) || verr.Problems[0].Kind != wiretrace.ProblemMissingElement {
+		t.Fatalf("err = %v", err)
+	}
+
+	trace, err := wiretrace.Link(ctx, scope, idx, "cable", map[string]interface{}{"company": "Comcast"}, refs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := wiretrace.Refs(*trace); len(got) != 2 || trace.Company != "Comcast" {
+		t.Errorf("trace = %+v", trace)
+	}
+
+	idx, err = wiretrace.Load(ctx, scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms := idx.Measurements(trace.ID); len(ms) != 2 || ms[0].Height != katapultpro.FeetInches(21, 6) {
+		t.Errorf("measurements = %+v", ms)
+	}
+	if problems := idx.Check(); len(problems) != 0 {
+		t.Errorf("problems = %v", problems)
+	}
+}